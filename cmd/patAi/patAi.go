@@ -1,26 +1,89 @@
 package main
 
 import (
+	"github.com/labstack/gommon/bytes"
+
+	"github.com/MyChaOS87/patAi/internal/api/admin"
 	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
 	"github.com/MyChaOS87/patAi/internal/api/server"
 	"github.com/MyChaOS87/patAi/internal/authorization"
 	"github.com/MyChaOS87/patAi/internal/cmd"
+	"github.com/MyChaOS87/patAi/internal/engines"
+	"github.com/MyChaOS87/patAi/internal/report"
+	"github.com/MyChaOS87/patAi/internal/similarity"
 	"github.com/MyChaOS87/patAi/internal/simulation"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/pkg/currency"
 	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
 )
 
 func main() {
 	ctx, cancel, cfg := cmd.Init()
 	defer cancel()
 
+	engineRegistry := engines.NewRegistry(simulation.NewEngine(&cfg.Simulation),
+		simulation.NewWeightedEngine(&cfg.Simulation))
 	simulation := simulation.NewInMemoryQueueAndQuotaServiceSimulation()
-	usecase := patents.NewValuationJobUseCase(simulation, simulation)
-	handler := patents.NewHandler(usecase)
-	patentsRouter := patents.NewPatentsRouter(authorization.NewMockProvider(), handler)
+	authorizationProvider := authorization.NewMockProvider()
+
+	usecase := patents.NewValuationJobUseCase(simulation, simulation,
+		patents.WithResultCache(simulation, &cfg.ResultCache),
+		patents.WithBatches(simulation, cfg.API.MaxBatchSize),
+		patents.WithEngines(engineRegistry),
+		patents.WithSimilarityIndex(similarity.NewIndex()),
+		patents.WithSearch(simulation),
+	)
+	openAPIDocument, err := openapi.LoadDocument(cfg.API.OpenAPIFile, struct{ ServerBaseURL string }{})
+	if err != nil {
+		log.Fatalf("cannot load OpenAPI document: %v", err)
+	}
+
+	patentSchema, err := openAPIDocument.Schema("PatentSubmission")
+	if err != nil {
+		log.Fatalf("cannot load patent schema: %v", err)
+	}
+
+	currencies, err := currency.Load(cfg.Currency.TableFile)
+	if err != nil {
+		log.Fatalf("cannot load currency table: %v", err)
+	}
+
+	reports, err := report.NewRenderer(&cfg.Report)
+	if err != nil {
+		log.Fatalf("cannot load report template: %v", err)
+	}
+
+	var maxPDFDecodedSize int64
+	if cfg.API.MaxPDFDecodedSize != "" {
+		if maxPDFDecodedSize, err = bytes.Parse(cfg.API.MaxPDFDecodedSize); err != nil {
+			log.Fatalf("cannot parse maximum decoded PDF size: %v", err)
+		}
+	}
+
+	handler := patents.NewHandler(usecase, patentSchema, currencies, reports,
+		patents.WithMaxPDFDecodedSize(maxPDFDecodedSize))
+	patentsRouter := patents.NewPatentsRouter(&cfg.API, authorizationProvider, handler)
+
+	portfolioUseCase := portfolios.NewPortfolioUseCase(simulation, simulation, simulation, currencies)
+	portfolioHandler := portfolios.NewHandler(portfolioUseCase)
+	portfoliosRouter := portfolios.NewPortfoliosRouter(authorizationProvider, portfolioHandler)
+
+	adminUseCase := admin.NewDeadLetterUseCase(simulation)
+	adminHandler := admin.NewHandler(adminUseCase)
+	adminRouter := admin.NewAdminRouter(authorizationProvider, adminHandler)
+
+	go worker.NewWorker(&cfg.Worker, simulation, engineRegistry).Run(ctx)
+	go worker.NewReaper(&cfg.Worker, simulation).Run(ctx)
 
 	srv := server.NewServer(
 		server.API(&cfg.API),
-		server.ChildRouters(patentsRouter),
+		server.ChildRouters(patentsRouter, portfoliosRouter, adminRouter),
+		server.OpenAPIDocument(openAPIDocument),
+		server.ProblemTypes(patents.ProblemTypes()...),
+		server.ProblemTypes(portfolios.ProblemTypes()...),
+		server.ProblemTypes(admin.ProblemTypes()...),
 	)
 	if err := srv.Run(ctx); err != nil {
 		log.Errorf("error running server: %v", err)
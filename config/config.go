@@ -17,8 +17,13 @@ const (
 
 // Config struct.
 type Config struct {
-	Logger loggerConfig.Logger
-	API    APIConfig
+	Logger      loggerConfig.Logger
+	API         APIConfig
+	Worker      WorkerConfig
+	ResultCache ResultCacheConfig
+	Currency    CurrencyConfig
+	Report      ReportConfig
+	Simulation  SimulationConfig
 }
 
 // APIConfig struct.
@@ -28,6 +33,20 @@ type APIConfig struct {
 	OpenAPISwaggerUI bool
 	ServerBaseURL    string
 	AllowedOrigins   []string
+	// IdempotencyKeyTTL is how long responses are kept for replay to requests with the same Idempotency-Key
+	IdempotencyKeyTTL time.Duration
+	// MaxBatchSize limits the number of patents per batch submission
+	MaxBatchSize int
+	// BodyLimit caps request bodies, e.g. "2M"
+	BodyLimit string
+	// RouteBodyLimits overrides BodyLimit per route, keyed by method and path as registered, e.g. "POST /api/v0/patents"
+	RouteBodyLimits map[string]string
+	// MaxPDFDecodedSize caps what the streams of an uploaded PDF may decompress to, e.g. "64M"
+	MaxPDFDecodedSize string
+	// ExposeInternalErrors reports the messages of internal errors in problem details, never enable it in production
+	ExposeInternalErrors bool
+	// ValidateResponses logs responses deviating from the OpenAPI document, meant for development as it records them
+	ValidateResponses bool
 }
 
 // ServerConfig struct.
@@ -38,6 +57,65 @@ type ServerConfig struct {
 	GracefulShutdownTimeout time.Duration
 }
 
+// WorkerConfig struct.
+type WorkerConfig struct {
+	Count             int
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+
+	// ExecutionTimeouts bounds a single evaluation per engine name, DefaultExecutionTimeout applies to all others
+	ExecutionTimeouts       map[string]time.Duration
+	DefaultExecutionTimeout time.Duration
+	HeartbeatInterval       time.Duration
+	// LeaseTimeout is the time without heartbeat after which a running job is considered abandoned by its worker
+	LeaseTimeout   time.Duration
+	ReaperInterval time.Duration
+	// MaxQueueTime is the time a job may wait for a worker before it is failed, zero disables the limit
+	MaxQueueTime time.Duration
+}
+
+// ResultCacheConfig struct.
+type ResultCacheConfig struct {
+	// TTL is how long a result is reused for identical content, zero disables the cache
+	TTL time.Duration
+	// ConsumeQuota charges cache hits against the quota like regular jobs
+	ConsumeQuota bool
+}
+
+// CurrencyConfig struct.
+type CurrencyConfig struct {
+	// TableFile holds the exchange rates valuations are converted with
+	TableFile string
+}
+
+// ReportConfig struct.
+type ReportConfig struct {
+	// TemplateFile is the html/template printable valuation reports are rendered with
+	TemplateFile string
+	// Branding applies to all tenants, Tenants override it field by field per identity ID
+	Branding BrandingConfig
+	Tenants  map[string]BrandingConfig
+}
+
+// BrandingConfig struct.
+type BrandingConfig struct {
+	Name string
+	// LogoFile is embedded into the reports as a data URI, so that they stay self-contained
+	LogoFile string
+	// PrimaryColor and AccentColor are CSS hex colors, e.g. #1f3a5f
+	PrimaryColor string
+	AccentColor  string
+	Footer       string
+}
+
+// SimulationConfig struct.
+type SimulationConfig struct {
+	EvaluationDuration time.Duration
+	FailureRate        float64
+}
+
 // LoadConfig loads config file from given path.
 func LoadConfig(filename string) (*viper.Viper, error) {
 	v := viper.New()
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+type JobErrorDTO struct {
+	Attempt    int       `json:"attempt"`
+	Message    string    `json:"message"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+type DeadLetterJobDTO struct {
+	ID       string        `json:"id"`
+	OwnerID  string        `json:"ownerId"`
+	Attempts int           `json:"attempts"`
+	Errors   []JobErrorDTO `json:"errors"`
+}
+
+func DeadLetterJobToDTO(job entities.EvaluationJob) DeadLetterJobDTO {
+	dto := DeadLetterJobDTO{
+		ID:       job.ID.String(),
+		OwnerID:  job.OwnerID,
+		Attempts: job.Attempts,
+		Errors:   make([]JobErrorDTO, 0, len(job.Errors)),
+	}
+
+	for _, e := range job.Errors {
+		dto.Errors = append(dto.Errors, JobErrorDTO{
+			Attempt:    e.Attempt,
+			Message:    e.Message,
+			OccurredAt: e.OccurredAt,
+		})
+	}
+
+	return dto
+}
+
+func DeadLetterJobsToDTO(jobs []entities.EvaluationJob) []DeadLetterJobDTO {
+	result := make([]DeadLetterJobDTO, 0, len(jobs))
+
+	for _, job := range jobs {
+		result = append(result, DeadLetterJobToDTO(job))
+	}
+
+	return result
+}
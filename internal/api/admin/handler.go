@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+type handler struct {
+	useCase DeadLetterUseCase
+}
+
+func NewHandler(useCase DeadLetterUseCase) Handler {
+	return &handler{
+		useCase: useCase,
+	}
+}
+
+var (
+	errGetIdentityFailed = errors.New("cannot get identity from context")
+	errMalformedJobID    = errors.New("malformed job id")
+)
+
+func getIdentityFromContext(c echo.Context) (authorization.Identity, error) {
+	identity, ok := c.Get(contextIdentityKey).(authorization.Identity)
+	if !ok {
+		return nil, errGetIdentityFailed
+	}
+
+	return identity, nil
+}
+
+func (h *handler) GetDeadLetterJobs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		jobs, err := h.useCase.GetDeadLetterJobs(identity)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, DeadLetterJobsToDTO(jobs)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetDeadLetterJobByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		job, err := h.useCase.GetDeadLetterJobByID(identity, id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, DeadLetterJobToDTO(job)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) RequeueDeadLetterJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		job, err := h.useCase.RequeueDeadLetterJob(identity, id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		log.Infof("Job %s requeued by operator %s", job.ID.String(), identity.GetID())
+
+		if err := c.JSON(http.StatusOK, DeadLetterJobToDTO(job)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// ProblemTypes maps the errors of the package to the problem types they are reported as.
+func ProblemTypes() []problem.Mapping {
+	return []problem.Mapping{
+		{Err: ErrForbidden, Type: problem.Type{
+			Status: http.StatusForbidden, Code: "operator-required", Title: "Operator privileges required",
+		}},
+		{Err: ErrDeadLetterJobNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "dead-letter-job-not-found", Title: "Dead-letter job not found",
+		}},
+		{Err: errMalformedJobID, Type: problem.Type{
+			Status: http.StatusBadRequest, Code: "malformed-job-id", Title: "Malformed job ID",
+		}},
+	}
+}
@@ -0,0 +1,20 @@
+//go:generate mockery --name DeadLetterService
+
+package admin
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var ErrDeadLetterJobNotFound = errors.New("dead-letter job not found")
+
+type DeadLetterService interface {
+	GetDeadLetterJobs() ([]entities.EvaluationJob, error)
+	GetDeadLetterJobByID(id uuid.UUID) (entities.EvaluationJob, error)
+	// RequeueDeadLetterJob removes the job from the dead-letter set and schedules it again with fresh attempts,
+	// returns an ErrDeadLetterJobNotFound error if the job is not dead-lettered
+	RequeueDeadLetterJob(id uuid.UUID) (entities.EvaluationJob, error)
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/MyChaOS87/patAi/internal/api/router"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/pkg/middleware"
+)
+
+const (
+	adminBaseURI       = "admin"
+	deadLettersURI     = "/dead-letters"
+	contextIdentityKey = "admin-identity"
+)
+
+var _ router.Router = &admin{}
+
+type Handler interface {
+	GetDeadLetterJobs() echo.HandlerFunc
+	GetDeadLetterJobByID() echo.HandlerFunc
+	RequeueDeadLetterJob() echo.HandlerFunc
+}
+
+type admin struct {
+	authorizationProvider middleware.AuthorizationProvider[authorization.Identity]
+	handler               Handler
+}
+
+func NewAdminRouter(
+	authorizationProvider middleware.AuthorizationProvider[authorization.Identity], handler Handler,
+) router.Router {
+	return &admin{
+		authorizationProvider: authorizationProvider,
+		handler:               handler,
+	}
+}
+
+func (a *admin) AddRoutes(baseGroup *echo.Group) {
+	adminGroup := baseGroup.Group(adminBaseURI)
+	adminGroup.Use(middleware.APIKey(a.authorizationProvider, contextIdentityKey))
+
+	adminGroup.GET(deadLettersURI, a.handler.GetDeadLetterJobs())
+	adminGroup.GET(deadLettersURI+"/:id", a.handler.GetDeadLetterJobByID())
+	adminGroup.POST(deadLettersURI+"/:id/requeue", a.handler.RequeueDeadLetterJob())
+}
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var (
+	ErrDeadLetterUseCase = errors.New("dead-letter use case error")
+	ErrForbidden         = errors.New("operator privileges required")
+)
+
+// DeadLetterUseCase gives operators access to jobs that exhausted their retries,
+// all methods return an ErrForbidden error for non-operator identities.
+type DeadLetterUseCase interface {
+	GetDeadLetterJobs(identity authorization.Identity) ([]entities.EvaluationJob, error)
+	GetDeadLetterJobByID(identity authorization.Identity, id uuid.UUID) (entities.EvaluationJob, error)
+	RequeueDeadLetterJob(identity authorization.Identity, id uuid.UUID) (entities.EvaluationJob, error)
+}
+
+type deadLetterUseCase struct {
+	deadLetterService DeadLetterService
+}
+
+func NewDeadLetterUseCase(deadLetterService DeadLetterService) DeadLetterUseCase {
+	return &deadLetterUseCase{
+		deadLetterService: deadLetterService,
+	}
+}
+
+func (d *deadLetterUseCase) GetDeadLetterJobs(identity authorization.Identity) ([]entities.EvaluationJob, error) {
+	if !identity.IsOperator() {
+		return nil, ErrForbidden
+	}
+
+	jobs, err := d.deadLetterService.GetDeadLetterJobs()
+	if err != nil {
+		return nil, errors.Wrap(err, ErrDeadLetterUseCase.Error())
+	}
+
+	return jobs, nil
+}
+
+func (d *deadLetterUseCase) GetDeadLetterJobByID(
+	identity authorization.Identity, id uuid.UUID,
+) (entities.EvaluationJob, error) {
+	if !identity.IsOperator() {
+		return entities.EvaluationJob{}, ErrForbidden
+	}
+
+	job, err := d.deadLetterService.GetDeadLetterJobByID(id)
+	if err != nil {
+		return entities.EvaluationJob{}, errors.Wrap(err, ErrDeadLetterUseCase.Error())
+	}
+
+	return job, nil
+}
+
+func (d *deadLetterUseCase) RequeueDeadLetterJob(
+	identity authorization.Identity, id uuid.UUID,
+) (entities.EvaluationJob, error) {
+	if !identity.IsOperator() {
+		return entities.EvaluationJob{}, ErrForbidden
+	}
+
+	job, err := d.deadLetterService.RequeueDeadLetterJob(id)
+	if err != nil {
+		return entities.EvaluationJob{}, errors.Wrap(err, ErrDeadLetterUseCase.Error())
+	}
+
+	return job, nil
+}
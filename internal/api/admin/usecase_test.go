@@ -0,0 +1,100 @@
+//nolint:funlen // Test functions are long, due to test cases
+package admin_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/api/admin"
+	"github.com/MyChaOS87/patAi/internal/api/admin/mocks"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+type identity struct {
+	id       string
+	operator bool
+}
+
+func (i *identity) GetID() string {
+	return i.id
+}
+
+func (i *identity) IsOperator() bool {
+	return i.operator
+}
+
+func (i *identity) GetPlan() authorization.Plan {
+	return authorization.PlanFree
+}
+
+func Test_deadLetterUseCase_RequeueDeadLetterJob(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+	requeuedJob := entities.EvaluationJob{
+		ID:                  id,
+		OwnerID:             "Alice",
+		EvaluationJobStatus: entities.EvaluationJobStatusPending,
+	}
+
+	testCases := []struct {
+		name            string
+		mockExpectation func(*mocks.DeadLetterService)
+		identity        authorization.Identity
+		want            entities.EvaluationJob
+		wantErr         error
+	}{
+		{
+			name: "operator requeues a dead-lettered job",
+			mockExpectation: func(m *mocks.DeadLetterService) {
+				m.On("RequeueDeadLetterJob", id).Return(requeuedJob, nil).Once()
+			},
+			identity: &identity{id: "Carol", operator: true},
+			want:     requeuedJob,
+			wantErr:  nil,
+		},
+		{
+			name: "operator cannot requeue a job that is not dead-lettered",
+			mockExpectation: func(m *mocks.DeadLetterService) {
+				m.On("RequeueDeadLetterJob", id).Return(entities.EvaluationJob{}, admin.ErrDeadLetterJobNotFound).Once()
+			},
+			identity: &identity{id: "Carol", operator: true},
+			want:     entities.EvaluationJob{},
+			wantErr:  admin.ErrDeadLetterJobNotFound,
+		},
+		{
+			name:            "Alice is no operator",
+			mockExpectation: func(*mocks.DeadLetterService) {},
+			identity:        &identity{id: "Alice"},
+			want:            entities.EvaluationJob{},
+			wantErr:         admin.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deadLetterService := new(mocks.DeadLetterService)
+
+			tc.mockExpectation(deadLetterService)
+
+			useCase := admin.NewDeadLetterUseCase(deadLetterService)
+
+			job, err := useCase.RequeueDeadLetterJob(tc.identity, id)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, job)
+
+			deadLetterService.AssertExpectations(t)
+		})
+	}
+}
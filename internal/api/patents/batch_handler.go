@@ -0,0 +1,152 @@
+package patents
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const mimeApplicationNDJSON = "application/x-ndjson"
+
+var (
+	errMalformedBatch          = errors.New("malformed batch")
+	errUnsupportedBatchContent = errors.New("unsupported batch content type, use application/json or " +
+		mimeApplicationNDJSON)
+)
+
+// decodeBatch reads a JSON array or newline delimited JSON objects depending on the content type.
+func decodeBatch(c echo.Context) ([]CreateJobRequest, error) {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return nil, errUnsupportedBatchContent
+	}
+
+	var items []BatchItemRequestDTO
+
+	decoder := json.NewDecoder(c.Request().Body)
+
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		if err := decoder.Decode(&items); err != nil {
+			return nil, problem.Detailed(errMalformedBatch, "%v", err)
+		}
+	case mimeApplicationNDJSON:
+		for {
+			var item BatchItemRequestDTO
+			if err := decoder.Decode(&item); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, problem.Detailed(errMalformedBatch, "%v", err)
+			}
+
+			items = append(items, item)
+		}
+	default:
+		return nil, errUnsupportedBatchContent
+	}
+
+	requests := make([]CreateJobRequest, 0, len(items))
+
+	for i, item := range items {
+		request, err := BatchItemRequestFromDTO(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "item %d", i)
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (h *handler) CreatePatentValuationBatch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		mode, err := BatchModeFromDTO(c.QueryParam("mode"))
+		if err != nil {
+			return problem.Invalid("mode", err)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		requests, err := decodeBatch(c)
+		if err != nil {
+			return err
+		}
+
+		batch, jobs, err := h.useCase.CreatePatentValuationBatch(identity, requests, mode)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto, err := h.batchInCurrency(targetCurrency, BatchToDTO(batch, jobs))
+		if err != nil {
+			return err
+		}
+
+		if err := c.JSON(http.StatusCreated, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPatentValuationBatchByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedBatchID)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		batch, jobs, err := h.useCase.GetPatentValuationBatchByIdentityAndID(identity, id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto, err := h.batchInCurrency(targetCurrency, BatchToDTO(batch, jobs))
+		if err != nil {
+			return err
+		}
+
+		if err := c.JSON(http.StatusOK, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) batchInCurrency(code string, dto BatchDTO) (BatchDTO, error) {
+	for _, item := range dto.Items {
+		if err := h.inCurrency(code, item.Job); err != nil {
+			return BatchDTO{}, err
+		}
+	}
+
+	return dto, nil
+}
@@ -0,0 +1,208 @@
+package patents
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+func (v *valuationJobUseCase) CreatePatentValuationBatch(
+	identity authorization.Identity, requests []CreateJobRequest, mode entities.BatchMode,
+) (entities.Batch, []entities.EvaluationJob, error) {
+	if v.batchService == nil {
+		return entities.Batch{}, nil, ErrBatchesDisabled
+	}
+
+	if len(requests) == 0 {
+		return entities.Batch{}, nil, ErrEmptyBatch
+	}
+
+	if len(requests) > v.maxBatchSize {
+		return entities.Batch{}, nil, problem.Detailed(ErrBatchTooLarge, "at most %d jobs", v.maxBatchSize)
+	}
+
+	batch := entities.Batch{
+		ID:        uuid.New(),
+		OwnerID:   identity.GetID(),
+		Mode:      mode,
+		CreatedAt: time.Now(),
+		Items:     make([]entities.BatchItem, len(requests)),
+	}
+
+	options := make([]entities.JobOptions, len(requests))
+	for i, request := range requests {
+		var err error
+
+		options[i], err = v.jobOptions(identity, request)
+		if err != nil && mode == entities.BatchModeAtomic {
+			return entities.Batch{}, nil, errors.Wrapf(err, "item %d", i)
+		} else if err != nil {
+			batch.Items[i].Rejection = problem.ClientMessage(err, err.Error())
+		}
+
+		options[i].BatchID = batch.ID
+	}
+
+	tokens, err := v.batchQuotaTokens(identity.GetID(), &batch, options)
+	if err != nil {
+		return entities.Batch{}, nil, err
+	}
+
+	jobs := make([]entities.EvaluationJob, 0, len(requests))
+
+	for i, request := range requests {
+		if batch.Items[i].Rejection != "" {
+			continue
+		}
+
+		job, err := v.enqueueJob(identity.GetID(), request.Content, options[i], tokens[i])
+		if err != nil {
+			// enqueueJob returned the token already
+			tokens[i] = uuid.Nil
+
+			if mode == entities.BatchModeAtomic {
+				v.rollBackBatch(batch, tokens)
+
+				return entities.Batch{}, nil, errors.Wrapf(err, "item %d", i)
+			}
+
+			batch.Items[i].Rejection = ErrCouldNotEnqueueJob.Error()
+
+			continue
+		}
+
+		batch.Items[i].JobID = job.ID
+		jobs = append(jobs, job)
+	}
+
+	if err := v.batchService.CreateBatch(batch); err != nil {
+		v.rollBackBatch(batch, tokens)
+
+		return entities.Batch{}, nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	for _, job := range jobs {
+		v.indexSimilarity(job)
+	}
+
+	return batch, jobs, nil
+}
+
+// rollBackBatch deletes the jobs enqueued for the batch and returns the quota tokens of its items, so that a batch
+// failing to be created leaves neither running nor charged jobs behind.
+func (v *valuationJobUseCase) rollBackBatch(batch entities.Batch, tokens []uuid.UUID) {
+	for i, item := range batch.Items {
+		if item.JobID != uuid.Nil {
+			if err := v.queueService.DeleteJob(item.JobID); err != nil {
+				log.Errorf("cannot roll back job %s of batch %s: %v", item.JobID.String(), batch.ID.String(), err)
+			}
+		}
+
+		if tokens[i] != uuid.Nil {
+			v.quotaService.ReturnQuotaToken(tokens[i])
+		}
+	}
+}
+
+// batchQuotaTokens gets the tokens for all accepted items needing quota, indexed like the items. Atomic batches get
+// all tokens or an ErrQuotaExceeded error, best-effort batches reject the items beyond the quota.
+func (v *valuationJobUseCase) batchQuotaTokens(
+	ownerID string, batch *entities.Batch, options []entities.JobOptions,
+) ([]uuid.UUID, error) {
+	tokens := make([]uuid.UUID, len(options))
+
+	var needQuota []int
+
+	for i := range options {
+		if batch.Items[i].Rejection == "" && v.consumesQuota(options[i]) {
+			needQuota = append(needQuota, i)
+		}
+	}
+
+	if len(needQuota) == 0 {
+		return tokens, nil
+	}
+
+	if batch.Mode == entities.BatchModeAtomic {
+		granted, err := v.quotaService.GetQuotaTokens(ownerID, len(needQuota))
+		if err != nil {
+			return nil, errors.Wrap(err, ErrCouldNotRetrieveQuota.Error())
+		}
+
+		for i, item := range needQuota {
+			tokens[item] = granted[i]
+		}
+
+		return tokens, nil
+	}
+
+	quotaExceeded := false
+	exceededRejection := ErrCouldNotRetrieveQuota.Error() + ": " + ErrQuotaExceeded.Error()
+
+	for _, item := range needQuota {
+		if quotaExceeded {
+			batch.Items[item].Rejection = exceededRejection
+
+			continue
+		}
+
+		token, err := v.quotaService.GetQuotaToken(ownerID)
+		if err == nil {
+			tokens[item] = token
+
+			continue
+		}
+
+		// rejections are shown to clients, the cause of other failures is only logged
+		quotaExceeded = errors.Is(err, ErrQuotaExceeded)
+		if quotaExceeded {
+			batch.Items[item].Rejection = exceededRejection
+		} else {
+			log.Warnf("cannot retrieve quota token for item %d of batch %s: %v", item, batch.ID.String(), err)
+
+			batch.Items[item].Rejection = ErrCouldNotRetrieveQuota.Error()
+		}
+	}
+
+	return tokens, nil
+}
+
+func (v *valuationJobUseCase) GetPatentValuationBatchByIdentityAndID(
+	identity authorization.Identity, id uuid.UUID,
+) (entities.Batch, []entities.EvaluationJob, error) {
+	if v.batchService == nil {
+		return entities.Batch{}, nil, ErrBatchesDisabled
+	}
+
+	batch, err := v.batchService.GetBatchByID(id)
+	if err != nil {
+		return entities.Batch{}, nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	if batch.OwnerID != identity.GetID() {
+		return entities.Batch{}, nil, ErrBatchNotFound
+	}
+
+	jobs := make([]entities.EvaluationJob, 0, len(batch.Items))
+
+	for _, item := range batch.Items {
+		if item.JobID == uuid.Nil {
+			continue
+		}
+
+		job, err := v.queueService.GetJobByID(item.JobID)
+		if err != nil {
+			return entities.Batch{}, nil, errors.Wrap(err, ErrValuationUseCase.Error())
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return batch, jobs, nil
+}
@@ -1,34 +1,262 @@
 package patents
 
-import "github.com/MyChaOS87/patAi/internal/entities"
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+)
 
 const (
 	dtoStatusPending  = "pending"
+	dtoStatusRunning  = "running"
 	dtoStatusFinished = "finished"
 	dtoStatusFailed   = "failed"
 	dtoStatusUnknown  = "unknown"
+
+	dtoPriorityLow    = "low"
+	dtoPriorityNormal = "normal"
+	dtoPriorityHigh   = "high"
 )
 
+var errUnknownPriority = errors.New("unknown priority, use one of low, normal, high")
+
 type JobDTO struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
-	Value  *int   `json:"value,omitempty"`
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Priority  string    `json:"priority"`
+	CreatedAt time.Time `json:"createdAt"`
+	BatchID   string    `json:"batchId,omitempty"`
+	// PatentKey links the job to a logical patent across amended submissions
+	PatentKey string `json:"patentKey,omitempty"`
+	// TechnicalField is empty for unclassified patents
+	TechnicalField string `json:"technicalField,omitempty"`
+	// Title and PublicationNumber are only known for structured submissions
+	Title             string `json:"title,omitempty"`
+	PublicationNumber string `json:"publicationNumber,omitempty"`
+	// Document describes the uploaded file, its content is served separately
+	Document *DocumentDTO `json:"document,omitempty"`
+	// Engine is the engine chosen at creation, or the one that valued the job once finished
+	Engine *EngineDTO `json:"engine,omitempty"`
+	// RevaluationOf is the job this one values again with another engine
+	RevaluationOf string `json:"revaluationOf,omitempty"`
+	// Labels, Tags and Note are the owner's metadata, they can be changed at any time
+	Labels map[string]string `json:"labels,omitempty"`
+	Tags   []string          `json:"tags,omitempty"`
+	Note   string            `json:"note,omitempty"`
+	// Value is the expected value in the currency of Valuation, superseded by it
+	Value *int `json:"value,omitempty"`
+	// Valuation is the monetary result, only present for finished jobs
+	Valuation *ValuationDTO `json:"valuation,omitempty"`
+	// Explanation justifies the value, only present for finished jobs
+	Explanation *ExplanationDTO `json:"explanation,omitempty"`
+	Cached      bool            `json:"cached,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	// Search explains why the job matched a full-text query, only present in search results
+	Search *SearchMatchDTO `json:"search,omitempty"`
+}
+
+type SearchMatchDTO struct {
+	Score    float64      `json:"score"`
+	Snippets []SnippetDTO `json:"snippets"`
+}
+
+type SnippetDTO struct {
+	Field string `json:"field"`
+	// Text is HTML with the matching terms enclosed in <mark> elements
+	Text string `json:"text"`
+}
+
+type ValuationDTO struct {
+	Currency string  `json:"currency"`
+	Low      float64 `json:"low"`
+	Expected float64 `json:"expected"`
+	High     float64 `json:"high"`
+	// Date is the day the patent was valued, YYYY-MM-DD
+	Date string `json:"date"`
+}
+
+// Convert converts the amounts to the given currency, valuations without currency are taken to be in the base
+// currency of the table.
+func (v *ValuationDTO) Convert(table *currency.Table, to string) error {
+	from := v.Currency
+	if from == "" {
+		from = table.Base()
+	}
+
+	for _, amount := range []*float64{&v.Low, &v.Expected, &v.High} {
+		converted, err := table.Convert(*amount, from, to)
+		if err != nil {
+			return errors.Wrap(err, "cannot convert valuation")
+		}
+
+		*amount = converted
+	}
+
+	v.Currency = to
+
+	return nil
+}
+
+// Convert converts all amounts of a finished job, the legacy value and the confidence bounds are rounded to whole
+// units.
+func (j *JobDTO) Convert(table *currency.Table, to string) error {
+	if j.Valuation == nil {
+		return nil
+	}
+
+	from := j.Valuation.Currency
+	if from == "" {
+		from = table.Base()
+	}
+
+	amounts := []*int{j.Value}
+	if j.Explanation != nil && j.Explanation.Confidence != nil {
+		amounts = append(amounts, &j.Explanation.Confidence.Lower, &j.Explanation.Confidence.Upper)
+		j.Explanation.Confidence.Currency = to
+	}
+
+	for _, amount := range amounts {
+		if amount == nil {
+			continue
+		}
+
+		converted, err := table.Convert(float64(*amount), from, to)
+		if err != nil {
+			return errors.Wrap(err, "cannot convert valuation")
+		}
+
+		*amount = int(math.Round(converted))
+	}
+
+	return j.Valuation.Convert(table, to)
+}
+
+type ExplanationDTO struct {
+	Engine  EngineDTO            `json:"engine"`
+	Factors []ValuationFactorDTO `json:"factors"`
+	// Confidence is absent if the engine gave no confidence interval
+	Confidence *ConfidenceIntervalDTO `json:"confidence,omitempty"`
+}
+
+type EngineDTO struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AvailableEngineDTO is an engine jobs can be created for.
+type AvailableEngineDTO struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Default marks the engine used if none is requested
+	Default bool `json:"default"`
+}
+
+type ValuationFactorDTO struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Weight      float64 `json:"weight"`
+	Score       float64 `json:"score"`
+}
+
+type ConfidenceIntervalDTO struct {
+	Level float64 `json:"level"`
+	// Currency is the ISO 4217 code of the bounds, the same as that of the valuation
+	Currency string `json:"currency"`
+	Lower    int    `json:"lower"`
+	Upper    int    `json:"upper"`
+}
+
+type DocumentDTO struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+// PriorityFromDTO parses a priority, an empty string is the normal priority.
+func PriorityFromDTO(priority string) (entities.JobPriority, error) {
+	switch priority {
+	case dtoPriorityLow:
+		return entities.JobPriorityLow, nil
+	case dtoPriorityNormal, "":
+		return entities.JobPriorityNormal, nil
+	case dtoPriorityHigh:
+		return entities.JobPriorityHigh, nil
+	default:
+		return 0, errUnknownPriority
+	}
+}
+
+func PriorityToDTO(priority entities.JobPriority) string {
+	switch priority {
+	case entities.JobPriorityLow:
+		return dtoPriorityLow
+	case entities.JobPriorityNormal:
+		return dtoPriorityNormal
+	case entities.JobPriorityHigh:
+		return dtoPriorityHigh
+	default:
+		return dtoStatusUnknown
+	}
 }
 
 func JobToDTO(job entities.EvaluationJob) JobDTO {
 	dto := JobDTO{
-		ID:    job.ID.String(),
-		Value: nil,
+		ID:             job.ID.String(),
+		Priority:       PriorityToDTO(job.Priority),
+		CreatedAt:      job.CreatedAt,
+		PatentKey:      job.PatentKey,
+		TechnicalField: job.TechnicalField,
+		Labels:         job.Metadata.Labels,
+		Tags:           job.Metadata.Tags,
+		Note:           job.Metadata.Note,
+		Value:          nil,
+	}
+
+	if job.BatchID != uuid.Nil {
+		dto.BatchID = job.BatchID.String()
+	}
+
+	if job.Engine != (entities.EngineInfo{}) {
+		dto.Engine = &EngineDTO{Name: job.Engine.Name, Version: job.Engine.Version}
+	}
+
+	if job.RevaluationOf != uuid.Nil {
+		dto.RevaluationOf = job.RevaluationOf.String()
+	}
+
+	if job.Patent != nil {
+		dto.Title = job.Patent.Title
+		dto.PublicationNumber = job.Patent.PublicationNumber
+	}
+
+	if job.Document != nil {
+		dto.Document = &DocumentDTO{
+			FileName:    job.Document.FileName,
+			ContentType: job.Document.ContentType,
+			Size:        len(job.Document.Content),
+		}
 	}
 
 	switch job.EvaluationJobStatus {
 	case entities.EvaluationJobStatusPending:
 		dto.Status = dtoStatusPending
+	case entities.EvaluationJobStatusRunning:
+		dto.Status = dtoStatusRunning
 	case entities.EvaluationJobStatusFinished:
 		dto.Status = dtoStatusFinished
 		dto.Value = &job.Value
+		dto.Valuation = ValuationToDTO(job)
+		dto.Explanation = ExplanationToDTO(job)
+		dto.Cached = job.Cached
 	case entities.EvaluationJobStatusFailed:
 		dto.Status = dtoStatusFailed
+		dto.Error = job.FailureReason
 	default:
 		dto.Status = dtoStatusUnknown
 	}
@@ -36,6 +264,218 @@ func JobToDTO(job entities.EvaluationJob) JobDTO {
 	return dto
 }
 
+// ValuationToDTO spans the confidence interval as range, without one the range is just the expected value.
+func ValuationToDTO(job entities.EvaluationJob) *ValuationDTO {
+	dto := &ValuationDTO{
+		Currency: job.Currency,
+		Low:      float64(job.Value),
+		Expected: float64(job.Value),
+		High:     float64(job.Value),
+		Date:     job.FinishedAt.Format(time.DateOnly),
+	}
+
+	if job.Confidence.Level > 0 {
+		dto.Low = float64(job.Confidence.Lower)
+		dto.High = float64(job.Confidence.Upper)
+	}
+
+	return dto
+}
+
+func ExplanationToDTO(job entities.EvaluationJob) *ExplanationDTO {
+	dto := &ExplanationDTO{
+		Engine:  EngineDTO{Name: job.Engine.Name, Version: job.Engine.Version},
+		Factors: make([]ValuationFactorDTO, 0, len(job.Factors)),
+	}
+
+	for _, factor := range job.Factors {
+		dto.Factors = append(dto.Factors, ValuationFactorDTO{
+			Name:        factor.Name,
+			Description: factor.Description,
+			Weight:      factor.Weight,
+			Score:       factor.Score,
+		})
+	}
+
+	if job.Confidence.Level > 0 {
+		dto.Confidence = &ConfidenceIntervalDTO{
+			Level:    job.Confidence.Level,
+			Currency: job.Currency,
+			Lower:    job.Confidence.Lower,
+			Upper:    job.Confidence.Upper,
+		}
+	}
+
+	return dto
+}
+
+func EnginesToDTO(engines []entities.EngineInfo, defaultEngine entities.EngineInfo) []AvailableEngineDTO {
+	result := make([]AvailableEngineDTO, 0, len(engines))
+
+	for _, engine := range engines {
+		result = append(result, AvailableEngineDTO{
+			Name:    engine.Name,
+			Version: engine.Version,
+			Default: engine == defaultEngine,
+		})
+	}
+
+	return result
+}
+
+type SimilarPatentDTO struct {
+	// Score is the similarity from 0 (nothing in common) to 1 (same terms)
+	Score float64 `json:"score"`
+	Job   JobDTO  `json:"job"`
+}
+
+func SimilarPatentsToDTO(patents []SimilarPatent) []SimilarPatentDTO {
+	result := make([]SimilarPatentDTO, 0, len(patents))
+
+	for _, patent := range patents {
+		result = append(result, SimilarPatentDTO{
+			Score: patent.Score,
+			Job:   JobToDTO(patent.Job),
+		})
+	}
+
+	return result
+}
+
+func SearchResultsToDTO(results []SearchResult) []JobDTO {
+	result := make([]JobDTO, 0, len(results))
+
+	for _, found := range results {
+		result = append(result, SearchResultToDTO(found))
+	}
+
+	return result
+}
+
+func SearchResultToDTO(found SearchResult) JobDTO {
+	dto := JobToDTO(found.Job)
+	dto.Search = &SearchMatchDTO{
+		Score:    found.Score,
+		Snippets: make([]SnippetDTO, 0, len(found.Snippets)),
+	}
+
+	for _, snippet := range found.Snippets {
+		dto.Search.Snippets = append(dto.Search.Snippets, SnippetDTO{Field: snippet.Field, Text: snippet.Text})
+	}
+
+	return dto
+}
+
+type HistoryDTO struct {
+	PatentKey string   `json:"patentKey"`
+	Jobs      []JobDTO `json:"jobs"`
+}
+
+type PatentDiffDTO struct {
+	PatentKey string `json:"patentKey"`
+	From      JobDTO `json:"from"`
+	To        JobDTO `json:"to"`
+	// Value is only present if both jobs are finished
+	Value  *ValueChangeDTO `json:"value,omitempty"`
+	Claims ClaimDiffDTO    `json:"claims"`
+}
+
+type ValueChangeDTO struct {
+	Currency string  `json:"currency"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Change   float64 `json:"change"`
+	// ChangePercent is relative to From, absent if From is zero
+	ChangePercent *float64 `json:"changePercent,omitempty"`
+}
+
+type ClaimDiffDTO struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Amended   int `json:"amended"`
+	Unchanged int `json:"unchanged"`
+	// Changes lists the added, removed and amended claims
+	Changes []ClaimChangeDTO `json:"changes"`
+}
+
+type ClaimChangeDTO struct {
+	Number int    `json:"number"`
+	Change string `json:"change"`
+	// Before is absent for added and After for removed claims
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+func HistoryToDTO(patentKey string, jobs []entities.EvaluationJob) HistoryDTO {
+	return HistoryDTO{
+		PatentKey: PatentKey(patentKey),
+		Jobs:      JobsToDTO(jobs),
+	}
+}
+
+// PatentDiffToDTO leaves the value change to ValueChangeToDTO, as it depends on the currency of the valuations.
+func PatentDiffToDTO(diff PatentDiff) PatentDiffDTO {
+	dto := PatentDiffDTO{
+		PatentKey: diff.PatentKey,
+		From:      JobToDTO(diff.From),
+		To:        JobToDTO(diff.To),
+		Claims:    ClaimDiffDTO{Changes: []ClaimChangeDTO{}},
+	}
+
+	for _, change := range diff.Claims {
+		switch change.Kind {
+		case claims.ChangeAdded:
+			dto.Claims.Added++
+		case claims.ChangeRemoved:
+			dto.Claims.Removed++
+		case claims.ChangeAmended:
+			dto.Claims.Amended++
+		case claims.ChangeUnchanged:
+			dto.Claims.Unchanged++
+
+			continue
+		}
+
+		changeDTO := ClaimChangeDTO{Number: change.Number, Change: string(change.Kind)}
+		if change.Before != nil {
+			changeDTO.Before = change.Before.Text
+		}
+
+		if change.After != nil {
+			changeDTO.After = change.After.Text
+		}
+
+		dto.Claims.Changes = append(dto.Claims.Changes, changeDTO)
+	}
+
+	return dto
+}
+
+// ValueChangeToDTO compares the expected values, which must be in the same currency, nil unless both are given.
+func ValueChangeToDTO(from, to *ValuationDTO) *ValueChangeDTO {
+	if from == nil || to == nil {
+		return nil
+	}
+
+	dto := &ValueChangeDTO{
+		Currency: to.Currency,
+		From:     from.Expected,
+		To:       to.Expected,
+		Change:   roundHundredths(to.Expected - from.Expected),
+	}
+
+	if from.Expected != 0 {
+		percent := roundHundredths((to.Expected - from.Expected) / from.Expected * 100) //nolint:gomnd // percent
+		dto.ChangePercent = &percent
+	}
+
+	return dto
+}
+
+func roundHundredths(value float64) float64 {
+	return math.Round(value*100) / 100 //nolint:gomnd // hundredths
+}
+
 func JobsToDTO(jobs []entities.EvaluationJob) []JobDTO {
 	result := make([]JobDTO, 0, len(jobs))
 
@@ -45,3 +485,289 @@ func JobsToDTO(jobs []entities.EvaluationJob) []JobDTO {
 
 	return result
 }
+
+const (
+	dtoBatchModeAtomic     = "atomic"
+	dtoBatchModeBestEffort = "best-effort"
+)
+
+var errUnknownBatchMode = errors.New("unknown batch mode, use one of atomic, best-effort")
+
+// BatchItemRequestDTO is a single patent of a batch submission.
+type BatchItemRequestDTO struct {
+	Content  string `json:"content"`
+	Priority string `json:"priority,omitempty"`
+	Fresh    bool   `json:"fresh,omitempty"`
+	// TechnicalField optionally classifies the patent
+	TechnicalField string `json:"technicalField,omitempty"`
+	// Engine and EngineVersion optionally select the engine
+	Engine        string `json:"engine,omitempty"`
+	EngineVersion string `json:"engineVersion,omitempty"`
+	// PatentKey optionally links the job to a logical patent
+	PatentKey string `json:"patentKey,omitempty"`
+	// Labels, Tags and Note optionally organise the job
+	Labels map[string]string `json:"labels,omitempty"`
+	Tags   []string          `json:"tags,omitempty"`
+	Note   string            `json:"note,omitempty"`
+}
+
+type BatchDTO struct {
+	ID        string           `json:"id"`
+	Mode      string           `json:"mode"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Progress  BatchProgressDTO `json:"progress"`
+	Items     []BatchItemDTO   `json:"items"`
+}
+
+type BatchProgressDTO struct {
+	Total    int `json:"total"`
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	Pending  int `json:"pending"`
+	Running  int `json:"running"`
+	Finished int `json:"finished"`
+	Failed   int `json:"failed"`
+	// Done reports whether all accepted jobs are finished or failed
+	Done bool `json:"done"`
+}
+
+type BatchItemDTO struct {
+	Index int     `json:"index"`
+	Job   *JobDTO `json:"job,omitempty"`
+	Error string  `json:"error,omitempty"`
+}
+
+func BatchItemRequestFromDTO(dto BatchItemRequestDTO) (CreateJobRequest, error) {
+	priority, err := PriorityFromDTO(dto.Priority)
+	if err != nil {
+		return CreateJobRequest{}, err
+	}
+
+	return CreateJobRequest{
+		Content:        dto.Content,
+		Priority:       priority,
+		Fresh:          dto.Fresh,
+		TechnicalField: dto.TechnicalField,
+		Engine:         dto.Engine,
+		EngineVersion:  dto.EngineVersion,
+		PatentKey:      dto.PatentKey,
+		Metadata: entities.JobMetadata{
+			Labels: dto.Labels,
+			Tags:   dto.Tags,
+			Note:   dto.Note,
+		},
+	}, nil
+}
+
+// JobMetadataPatchDTO changes the metadata of a job, absent fields are left as they are.
+type JobMetadataPatchDTO struct {
+	// Labels are merged into the existing labels, labels set to null are removed
+	Labels map[string]*string `json:"labels,omitempty"`
+	// Tags replace the existing tags
+	Tags *[]string `json:"tags,omitempty"`
+	// Note replaces the existing note, an empty note removes it
+	Note *string `json:"note,omitempty"`
+}
+
+func MetadataPatchFromDTO(dto JobMetadataPatchDTO) MetadataPatch {
+	return MetadataPatch{
+		Labels: dto.Labels,
+		Tags:   dto.Tags,
+		Note:   dto.Note,
+	}
+}
+
+// BatchModeFromDTO parses a batch mode, an empty string is the atomic mode.
+func BatchModeFromDTO(mode string) (entities.BatchMode, error) {
+	switch mode {
+	case dtoBatchModeAtomic, "":
+		return entities.BatchModeAtomic, nil
+	case dtoBatchModeBestEffort:
+		return entities.BatchModeBestEffort, nil
+	default:
+		return 0, errUnknownBatchMode
+	}
+}
+
+func BatchModeToDTO(mode entities.BatchMode) string {
+	switch mode {
+	case entities.BatchModeAtomic:
+		return dtoBatchModeAtomic
+	case entities.BatchModeBestEffort:
+		return dtoBatchModeBestEffort
+	default:
+		return dtoStatusUnknown
+	}
+}
+
+// BatchToDTO lists the batch items with their jobs and sums up the progress of the jobs.
+func BatchToDTO(batch entities.Batch, jobs []entities.EvaluationJob) BatchDTO {
+	jobsByID := make(map[uuid.UUID]entities.EvaluationJob, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+
+	dto := BatchDTO{
+		ID:        batch.ID.String(),
+		Mode:      BatchModeToDTO(batch.Mode),
+		CreatedAt: batch.CreatedAt,
+		Progress:  BatchProgressDTO{Total: len(batch.Items)},
+		Items:     make([]BatchItemDTO, 0, len(batch.Items)),
+	}
+
+	for i, item := range batch.Items {
+		itemDTO := BatchItemDTO{Index: i, Error: item.Rejection}
+
+		if job, ok := jobsByID[item.JobID]; ok {
+			jobDTO := JobToDTO(job)
+			itemDTO.Job = &jobDTO
+			dto.Progress.Accepted++
+
+			switch job.EvaluationJobStatus {
+			case entities.EvaluationJobStatusPending:
+				dto.Progress.Pending++
+			case entities.EvaluationJobStatusRunning:
+				dto.Progress.Running++
+			case entities.EvaluationJobStatusFinished:
+				dto.Progress.Finished++
+			case entities.EvaluationJobStatusFailed:
+				dto.Progress.Failed++
+			}
+		} else {
+			dto.Progress.Rejected++
+		}
+
+		dto.Items = append(dto.Items, itemDTO)
+	}
+
+	dto.Progress.Done = dto.Progress.Finished+dto.Progress.Failed == dto.Progress.Accepted
+
+	return dto
+}
+
+// PatentSubmissionDTO is the structured patent submission, see the PatentSubmission schema.
+type PatentSubmissionDTO struct {
+	Title             string   `json:"title"`
+	Abstract          string   `json:"abstract"`
+	Claims            []string `json:"claims"`
+	Description       string   `json:"description"`
+	PublicationNumber string   `json:"publicationNumber"`
+	// PriorityDate is formatted YYYY-MM-DD
+	PriorityDate    string   `json:"priorityDate"`
+	CPCClasses      []string `json:"cpcClasses"`
+	IPCClasses      []string `json:"ipcClasses"`
+	CitedReferences []string `json:"citedReferences"`
+	Jurisdiction    string   `json:"jurisdiction"`
+}
+
+var errMalformedPriorityDate = errors.New("malformed priority date, use YYYY-MM-DD")
+
+func PatentFromDTO(dto PatentSubmissionDTO) (entities.Patent, error) {
+	patent := entities.Patent{
+		Title:             dto.Title,
+		Abstract:          dto.Abstract,
+		Claims:            make([]entities.Claim, 0, len(dto.Claims)),
+		Description:       dto.Description,
+		PublicationNumber: dto.PublicationNumber,
+		CPCClasses:        dto.CPCClasses,
+		IPCClasses:        dto.IPCClasses,
+		CitedReferences:   dto.CitedReferences,
+		Jurisdiction:      dto.Jurisdiction,
+	}
+
+	for i, claim := range dto.Claims {
+		patent.Claims = append(patent.Claims, entities.Claim{Number: i + 1, Text: claim})
+	}
+
+	if dto.PriorityDate != "" {
+		priorityDate, err := time.Parse(time.DateOnly, dto.PriorityDate)
+		if err != nil {
+			return entities.Patent{}, errMalformedPriorityDate
+		}
+
+		patent.PriorityDate = priorityDate
+	}
+
+	return patent, nil
+}
+
+type ClaimAnalysisDTO struct {
+	// Claims holds the independent claims, dependent claims are nested below the first claim they refer to
+	Claims  []ClaimNodeDTO  `json:"claims"`
+	Metrics ClaimMetricsDTO `json:"metrics"`
+}
+
+type ClaimNodeDTO struct {
+	Number      int            `json:"number"`
+	Text        string         `json:"text"`
+	Independent bool           `json:"independent"`
+	Category    string         `json:"category"`
+	DependsOn   []int          `json:"dependsOn,omitempty"`
+	Children    []ClaimNodeDTO `json:"children,omitempty"`
+}
+
+type ClaimMetricsDTO struct {
+	Claims                        int `json:"claims"`
+	IndependentClaims             int `json:"independentClaims"`
+	DependentClaims               int `json:"dependentClaims"`
+	Breadth                       int `json:"breadth"`
+	Depth                         int `json:"depth"`
+	ShortestIndependentClaimWords int `json:"shortestIndependentClaimWords"`
+	Categories                    int `json:"categories"`
+}
+
+func ClaimCategoryToDTO(category claims.Category) string {
+	switch category {
+	case claims.CategoryMethod:
+		return "method"
+	case claims.CategoryApparatus:
+		return "apparatus"
+	case claims.CategoryComposition:
+		return "composition"
+	case claims.CategoryUse:
+		return "use"
+	case claims.CategoryComputerProgram:
+		return "computer-program"
+	default:
+		return "other"
+	}
+}
+
+func ClaimAnalysisToDTO(analysis claims.Analysis) ClaimAnalysisDTO {
+	metrics := analysis.Metrics
+
+	return ClaimAnalysisDTO{
+		Claims: claimNodesToDTO(analysis.Roots),
+		Metrics: ClaimMetricsDTO{
+			Claims:                        metrics.Claims,
+			IndependentClaims:             metrics.IndependentClaims,
+			DependentClaims:               metrics.DependentClaims,
+			Breadth:                       metrics.Breadth,
+			Depth:                         metrics.Depth,
+			ShortestIndependentClaimWords: metrics.ShortestIndependentClaimWords,
+			Categories:                    metrics.Categories,
+		},
+	}
+}
+
+func claimNodesToDTO(nodes []*claims.Node) []ClaimNodeDTO {
+	result := make([]ClaimNodeDTO, 0, len(nodes))
+
+	for _, node := range nodes {
+		dto := ClaimNodeDTO{
+			Number:      node.Claim.Number,
+			Text:        node.Claim.Text,
+			Independent: node.Independent(),
+			Category:    ClaimCategoryToDTO(node.Category),
+			DependsOn:   node.Claim.DependsOn,
+		}
+
+		if len(node.Children) > 0 {
+			dto.Children = claimNodesToDTO(node.Children)
+		}
+
+		result = append(result, dto)
+	}
+
+	return result
+}
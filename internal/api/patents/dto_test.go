@@ -0,0 +1,286 @@
+//nolint:funlen // Test functions are long, due to test cases
+package patents_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/report"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+)
+
+func TestJobDTO_Convert(t *testing.T) {
+	t.Parallel()
+
+	table, err := currency.NewTable("EUR", map[string]float64{"USD": 1.08, "JPY": 162.5})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	finished := func(currency string, confidence entities.ConfidenceInterval) patents.JobDTO {
+		return patents.JobToDTO(entities.EvaluationJob{
+			EvaluationJobStatus: entities.EvaluationJobStatusFinished,
+			Value:               1000,
+			Currency:            currency,
+			Confidence:          confidence,
+			FinishedAt:          time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	value := func(v int) *int { return &v }
+	interval := entities.ConfidenceInterval{Level: 0.9, Lower: 800, Upper: 1250}
+
+	testCases := []struct {
+		name           string
+		job            patents.JobDTO
+		to             string
+		wantValue      *int
+		wantValuation  *patents.ValuationDTO
+		wantConfidence *patents.ConfidenceIntervalDTO
+		wantErr        error
+	}{
+		{
+			name:      "all amounts",
+			job:       finished("USD", interval),
+			to:        "EUR",
+			wantValue: value(926),
+			wantValuation: &patents.ValuationDTO{
+				Currency: "EUR", Low: 800 / 1.08, Expected: 1000 / 1.08, High: 1250 / 1.08, Date: "2024-01-31",
+			},
+			wantConfidence: &patents.ConfidenceIntervalDTO{Level: 0.9, Currency: "EUR", Lower: 741, Upper: 1157},
+		},
+		{
+			name:      "between non-base currencies",
+			job:       finished("USD", interval),
+			to:        "JPY",
+			wantValue: value(150463),
+			wantValuation: &patents.ValuationDTO{
+				Currency: "JPY", Low: 800 / 1.08 * 162.5, Expected: 1000 / 1.08 * 162.5, High: 1250 / 1.08 * 162.5,
+				Date: "2024-01-31",
+			},
+			wantConfidence: &patents.ConfidenceIntervalDTO{Level: 0.9, Currency: "JPY", Lower: 120370, Upper: 188079},
+		},
+		{
+			name:      "without currency in the base currency",
+			job:       finished("", entities.ConfidenceInterval{}),
+			to:        "USD",
+			wantValue: value(1080),
+			wantValuation: &patents.ValuationDTO{
+				Currency: "USD", Low: 1080, Expected: 1080, High: 1080, Date: "2024-01-31",
+			},
+		},
+		{
+			name: "unfinished job",
+			job:  patents.JobToDTO(entities.EvaluationJob{EvaluationJobStatus: entities.EvaluationJobStatusPending}),
+			to:   "USD",
+		},
+		{
+			name:    "unknown currency",
+			job:     finished("USD", interval),
+			to:      "XYZ",
+			wantErr: currency.ErrUnknownCurrency,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := tc.job
+
+			err := job.Convert(table, tc.to)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			assert.Equal(t, tc.wantValue, job.Value)
+
+			if tc.wantValuation == nil {
+				assert.Nil(t, job.Valuation)
+			} else if assert.NotNil(t, job.Valuation) {
+				assert.Equal(t, tc.wantValuation.Currency, job.Valuation.Currency)
+				assert.InDelta(t, tc.wantValuation.Low, job.Valuation.Low, 0.01)
+				assert.InDelta(t, tc.wantValuation.Expected, job.Valuation.Expected, 0.01)
+				assert.InDelta(t, tc.wantValuation.High, job.Valuation.High, 0.01)
+				assert.Equal(t, tc.wantValuation.Date, job.Valuation.Date)
+			}
+
+			if job.Explanation != nil {
+				assert.Equal(t, tc.wantConfidence, job.Explanation.Confidence)
+			}
+		})
+	}
+}
+
+func TestExplanationToDTO(t *testing.T) {
+	t.Parallel()
+
+	engine := entities.EngineInfo{Name: "simulation", Version: "2.0.0"}
+	factors := []entities.ValuationFactor{
+		{Name: "claimBreadth", Description: "Number of independent claims", Weight: 0.6, Score: 50},
+		{Name: "citations", Weight: 0.4, Score: 30},
+	}
+	finishedAt := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		job             entities.EvaluationJob
+		wantExplanation *patents.ExplanationDTO
+		wantValuation   *patents.ValuationDTO
+	}{
+		{
+			name: "factors and confidence interval",
+			job: entities.EvaluationJob{
+				Engine:     engine,
+				Value:      42000,
+				Currency:   "EUR",
+				Factors:    factors,
+				Confidence: entities.ConfidenceInterval{Level: 0.9, Lower: 37800, Upper: 46200},
+				FinishedAt: finishedAt,
+			},
+			wantExplanation: &patents.ExplanationDTO{
+				Engine: patents.EngineDTO{Name: "simulation", Version: "2.0.0"},
+				Factors: []patents.ValuationFactorDTO{
+					{Name: "claimBreadth", Description: "Number of independent claims", Weight: 0.6, Score: 50},
+					{Name: "citations", Weight: 0.4, Score: 30},
+				},
+				Confidence: &patents.ConfidenceIntervalDTO{Level: 0.9, Currency: "EUR", Lower: 37800, Upper: 46200},
+			},
+			wantValuation: &patents.ValuationDTO{
+				Currency: "EUR", Low: 37800, Expected: 42000, High: 46200, Date: "2024-01-31",
+			},
+		},
+		{
+			name: "without confidence interval the range is the value",
+			job: entities.EvaluationJob{
+				Engine:     engine,
+				Value:      42000,
+				Currency:   "USD",
+				Factors:    factors[:1],
+				FinishedAt: finishedAt,
+			},
+			wantExplanation: &patents.ExplanationDTO{
+				Engine: patents.EngineDTO{Name: "simulation", Version: "2.0.0"},
+				Factors: []patents.ValuationFactorDTO{
+					{Name: "claimBreadth", Description: "Number of independent claims", Weight: 0.6, Score: 50},
+				},
+			},
+			wantValuation: &patents.ValuationDTO{
+				Currency: "USD", Low: 42000, Expected: 42000, High: 42000, Date: "2024-01-31",
+			},
+		},
+		{
+			name: "engine without explanation",
+			job:  entities.EvaluationJob{Engine: engine, Value: 1000, Currency: "EUR", FinishedAt: finishedAt},
+			wantExplanation: &patents.ExplanationDTO{
+				Engine:  patents.EngineDTO{Name: "simulation", Version: "2.0.0"},
+				Factors: []patents.ValuationFactorDTO{},
+			},
+			wantValuation: &patents.ValuationDTO{
+				Currency: "EUR", Low: 1000, Expected: 1000, High: 1000, Date: "2024-01-31",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.wantExplanation, patents.ExplanationToDTO(tc.job))
+			assert.Equal(t, tc.wantValuation, patents.ValuationToDTO(tc.job))
+
+			tc.job.EvaluationJobStatus = entities.EvaluationJobStatusFinished
+			dto := patents.JobToDTO(tc.job)
+			assert.Equal(t, tc.wantExplanation, dto.Explanation)
+			assert.Equal(t, tc.wantValuation, dto.Valuation)
+		})
+	}
+}
+
+func TestJobToReport(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	createdAt := time.Date(2024, 1, 30, 12, 0, 0, 0, time.UTC)
+	finishedAt := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	engine := entities.EngineInfo{Name: "simulation", Version: "2.0.0"}
+
+	testCases := []struct {
+		name string
+		job  entities.EvaluationJob
+		want report.Job
+	}{
+		{
+			name: "finished upload",
+			job: entities.EvaluationJob{
+				ID:                  id,
+				EvaluationJobStatus: entities.EvaluationJobStatusFinished,
+				CreatedAt:           createdAt,
+				PatentKey:           "EP1234567",
+				Document:            &entities.Document{FileName: "widget.pdf", ContentType: "application/pdf"},
+				Metadata:            entities.JobMetadata{Tags: []string{"urgent"}, Note: "check"},
+				Engine:              engine,
+				Value:               42000,
+				Currency:            "EUR",
+				Factors:             []entities.ValuationFactor{{Name: "citations", Weight: 0.4, Score: 30}},
+				Confidence:          entities.ConfidenceInterval{Level: 0.9, Lower: 37800, Upper: 46200},
+				FinishedAt:          finishedAt,
+				Cached:              true,
+			},
+			want: report.Job{
+				ID:        id.String(),
+				Status:    "finished",
+				CreatedAt: createdAt,
+				PatentKey: "EP1234567",
+				FileName:  "widget.pdf",
+				Tags:      []string{"urgent"},
+				Note:      "check",
+				Valuation: &report.Valuation{
+					Currency: "EUR", Low: 37800, Expected: 42000, High: 46200, Date: "2024-01-31",
+				},
+				Explanation: &report.Explanation{
+					Engine:          engine,
+					Factors:         []report.ValuationFactor{{Name: "citations", Weight: 0.4, Score: 30}},
+					ConfidenceLevel: 0.9,
+				},
+				Cached: true,
+			},
+		},
+		{
+			name: "failed structured patent",
+			job: entities.EvaluationJob{
+				ID:                  id,
+				EvaluationJobStatus: entities.EvaluationJobStatusFailed,
+				CreatedAt:           createdAt,
+				Patent:              &entities.Patent{Title: "Widget", PublicationNumber: "EP1234567A1"},
+				FailureReason:       "engine unavailable",
+			},
+			want: report.Job{
+				ID:                id.String(),
+				Status:            "failed",
+				CreatedAt:         createdAt,
+				Title:             "Widget",
+				PublicationNumber: "EP1234567A1",
+				Error:             "engine unavailable",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, patents.JobToReport(patents.JobToDTO(tc.job)))
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package patents
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/xlsx"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+
+	mimeTextCSV = "text/csv"
+)
+
+var (
+	errUnknownExportFormat = errors.New("unknown export format, use one of csv, ndjson, xlsx")
+	errNotAcceptable       = errors.New("none of the accepted media types can be exported, use one of " +
+		mimeTextCSV + ", " + mimeApplicationNDJSON + ", " + xlsx.MIMEType)
+	errUnknownExportColumn = errors.New("unknown export column")
+)
+
+// ExportMediaTypes are the media types of the export formats.
+//
+//nolint:gochecknoglobals // lookup table
+var ExportMediaTypes = map[string]string{
+	ExportFormatCSV:    mimeTextCSV,
+	ExportFormatNDJSON: mimeApplicationNDJSON,
+	ExportFormatXLSX:   xlsx.MIMEType,
+}
+
+// ExportFormatFromDTO selects the export format by the format query parameter, falling back to the most preferred
+// media type of the Accept header; CSV is exported if neither asks for a specific format.
+func ExportFormatFromDTO(format string, accept string) (string, error) {
+	if format != "" {
+		if _, ok := ExportMediaTypes[format]; !ok {
+			return "", errUnknownExportFormat
+		}
+
+		return format, nil
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return ExportFormatCSV, nil
+	}
+
+	for _, mediaType := range acceptedMediaTypes(accept) {
+		switch mediaType {
+		case mimeTextCSV, "text/*", "*/*":
+			return ExportFormatCSV, nil
+		case mimeApplicationNDJSON:
+			return ExportFormatNDJSON, nil
+		case xlsx.MIMEType:
+			return ExportFormatXLSX, nil
+		}
+	}
+
+	return "", errNotAcceptable
+}
+
+// acceptedMediaTypes returns the media types of an Accept header by descending quality, dropping those with quality
+// zero.
+func acceptedMediaTypes(accept string) []string {
+	type accepted struct {
+		mediaType string
+		quality   float64
+	}
+
+	var types []accepted
+
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			types = append(types, accepted{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(types, func(i, j int) bool { return types[i].quality > types[j].quality })
+
+	result := make([]string, 0, len(types))
+	for _, t := range types {
+		result = append(result, t.mediaType)
+	}
+
+	return result
+}
+
+// exportColumn extracts a value from a job, nil for values the job does not have.
+type exportColumn func(JobDTO) any
+
+//nolint:gochecknoglobals // lookup table
+var exportColumns = map[string]exportColumn{
+	"id":                func(j JobDTO) any { return j.ID },
+	"status":            func(j JobDTO) any { return j.Status },
+	"priority":          func(j JobDTO) any { return j.Priority },
+	"createdAt":         func(j JobDTO) any { return j.CreatedAt },
+	"batchId":           func(j JobDTO) any { return optional(j.BatchID) },
+	"patentKey":         func(j JobDTO) any { return optional(j.PatentKey) },
+	"publicationNumber": func(j JobDTO) any { return optional(j.PublicationNumber) },
+	"title":             func(j JobDTO) any { return optional(j.Title) },
+	"technicalField":    func(j JobDTO) any { return optional(j.TechnicalField) },
+	"engine": func(j JobDTO) any {
+		if j.Engine == nil {
+			return nil
+		}
+
+		return j.Engine.Name
+	},
+	"engineVersion": func(j JobDTO) any {
+		if j.Engine == nil {
+			return nil
+		}
+
+		return j.Engine.Version
+	},
+	"revaluationOf": func(j JobDTO) any { return optional(j.RevaluationOf) },
+	"currency":      valuationColumn(func(v *ValuationDTO) any { return v.Currency }),
+	"low":           valuationColumn(func(v *ValuationDTO) any { return v.Low }),
+	"expected":      valuationColumn(func(v *ValuationDTO) any { return v.Expected }),
+	"high":          valuationColumn(func(v *ValuationDTO) any { return v.High }),
+	"valuationDate": valuationColumn(func(v *ValuationDTO) any { return v.Date }),
+	"cached":        func(j JobDTO) any { return j.Cached },
+	"labels": func(j JobDTO) any {
+		if len(j.Labels) == 0 {
+			return nil
+		}
+
+		return j.Labels
+	},
+	"tags": func(j JobDTO) any {
+		if len(j.Tags) == 0 {
+			return nil
+		}
+
+		return j.Tags
+	},
+	"note":  func(j JobDTO) any { return optional(j.Note) },
+	"error": func(j JobDTO) any { return optional(j.Error) },
+	"score": func(j JobDTO) any {
+		if j.Search == nil {
+			return nil
+		}
+
+		return j.Search.Score
+	},
+}
+
+// DefaultExportColumns are exported unless columns are selected.
+//
+//nolint:gochecknoglobals // lookup table
+var DefaultExportColumns = []string{
+	"id", "status", "priority", "createdAt", "patentKey", "publicationNumber", "title", "technicalField", "engine",
+	"engineVersion", "currency", "low", "expected", "high", "valuationDate", "labels", "tags", "note", "error",
+}
+
+func optional(value string) any {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func valuationColumn(value func(*ValuationDTO) any) exportColumn {
+	return func(j JobDTO) any {
+		if j.Valuation == nil {
+			return nil
+		}
+
+		return value(j.Valuation)
+	}
+}
+
+// ExportColumnsFromDTO parses a comma separated list of column names, an empty list selects the
+// DefaultExportColumns.
+func ExportColumnsFromDTO(columns string) ([]string, error) {
+	if strings.TrimSpace(columns) == "" {
+		return DefaultExportColumns, nil
+	}
+
+	var result []string
+
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if _, ok := exportColumns[column]; !ok {
+			names := make([]string, 0, len(exportColumns))
+			for name := range exportColumns {
+				names = append(names, name)
+			}
+
+			slices.Sort(names)
+
+			return nil, errors.Wrapf(errUnknownExportColumn, "%q is none of %s", column, strings.Join(names, ", "))
+		}
+
+		result = append(result, column)
+	}
+
+	return result, nil
+}
+
+// ExportWriter writes jobs one at a time in an export format.
+type ExportWriter interface {
+	Write(job JobDTO) error
+	// Flush writes buffered jobs to the underlying writer
+	Flush() error
+	// Close completes the export, it does not close the underlying writer
+	Close() error
+}
+
+// NewExportWriter writes the given columns of jobs in the format; CSV and XLSX start with a header row and join
+// labels and tags into a single cell, NDJSON writes an object per job with the columns as keys.
+func NewExportWriter(format string, w io.Writer, columns []string) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		writer := &csvExportWriter{writer: csv.NewWriter(w), columns: columns}
+
+		return writer, writer.writeRecord(columns)
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{writer: w, columns: columns}, nil
+	case ExportFormatXLSX:
+		workbook, err := xlsx.NewWriter(w, "Patents")
+		if err != nil {
+			return nil, err
+		}
+
+		header := make([]any, len(columns))
+		for i, column := range columns {
+			header[i] = column
+		}
+
+		return &xlsxExportWriter{workbook: workbook, columns: columns}, workbook.WriteRow(header)
+	default:
+		return nil, errUnknownExportFormat
+	}
+}
+
+// cells returns the values of the columns with labels and tags joined into a single cell.
+func cells(job JobDTO, columns []string) []any {
+	values := make([]any, len(columns))
+
+	for i, column := range columns {
+		switch value := exportColumns[column](job).(type) {
+		case map[string]string:
+			pairs := make([]string, 0, len(value))
+			for key, labelValue := range value {
+				pairs = append(pairs, key+"="+labelValue)
+			}
+
+			slices.Sort(pairs)
+			values[i] = strings.Join(pairs, "; ")
+		case []string:
+			values[i] = strings.Join(value, "; ")
+		default:
+			values[i] = value
+		}
+	}
+
+	return values
+}
+
+// formulaPrefixes are the characters that make spreadsheet applications evaluate a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula prefixes text starting like a formula with an apostrophe, so a title or note such as
+// "=HYPERLINK(...)" is shown as text instead of being evaluated when a CSV export is opened in a spreadsheet. XLSX
+// needs no escaping as its text cells are inline strings, which are never evaluated.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
+
+type csvExportWriter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func (c *csvExportWriter) Write(job JobDTO) error {
+	values := cells(job, c.columns)
+	record := make([]string, len(values))
+
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = escapeFormula(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case time.Time:
+			record[i] = v.Format(time.RFC3339)
+		}
+	}
+
+	return c.writeRecord(record)
+}
+
+func (c *csvExportWriter) writeRecord(record []string) error {
+	return errors.Wrap(c.writer.Write(record), "cannot write CSV record")
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.writer.Flush()
+
+	return errors.Wrap(c.writer.Error(), "cannot flush CSV")
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+type ndjsonExportWriter struct {
+	writer  io.Writer
+	columns []string
+}
+
+// Write encodes the object by hand to keep the keys in the order of the columns.
+func (n *ndjsonExportWriter) Write(job JobDTO) error {
+	var line bytes.Buffer
+
+	line.WriteByte('{')
+
+	for i, column := range n.columns {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+
+		key, err := json.Marshal(column)
+		if err != nil {
+			return errors.Wrap(err, "cannot encode column")
+		}
+
+		value, err := json.Marshal(exportColumns[column](job))
+		if err != nil {
+			return errors.Wrapf(err, "cannot encode %s", column)
+		}
+
+		line.Write(key)
+		line.WriteByte(':')
+		line.Write(value)
+	}
+
+	line.WriteString("}\n")
+
+	_, err := n.writer.Write(line.Bytes())
+
+	return errors.Wrap(err, "cannot write NDJSON line")
+}
+
+func (n *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+type xlsxExportWriter struct {
+	workbook *xlsx.Writer
+	columns  []string
+}
+
+func (x *xlsxExportWriter) Write(job JobDTO) error {
+	return x.workbook.WriteRow(cells(job, x.columns))
+}
+
+func (x *xlsxExportWriter) Flush() error {
+	return x.workbook.Flush()
+}
+
+func (x *xlsxExportWriter) Close() error {
+	return x.workbook.Close()
+}
@@ -0,0 +1,127 @@
+package patents
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// exportFlushInterval is the number of jobs after which the export is flushed to the client.
+const exportFlushInterval = 100
+
+func (h *handler) ExportPatentValuationJobs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		format, err := ExportFormatFromDTO(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+		if errors.Is(err, errUnknownExportFormat) {
+			return problem.Invalid("format", err)
+		} else if err != nil {
+			return err
+		}
+
+		columns, err := ExportColumnsFromDTO(c.QueryParam("columns"))
+		if err != nil {
+			return problem.Invalid("columns", err)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		each, err := h.exportJobs(identity, c.QueryParam("q"), jobFilter(c))
+		if err != nil {
+			return err
+		}
+
+		response := c.Response()
+		response.Header().Set(echo.HeaderContentType, ExportMediaTypes[format])
+		response.Header().Set(echo.HeaderContentDisposition, `attachment; filename="patents.`+format+`"`)
+		response.WriteHeader(http.StatusOK)
+
+		// the response is committed from here on, failures can only be logged and end the download prematurely
+		if err := h.writeExport(response, format, columns, targetCurrency, each); err != nil {
+			log.Errorf("export aborted: %v", err)
+		}
+
+		return nil
+	}
+}
+
+// exportJobs returns an iteration over the jobs to export. Jobs are read from the store one at a time; search results
+// have to be ranked before the first one is known, so the query runs upfront and its failures are reported before the
+// response is committed.
+func (h *handler) exportJobs(
+	identity authorization.Identity, query string, filter JobFilter,
+) (func(fn func(JobDTO) error) error, error) {
+	if query == "" {
+		return func(fn func(JobDTO) error) error {
+			return errors.WithStack(h.useCase.EachPatentValuationJobByIdentity(identity, filter,
+				func(job entities.EvaluationJob) error { return fn(JobToDTO(job)) }))
+		}, nil
+	}
+
+	results, err := h.useCase.SearchPatentValuationJobsByIdentity(identity, query, filter)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return func(fn func(JobDTO) error) error {
+		for _, found := range results {
+			if err := fn(SearchResultToDTO(found)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+// writeExport converts and writes one job at a time, flushing to the client every exportFlushInterval jobs.
+func (h *handler) writeExport(
+	response *echo.Response, format string, columns []string, targetCurrency string,
+	each func(fn func(JobDTO) error) error,
+) error {
+	writer, err := NewExportWriter(format, response, columns)
+	if err != nil {
+		return err
+	}
+
+	written := 0
+
+	err = each(func(dto JobDTO) error {
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
+
+		if err := writer.Write(dto); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+
+			response.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
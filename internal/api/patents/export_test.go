@@ -0,0 +1,250 @@
+//nolint:funlen // Test functions are long, due to test cases
+package patents_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+)
+
+func TestExportFormatFromDTO(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		format  string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{name: "neither format nor accept", want: patents.ExportFormatCSV},
+		{name: "any media type", accept: "*/*", want: patents.ExportFormatCSV},
+		{name: "format wins over accept", format: "xlsx", accept: "text/csv", want: patents.ExportFormatXLSX},
+		{name: "unknown format", format: "pdf", wantErr: true},
+		{name: "NDJSON", accept: "application/x-ndjson", want: patents.ExportFormatNDJSON},
+		{
+			name:   "most preferred supported media type",
+			accept: "application/pdf, text/csv;q=0.5, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;q=0.8",
+			want:   patents.ExportFormatXLSX,
+		},
+		{name: "quality zero is not acceptable", accept: "text/csv;q=0, application/x-ndjson", want: "ndjson"},
+		{name: "no supported media type", accept: "application/json", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := patents.ExportFormatFromDTO(tc.format, tc.accept)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExportColumnsFromDTO(t *testing.T) {
+	t.Parallel()
+
+	columns, err := patents.ExportColumnsFromDTO("")
+	assert.NoError(t, err)
+	assert.Equal(t, patents.DefaultExportColumns, columns)
+
+	columns, err = patents.ExportColumnsFromDTO("id, expected,labels")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "expected", "labels"}, columns)
+
+	_, err = patents.ExportColumnsFromDTO("id,inventor")
+	assert.ErrorContains(t, err, `"inventor"`)
+}
+
+func TestNewExportWriter(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	jobs := []patents.JobDTO{
+		{
+			ID:        "finished",
+			Status:    "finished",
+			CreatedAt: createdAt,
+			Title:     `Signature, "verified"`,
+			Valuation: &patents.ValuationDTO{Currency: "EUR", Expected: 42000.5},
+			Labels:    map[string]string{"project": "alpha", "matter": "M-1"},
+			Tags:      []string{"billing", "urgent"},
+		},
+		{
+			ID:        "pending",
+			Status:    "pending",
+			CreatedAt: createdAt,
+		},
+	}
+	columns := []string{"id", "createdAt", "title", "currency", "expected", "labels", "tags"}
+
+	testCases := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "CSV",
+			format: patents.ExportFormatCSV,
+			want: "id,createdAt,title,currency,expected,labels,tags\n" +
+				`finished,2024-08-15T12:00:00Z,"Signature, ""verified""",EUR,42000.5,matter=M-1; project=alpha,billing; urgent` +
+				"\n" +
+				"pending,2024-08-15T12:00:00Z,,,,,\n",
+		},
+		{
+			name:   "NDJSON",
+			format: patents.ExportFormatNDJSON,
+			want: `{"id":"finished","createdAt":"2024-08-15T12:00:00Z","title":"Signature, \"verified\"","currency":"EUR",` +
+				`"expected":42000.5,"labels":{"matter":"M-1","project":"alpha"},"tags":["billing","urgent"]}` + "\n" +
+				`{"id":"pending","createdAt":"2024-08-15T12:00:00Z","title":null,"currency":null,"expected":null,` +
+				`"labels":null,"tags":null}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buffer bytes.Buffer
+
+			writer, err := patents.NewExportWriter(tc.format, &buffer, columns)
+			assert.NoError(t, err)
+
+			for _, job := range jobs {
+				assert.NoError(t, writer.Write(job))
+			}
+
+			assert.NoError(t, writer.Close())
+			assert.Equal(t, tc.want, buffer.String())
+		})
+	}
+}
+
+func TestNewExportWriter_Formulas(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		title   string
+		tags    []string
+		wantCSV string
+	}{
+		{name: "equals", title: `=HYPERLINK("http://evil.example","x")`, wantCSV: `'=HYPERLINK("http://evil.example","x")`},
+		{name: "plus", title: "+1+1", wantCSV: "'+1+1"},
+		{name: "minus", title: "-foo", wantCSV: "'-foo"},
+		{name: "at", title: "@SUM(A1)", wantCSV: "'@SUM(A1)"},
+		{name: "tab", title: "\t=1", wantCSV: "'\t=1"},
+		{name: "carriage return", title: "\r=1", wantCSV: "'\r=1"},
+		{name: "joined tags", tags: []string{"=cmd", "urgent"}, wantCSV: "'=cmd; urgent"},
+		{name: "plain text", title: "Signature = verified", wantCSV: "Signature = verified"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := patents.JobDTO{ID: "job", Title: tc.title, Tags: tc.tags}
+			columns := []string{"title"}
+			text := tc.title
+
+			if tc.tags != nil {
+				columns = []string{"tags"}
+				text = strings.Join(tc.tags, "; ")
+			}
+
+			var csvBuffer bytes.Buffer
+
+			writer, err := patents.NewExportWriter(patents.ExportFormatCSV, &csvBuffer, columns)
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Write(job))
+			assert.NoError(t, writer.Close())
+
+			records, err := csv.NewReader(&csvBuffer).ReadAll()
+			if assert.NoError(t, err) && assert.Len(t, records, 2) {
+				assert.Equal(t, []string{tc.wantCSV}, records[1])
+			}
+
+			// inline strings are never evaluated, so XLSX keeps the text as it is
+			var xlsxBuffer bytes.Buffer
+
+			writer, err = patents.NewExportWriter(patents.ExportFormatXLSX, &xlsxBuffer, columns)
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Write(job))
+			assert.NoError(t, writer.Close())
+			assert.Equal(t, []string{columns[0], text}, xlsxTexts(t, xlsxBuffer.Bytes()))
+
+			var ndjsonBuffer bytes.Buffer
+
+			writer, err = patents.NewExportWriter(patents.ExportFormatNDJSON, &ndjsonBuffer, []string{"title"})
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Write(job))
+			assert.NotContains(t, ndjsonBuffer.String(), `"'`, "NDJSON is not meant for spreadsheets and left as is")
+		})
+	}
+}
+
+// xlsxTexts returns the texts of the inline string cells of the workbook's sheet in document order.
+func xlsxTexts(t *testing.T, workbook []byte) []string {
+	t.Helper()
+
+	decoder := xml.NewDecoder(strings.NewReader(xlsxSheet(t, workbook)))
+
+	var texts []string
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return texts
+		} else if !assert.NoError(t, err) {
+			return nil
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "t" {
+			var text string
+			if !assert.NoError(t, decoder.DecodeElement(&text, &start)) {
+				return nil
+			}
+
+			texts = append(texts, text)
+		}
+	}
+}
+
+func xlsxSheet(t *testing.T, workbook []byte) string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(workbook), int64(len(workbook)))
+	if !assert.NoError(t, err) {
+		return ""
+	}
+
+	file, err := archive.Open("xl/worksheets/sheet1.xml")
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer file.Close()
+
+	sheet, err := io.ReadAll(file)
+	assert.NoError(t, err)
+
+	return string(sheet)
+}
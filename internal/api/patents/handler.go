@@ -1,8 +1,13 @@
 package patents
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -10,17 +15,40 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/MyChaOS87/patAi/internal/authorization"
-	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patentxml"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
 )
 
 type handler struct {
-	useCase ValuationJobUseCase
+	useCase      ValuationJobUseCase
+	patentSchema *openapi.Schema
+	currencies   *currency.Table
+	reports      ReportRenderer
+	// maxPDFDecodedSize of zero applies the default of pdftext
+	maxPDFDecodedSize int64
 }
 
-func NewHandler(useCase ValuationJobUseCase) Handler {
-	return &handler{
-		useCase: useCase,
+// NewHandler validates structured submissions against patentSchema, converts valuations with currencies and renders
+// printable reports with reports, nil disables reports.
+func NewHandler(
+	useCase ValuationJobUseCase, patentSchema *openapi.Schema, currencies *currency.Table, reports ReportRenderer,
+	options ...HandlerOption,
+) Handler {
+	h := &handler{
+		useCase:      useCase,
+		patentSchema: patentSchema,
+		currencies:   currencies,
+		reports:      reports,
 	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
 }
 
 var errGetIdentityFailed = errors.New("cannot get identity from context")
@@ -34,57 +62,337 @@ func getIdentityFromContext(c echo.Context) (authorization.Identity, error) {
 	return identity, nil
 }
 
+var (
+	errMalformedBoolQueryParam = errors.New("malformed boolean query parameter")
+	errMalformedUUIDQueryParam = errors.New("malformed id query parameter")
+	errMalformedLimit          = errors.New("limit must be a number between 1 and 100")
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 100
+)
+
+// parseBoolQueryParam returns false for an absent parameter.
+func parseBoolQueryParam(c echo.Context, name string) (bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, problem.Invalid(name, errMalformedBoolQueryParam)
+	}
+
+	return result, nil
+}
+
+// parseUUIDQueryParam returns uuid.Nil for an absent parameter.
+func parseUUIDQueryParam(c echo.Context, name string) (uuid.UUID, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+
+	result, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, problem.Invalid(name, errMalformedUUIDQueryParam)
+	}
+
+	return result, nil
+}
+
+// parseLabelQueryParams reads repeated key:value parameters, a key alone is kept with an empty value.
+func parseLabelQueryParams(c echo.Context, name string) map[string]string {
+	values := c.QueryParams()[name]
+	if len(values) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(values))
+
+	for _, value := range values {
+		key, labelValue, _ := strings.Cut(value, ":")
+		labels[key] = labelValue
+	}
+
+	return labels
+}
+
+// jobFilter selects jobs by the label and tag query parameters.
+func jobFilter(c echo.Context) JobFilter {
+	return JobFilter{
+		Labels: parseLabelQueryParams(c, "label"),
+		Tags:   c.QueryParams()["tag"],
+	}
+}
+
+const headerAcceptCurrency = "Accept-Currency"
+
+// requestedCurrency reads the currency query parameter, falling back to the Accept-Currency header, an empty result
+// keeps valuations in the engine's currency.
+func (h *handler) requestedCurrency(c echo.Context) (string, error) {
+	code := c.QueryParam("currency")
+	if code == "" {
+		code = c.Request().Header.Get(headerAcceptCurrency)
+	}
+
+	if code == "" {
+		return "", nil
+	}
+
+	if h.currencies == nil {
+		return "", problem.Invalid("currency", errors.Wrap(currency.ErrUnknownCurrency, code))
+	}
+
+	normalized, err := h.currencies.Normalize(code)
+	if err != nil {
+		return "", problem.Invalid("currency", errors.Wrap(err, "use one of the currencies of the conversion table"))
+	}
+
+	return normalized, nil
+}
+
+// inCurrency converts all amounts of finished jobs, an empty currency keeps them as they are.
+func (h *handler) inCurrency(code string, jobs ...*JobDTO) error {
+	if code == "" {
+		return nil
+	}
+
+	for _, job := range jobs {
+		if job != nil {
+			if err := job.Convert(h.currencies, code); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var errInvalidPatent = errors.New("invalid patent")
+
+var errUnsupportedPatentDocument = errors.New("unsupported patent document")
+
+// submission is a patent as read from a request.
+type submission struct {
+	content string
+	// patent is nil for plain text submissions
+	patent *entities.Patent
+	// document is nil unless a file was uploaded
+	document *entities.Document
+}
+
+// readSubmission reads a structured patent for JSON and XML (USPTO, EPO) content types and a PDF upload for
+// multipart forms, any other body is taken as plain text content.
+func (h *handler) readSubmission(c echo.Context) (submission, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType == echo.MIMEMultipartForm {
+		return h.readUpload(c)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return submission{}, errors.Wrap(err, "cannot read body")
+	}
+
+	var patent entities.Patent
+
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		patent, err = h.readJSONPatent(body)
+	case echo.MIMEApplicationXML, echo.MIMETextXML:
+		patent, err = patentxml.Parse(bytes.NewReader(body))
+		if errors.Is(err, patentxml.ErrUnsupportedDocument) {
+			err = problem.Detailed(errUnsupportedPatentDocument, "%v", err)
+		} else if err != nil {
+			err = problem.Detailed(errInvalidPatent, "%v", err)
+		}
+	default:
+		return submission{content: string(body)}, nil
+	}
+
+	if err != nil {
+		return submission{}, err
+	}
+
+	return submission{content: patent.Text(), patent: &patent}, nil
+}
+
+func (h *handler) readJSONPatent(body []byte) (entities.Patent, error) {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return entities.Patent{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	if err := h.patentSchema.Validate(document); err != nil {
+		return entities.Patent{}, schemaViolations(err)
+	}
+
+	var dto PatentSubmissionDTO
+	if err := json.Unmarshal(body, &dto); err != nil {
+		return entities.Patent{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	patent, err := PatentFromDTO(dto)
+	if err != nil {
+		return entities.Patent{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	return patent, nil
+}
+
+// schemaViolations reports the violations of the patent schema as field errors, located by JSON pointer.
+func schemaViolations(err error) error {
+	invalid := problem.Detailed(errInvalidPatent, "%v", err)
+
+	var violations openapi.ValidationErrors
+	if !errors.As(err, &violations) {
+		return invalid
+	}
+
+	fields := make([]problem.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		fields = append(fields, problem.FieldError{Field: violation.Path, Message: violation.Message})
+	}
+
+	return &problem.ValidationError{Err: invalid, Fields: fields}
+}
+
 func (h *handler) GetPatentValuationJobs() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		identity, err := getIdentityFromContext(c)
 		if err != nil {
-			log.Errorf("%v", err)
-
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			return err
 		}
 
-		jobs, err := h.useCase.GetPatentValuationJobsByIdentity(identity)
+		targetCurrency, err := h.requestedCurrency(c)
 		if err != nil {
-			log.Errorf("%v", err)
+			return err
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		dtos, err := h.listJobs(identity, c.QueryParam("q"), jobFilter(c))
+		if err != nil {
+			return err
 		}
 
-		if err := c.JSON(http.StatusOK, JobsToDTO(jobs)); err != nil {
-			log.Errorf("%v", err)
+		for i := range dtos {
+			if err := h.inCurrency(targetCurrency, &dtos[i]); err != nil {
+				return err
+			}
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if err := c.JSON(http.StatusOK, dtos); err != nil {
+			return errors.Wrap(err, "cannot write response")
 		}
 
 		return nil
 	}
 }
 
+// listJobs returns the jobs of the identity selected by the filter, restricted to those matching the full-text query
+// if there is one.
+func (h *handler) listJobs(identity authorization.Identity, query string, filter JobFilter) ([]JobDTO, error) {
+	if query == "" {
+		jobs, err := h.useCase.GetPatentValuationJobsByIdentity(identity, filter)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return JobsToDTO(jobs), nil
+	}
+
+	results, err := h.useCase.SearchPatentValuationJobsByIdentity(identity, query, filter)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return SearchResultsToDTO(results), nil
+}
+
 func (h *handler) GetPatentValuationJobByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		identity, err := getIdentityFromContext(c)
 		if err != nil {
-			log.Errorf("%v", err)
-
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			return err
 		}
 
 		uuid, err := uuid.Parse(c.Param("id"))
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "malformed job id")
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
 		}
 
 		job, err := h.useCase.GetPatentValuationJobByIdentityAndID(identity, uuid)
-		if errors.Is(err, ErrJobNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "job not found")
-		} else if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if err != nil {
+			return errors.WithStack(err)
 		}
 
-		if err := c.JSON(http.StatusOK, JobToDTO(job)); err != nil {
-			log.Errorf("%v", err)
+		dto := JobToDTO(job)
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if err := c.JSON(http.StatusOK, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPatentValuationDocument() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		document, err := h.useCase.GetPatentValuationDocumentByIdentityAndID(identity, uuid)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition,
+			mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+
+		if err := c.Blob(http.StatusOK, document.ContentType, document.Content); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPatentClaims() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		analysis, err := h.useCase.GetPatentClaimsByIdentityAndID(identity, uuid)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, ClaimAnalysisToDTO(analysis)); err != nil {
+			return errors.Wrap(err, "cannot write response")
 		}
 
 		return nil
@@ -95,33 +403,325 @@ func (h *handler) CreatePatentValuationJob() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		identity, err := getIdentityFromContext(c)
 		if err != nil {
-			log.Errorf("%v", err)
+			return err
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		priority, err := PriorityFromDTO(c.QueryParam("priority"))
+		if err != nil {
+			return problem.Invalid("priority", err)
 		}
 
-		body := new(strings.Builder)
-		if _, err := io.Copy(body, c.Request().Body); err != nil {
-			log.Errorf("%v", err)
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		submission, err := h.readSubmission(c)
+		if err != nil {
+			return err
 		}
 
-		content := body.String()
+		fresh, err := parseBoolQueryParam(c, "fresh")
+		if err != nil {
+			return err
+		}
 
-		job, err := h.useCase.CreatePatentValuationJob(identity, content)
-		if errors.Is(err, ErrQuotaExceeded) {
-			return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
-		} else if err != nil {
-			log.Errorf("%v", err)
+		job, err := h.useCase.CreatePatentValuationJob(identity, CreateJobRequest{
+			Content:        submission.content,
+			Priority:       priority,
+			Fresh:          fresh,
+			TechnicalField: c.QueryParam("technicalField"),
+			Patent:         submission.patent,
+			Document:       submission.document,
+			Engine:         c.QueryParam("engine"),
+			EngineVersion:  c.QueryParam("engineVersion"),
+			PatentKey:      c.QueryParam("patentKey"),
+			Metadata: entities.JobMetadata{
+				Labels: parseLabelQueryParams(c, "label"),
+				Tags:   c.QueryParams()["tag"],
+				Note:   c.QueryParam("note"),
+			},
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := JobToDTO(job)
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
+
+		if err := c.JSON(http.StatusCreated, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+var errMalformedMetadataPatch = errors.New("malformed metadata patch")
+
+func (h *handler) UpdatePatentValuationJobMetadata() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		var patch JobMetadataPatchDTO
+		if err := json.NewDecoder(c.Request().Body).Decode(&patch); err != nil {
+			return problem.Detailed(errMalformedMetadataPatch, "%v", err)
+		}
+
+		job, err := h.useCase.UpdatePatentValuationJobMetadata(identity, uuid, MetadataPatchFromDTO(patch))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := JobToDTO(job)
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
+
+		if err := c.JSON(http.StatusOK, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) RevaluePatentValuationJob() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		priority, err := PriorityFromDTO(c.QueryParam("priority"))
+		if err != nil {
+			return problem.Invalid("priority", err)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		job, err := h.useCase.RevaluePatentValuationJob(identity, uuid, RevalueJobRequest{
+			Priority:      priority,
+			Engine:        c.QueryParam("engine"),
+			EngineVersion: c.QueryParam("engineVersion"),
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := JobToDTO(job)
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if err := c.JSON(http.StatusCreated, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
 		}
 
-		if err := c.JSON(http.StatusCreated, JobToDTO(job)); err != nil {
-			log.Errorf("%v", err)
+		return nil
+	}
+}
+
+func (h *handler) GetPatentRevaluations() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		jobs, err := h.useCase.GetPatentRevaluationsByIdentityAndID(identity, uuid)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dtos := JobsToDTO(jobs)
+		for i := range dtos {
+			if err := h.inCurrency(targetCurrency, &dtos[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := c.JSON(http.StatusOK, dtos); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetEngines() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := c.JSON(http.StatusOK, EnginesToDTO(h.useCase.GetEngines())); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPatentHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		patentKey, err := url.PathUnescape(c.Param("key"))
+		if err != nil {
+			return problem.Invalid("key", errMalformedPatentKey)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		jobs, err := h.useCase.GetPatentHistoryByIdentity(identity, patentKey)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := HistoryToDTO(patentKey, jobs)
+		for i := range dto.Jobs {
+			if err := h.inCurrency(targetCurrency, &dto.Jobs[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := c.JSON(http.StatusOK, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPatentDiff() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		patentKey, err := url.PathUnescape(c.Param("key"))
+		if err != nil {
+			return problem.Invalid("key", errMalformedPatentKey)
+		}
+
+		from, err := parseUUIDQueryParam(c, "from")
+		if err != nil {
+			return err
+		}
+
+		to, err := parseUUIDQueryParam(c, "to")
+		if err != nil {
+			return err
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		diff, err := h.useCase.GetPatentDiffByIdentity(identity, patentKey, from, to)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := PatentDiffToDTO(diff)
+
+		// without a requested currency both values are compared in the currency of the earlier one
+		if targetCurrency == "" && dto.From.Valuation != nil && dto.To.Valuation != nil &&
+			dto.From.Valuation.Currency != dto.To.Valuation.Currency {
+			targetCurrency = dto.From.Valuation.Currency
+		}
+
+		if err := h.inCurrency(targetCurrency, &dto.From, &dto.To); err != nil {
+			return err
+		}
+
+		dto.Value = ValueChangeToDTO(dto.From.Valuation, dto.To.Valuation)
+
+		if err := c.JSON(http.StatusOK, dto); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetSimilarPatents() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		limit := defaultSimilarLimit
+		if value := c.QueryParam("limit"); value != "" {
+			limit, err = strconv.Atoi(value)
+			if err != nil || limit < 1 || limit > maxSimilarLimit {
+				return problem.Invalid("limit", errMalformedLimit)
+			}
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		patents, err := h.useCase.GetSimilarPatentsByIdentityAndID(identity, uuid, limit)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dtos := SimilarPatentsToDTO(patents)
+		for i := range dtos {
+			if err := h.inCurrency(targetCurrency, &dtos[i].Job); err != nil {
+				return err
+			}
+		}
 
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if err := c.JSON(http.StatusOK, dtos); err != nil {
+			return errors.Wrap(err, "cannot write response")
 		}
 
 		return nil
@@ -0,0 +1,154 @@
+package patents
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const (
+	maxLabels          = 32
+	maxLabelValueRunes = 256
+	maxTags            = 32
+	maxTagRunes        = 64
+	maxNoteRunes       = 4096
+)
+
+// labelKey matches label keys such as "matter" or "client.project-id".
+var labelKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,62}$`)
+
+// NormalizeMetadata trims labels, tags and the note, sorts the tags and drops duplicate ones; it returns an
+// ErrInvalidMetadata error for malformed label keys, empty label values or tags and for exceeded limits.
+func NormalizeMetadata(metadata entities.JobMetadata) (entities.JobMetadata, error) {
+	var result entities.JobMetadata
+
+	if len(metadata.Labels) > maxLabels {
+		return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata, "at most %d labels", maxLabels)
+	}
+
+	if len(metadata.Labels) > 0 {
+		result.Labels = make(map[string]string, len(metadata.Labels))
+	}
+
+	for key, value := range metadata.Labels {
+		if !labelKey.MatchString(key) {
+			return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata,
+				"label key %q must start with a letter or digit and consist of up to 63 letters, digits, ., _, / or -",
+				key)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" || utf8.RuneCountInString(value) > maxLabelValueRunes {
+			return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata,
+				"value of label %q must have 1 to %d characters", key, maxLabelValueRunes)
+		}
+
+		result.Labels[key] = value
+	}
+
+	for _, tag := range metadata.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagRunes {
+			return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata, "tags must have 1 to %d characters", maxTagRunes)
+		}
+
+		result.Tags = append(result.Tags, tag)
+	}
+
+	slices.Sort(result.Tags)
+	result.Tags = slices.Compact(result.Tags)
+
+	if len(result.Tags) > maxTags {
+		return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata, "at most %d tags", maxTags)
+	}
+
+	result.Note = strings.TrimSpace(metadata.Note)
+	if utf8.RuneCountInString(result.Note) > maxNoteRunes {
+		return entities.JobMetadata{}, problem.Detailed(ErrInvalidMetadata, "note must have at most %d characters",
+			maxNoteRunes)
+	}
+
+	return result, nil
+}
+
+// MetadataPatch changes the metadata of a job, nil fields are left as they are.
+type MetadataPatch struct {
+	// Labels are merged into the existing labels, a nil value removes the label
+	Labels map[string]*string
+	// Tags replace the existing tags
+	Tags *[]string
+	// Note replaces the existing note, an empty note removes it
+	Note *string
+}
+
+// Apply returns the metadata with the patch applied, the given metadata is not changed.
+func (p MetadataPatch) Apply(metadata entities.JobMetadata) entities.JobMetadata {
+	result := entities.JobMetadata{
+		Labels: make(map[string]string, len(metadata.Labels)+len(p.Labels)),
+		Tags:   metadata.Tags,
+		Note:   metadata.Note,
+	}
+
+	for key, value := range metadata.Labels {
+		result.Labels[key] = value
+	}
+
+	for key, value := range p.Labels {
+		if value == nil {
+			delete(result.Labels, key)
+		} else {
+			result.Labels[key] = *value
+		}
+	}
+
+	if p.Tags != nil {
+		result.Tags = *p.Tags
+	}
+
+	if p.Note != nil {
+		result.Note = *p.Note
+	}
+
+	return result
+}
+
+// JobFilter selects jobs by their metadata, the zero value selects all jobs.
+type JobFilter struct {
+	// Labels must all be set on the job, an empty value accepts any value of the label
+	Labels map[string]string
+	// Tags must all be set on the job
+	Tags []string
+}
+
+func (f JobFilter) Matches(job entities.EvaluationJob) bool {
+	for key, value := range f.Labels {
+		actual, ok := job.Metadata.Labels[key]
+		if !ok || (value != "" && actual != value) {
+			return false
+		}
+	}
+
+	for _, tag := range f.Tags {
+		if !slices.Contains(job.Metadata.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func filterJobs(jobs []entities.EvaluationJob, filter JobFilter) []entities.EvaluationJob {
+	result := make([]entities.EvaluationJob, 0, len(jobs))
+
+	for _, job := range jobs {
+		if filter.Matches(job) {
+			result = append(result, job)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+//nolint:funlen // Test functions are long, due to test cases
+package patents_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+func TestNormalizeMetadata(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		metadata entities.JobMetadata
+		want     entities.JobMetadata
+		wantErr  bool
+	}{
+		{
+			name:     "empty metadata",
+			metadata: entities.JobMetadata{Labels: map[string]string{}, Tags: []string{}},
+			want:     entities.JobMetadata{},
+		},
+		{
+			name: "values are trimmed, tags sorted without duplicates",
+			metadata: entities.JobMetadata{
+				Labels: map[string]string{"client.matter/no": " 2024-0815 "},
+				Tags:   []string{" urgent", "billing", "urgent "},
+				Note:   "  check claim 3\n",
+			},
+			want: entities.JobMetadata{
+				Labels: map[string]string{"client.matter/no": "2024-0815"},
+				Tags:   []string{"billing", "urgent"},
+				Note:   "check claim 3",
+			},
+		},
+		{
+			name:     "label key with spaces",
+			metadata: entities.JobMetadata{Labels: map[string]string{"client matter": "1"}},
+			wantErr:  true,
+		},
+		{
+			name:     "label key starting with a separator",
+			metadata: entities.JobMetadata{Labels: map[string]string{"-matter": "1"}},
+			wantErr:  true,
+		},
+		{
+			name:     "empty label value",
+			metadata: entities.JobMetadata{Labels: map[string]string{"matter": " "}},
+			wantErr:  true,
+		},
+		{
+			name:     "empty tag",
+			metadata: entities.JobMetadata{Tags: []string{"urgent", ""}},
+			wantErr:  true,
+		},
+		{
+			name:     "too long tag",
+			metadata: entities.JobMetadata{Tags: []string{strings.Repeat("x", 65)}},
+			wantErr:  true,
+		},
+		{
+			name:     "too long note",
+			metadata: entities.JobMetadata{Note: strings.Repeat("x", 4097)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := patents.NormalizeMetadata(tc.metadata)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, patents.ErrInvalidMetadata)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJobFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	job := entities.EvaluationJob{Metadata: entities.JobMetadata{
+		Labels: map[string]string{"matter": "M-1", "project": "alpha"},
+		Tags:   []string{"billing", "urgent"},
+	}}
+
+	testCases := []struct {
+		name   string
+		filter patents.JobFilter
+		want   bool
+	}{
+		{name: "empty filter", filter: patents.JobFilter{}, want: true},
+		{name: "label value", filter: patents.JobFilter{Labels: map[string]string{"matter": "M-1"}}, want: true},
+		{name: "other label value", filter: patents.JobFilter{Labels: map[string]string{"matter": "M-2"}}, want: false},
+		{name: "any label value", filter: patents.JobFilter{Labels: map[string]string{"project": ""}}, want: true},
+		{name: "missing label", filter: patents.JobFilter{Labels: map[string]string{"client": ""}}, want: false},
+		{name: "all tags", filter: patents.JobFilter{Tags: []string{"urgent", "billing"}}, want: true},
+		{name: "missing tag", filter: patents.JobFilter{Tags: []string{"urgent", "review"}}, want: false},
+		{
+			name:   "labels and tags",
+			filter: patents.JobFilter{Labels: map[string]string{"matter": "M-1"}, Tags: []string{"review"}},
+			want:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tc.filter.Matches(job))
+		})
+	}
+}
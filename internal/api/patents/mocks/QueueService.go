@@ -14,9 +14,45 @@ type QueueService struct {
 	mock.Mock
 }
 
-// EnqueueJob provides a mock function with given fields: ownerID, content
-func (_m *QueueService) EnqueueJob(ownerID string, content string) (entities.EvaluationJob, error) {
-	ret := _m.Called(ownerID, content)
+// DeleteJob provides a mock function with given fields: id
+func (_m *QueueService) DeleteJob(id uuid.UUID) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EachJobByOwnerID provides a mock function with given fields: ownerID, fn
+func (_m *QueueService) EachJobByOwnerID(ownerID string, fn func(entities.EvaluationJob) error) error {
+	ret := _m.Called(ownerID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EachJobByOwnerID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, func(entities.EvaluationJob) error) error); ok {
+		r0 = rf(ownerID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnqueueJob provides a mock function with given fields: ownerID, content, options
+func (_m *QueueService) EnqueueJob(ownerID string, content string, options entities.JobOptions) (entities.EvaluationJob, error) {
+	ret := _m.Called(ownerID, content, options)
 
 	if len(ret) == 0 {
 		panic("no return value specified for EnqueueJob")
@@ -24,17 +60,17 @@ func (_m *QueueService) EnqueueJob(ownerID string, content string) (entities.Eva
 
 	var r0 entities.EvaluationJob
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (entities.EvaluationJob, error)); ok {
-		return rf(ownerID, content)
+	if rf, ok := ret.Get(0).(func(string, string, entities.JobOptions) (entities.EvaluationJob, error)); ok {
+		return rf(ownerID, content, options)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) entities.EvaluationJob); ok {
-		r0 = rf(ownerID, content)
+	if rf, ok := ret.Get(0).(func(string, string, entities.JobOptions) entities.EvaluationJob); ok {
+		r0 = rf(ownerID, content, options)
 	} else {
 		r0 = ret.Get(0).(entities.EvaluationJob)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(ownerID, content)
+	if rf, ok := ret.Get(1).(func(string, string, entities.JobOptions) error); ok {
+		r1 = rf(ownerID, content, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,6 +136,34 @@ func (_m *QueueService) GetJobsByOwnerID(ownerID string) ([]entities.EvaluationJ
 	return r0, r1
 }
 
+// UpdateJobMetadata provides a mock function with given fields: id, metadata
+func (_m *QueueService) UpdateJobMetadata(id uuid.UUID, metadata entities.JobMetadata) (entities.EvaluationJob, error) {
+	ret := _m.Called(id, metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateJobMetadata")
+	}
+
+	var r0 entities.EvaluationJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, entities.JobMetadata) (entities.EvaluationJob, error)); ok {
+		return rf(id, metadata)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID, entities.JobMetadata) entities.EvaluationJob); ok {
+		r0 = rf(id, metadata)
+	} else {
+		r0 = ret.Get(0).(entities.EvaluationJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID, entities.JobMetadata) error); ok {
+		r1 = rf(id, metadata)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewQueueService creates a new instance of QueueService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQueueService(t interface {
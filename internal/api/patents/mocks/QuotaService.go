@@ -43,6 +43,36 @@ func (_m *QuotaService) GetQuotaToken(ownerID string) (uuid.UUID, error) {
 	return r0, r1
 }
 
+// GetQuotaTokens provides a mock function with given fields: ownerID, count
+func (_m *QuotaService) GetQuotaTokens(ownerID string, count int) ([]uuid.UUID, error) {
+	ret := _m.Called(ownerID, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuotaTokens")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]uuid.UUID, error)); ok {
+		return rf(ownerID, count)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []uuid.UUID); ok {
+		r0 = rf(ownerID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(ownerID, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReturnQuotaToken provides a mock function with given fields: token
 func (_m *QuotaService) ReturnQuotaToken(token uuid.UUID) {
 	_m.Called(token)
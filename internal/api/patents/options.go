@@ -0,0 +1,57 @@
+package patents
+
+import (
+	"github.com/MyChaOS87/patAi/config"
+)
+
+type (
+	UseCaseOption func(*valuationJobUseCase)
+	HandlerOption func(*handler)
+)
+
+// WithResultCache lets jobs for content recently valued by the job's engine finish immediately with the cached
+// result, which requires WithEngines to know the engine at creation.
+func WithResultCache(resultCache ResultCache, cfg *config.ResultCacheConfig) UseCaseOption {
+	return func(v *valuationJobUseCase) {
+		v.resultCache = resultCache
+		v.resultCacheConfig = cfg
+	}
+}
+
+// WithSimilarityIndex indexes the content of new jobs to find similar earlier submissions, re-valuations are not
+// indexed as their content is that of the original job.
+func WithSimilarityIndex(index SimilarityIndex) UseCaseOption {
+	return func(v *valuationJobUseCase) {
+		v.similarityIndex = index
+	}
+}
+
+// WithSearch enables full-text search across the jobs of an identity.
+func WithSearch(searchService SearchService) UseCaseOption {
+	return func(v *valuationJobUseCase) {
+		v.searchService = searchService
+	}
+}
+
+// WithEngines lets jobs be created for a specific engine and enables re-valuations, without it jobs are left to the
+// workers' default engine.
+func WithEngines(engines EngineRegistry) UseCaseOption {
+	return func(v *valuationJobUseCase) {
+		v.engines = engines
+	}
+}
+
+// WithBatches enables batch submissions of up to maxBatchSize jobs.
+func WithBatches(batchService BatchService, maxBatchSize int) UseCaseOption {
+	return func(v *valuationJobUseCase) {
+		v.batchService = batchService
+		v.maxBatchSize = maxBatchSize
+	}
+}
+
+// WithMaxPDFDecodedSize limits what the streams of an uploaded PDF decompress to, larger uploads are rejected.
+func WithMaxPDFDecodedSize(size int64) HandlerOption {
+	return func(h *handler) {
+		h.maxPDFDecodedSize = size
+	}
+}
@@ -0,0 +1,24 @@
+package patents
+
+import (
+	"regexp"
+	"strings"
+)
+
+// kindCode matches the kind code ending a publication number, e.g. A1 or B2.
+var kindCode = regexp.MustCompile(`(\d)[A-Z]\d?$`)
+
+// PatentKey identifies a logical patent by its publication number independent of its formatting and the stage of
+// publication: "EP 1 234 567 A1" and "ep1234567b1" share the key "EP1234567".
+func PatentKey(publicationNumber string) string {
+	key := strings.ToUpper(publicationNumber)
+	key = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '/' || r == ',' || r == '.' {
+			return -1
+		}
+
+		return r
+	}, key)
+
+	return kindCode.ReplaceAllString(key, "$1")
+}
@@ -0,0 +1,98 @@
+package patents
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+var (
+	errMalformedJobID     = errors.New("malformed job id")
+	errMalformedBatchID   = errors.New("malformed batch id")
+	errMalformedPatentKey = errors.New("malformed patent key")
+)
+
+// ProblemTypes maps the errors of the package to the problem types they are reported as.
+func ProblemTypes() []problem.Mapping {
+	return []problem.Mapping{
+		// not found
+		{Err: ErrJobNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "job-not-found", Title: "Job not found",
+		}},
+		{Err: ErrDocumentNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "document-not-found", Title: "Job was not submitted as a document",
+		}},
+		{Err: ErrBatchNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "batch-not-found", Title: "Batch not found",
+		}},
+		{Err: ErrPatentNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "patent-not-found", Title: "Patent not found",
+		}},
+		{Err: ErrNoPreviousSubmission, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "no-previous-submission", Title: "Patent has no previous submission",
+		}},
+		// plan and quota
+		{Err: ErrQuotaExceeded, Type: problem.Type{
+			Status: http.StatusTooManyRequests, Code: "quota-exceeded", Title: "Quota exceeded",
+		}},
+		{Err: ErrPriorityNotAllowed, Type: problem.Type{
+			Status: http.StatusForbidden, Code: "priority-not-allowed", Title: "Priority not allowed by plan",
+		}},
+		{Err: ErrSameEngine, Type: problem.Type{
+			Status: http.StatusConflict, Code: "same-engine", Title: "Job was already valued by this engine",
+		}},
+		// disabled features
+		{Err: ErrBatchesDisabled, Type: problem.Type{
+			Status: http.StatusNotImplemented, Code: "batches-disabled", Title: "Batches are disabled",
+		}},
+		{Err: ErrSimilarityDisabled, Type: problem.Type{
+			Status: http.StatusNotImplemented, Code: "similarity-disabled", Title: "Similarity search is disabled",
+		}},
+		{Err: ErrSearchDisabled, Type: problem.Type{
+			Status: http.StatusNotImplemented, Code: "search-disabled", Title: "Full-text search is disabled",
+		}},
+		{Err: errReportsDisabled, Type: problem.Type{
+			Status: http.StatusNotImplemented, Code: "reports-disabled", Title: "Reports are disabled",
+		}},
+		// content negotiation
+		{Err: errUnsupportedPatentDocument, Type: problem.Type{
+			Status: http.StatusUnsupportedMediaType, Code: "unsupported-patent-document", Title: "Unsupported patent document",
+		}},
+		{Err: errUnsupportedBatchContent, Type: problem.Type{
+			Status: http.StatusUnsupportedMediaType, Code: "unsupported-batch-content", Title: "Unsupported batch content type",
+		}},
+		{Err: errDocumentTooLarge, Type: problem.Type{
+			Status: http.StatusRequestEntityTooLarge, Code: "document-too-large", Title: "Document too large",
+		}},
+		{Err: errNotAcceptable, Type: problem.Type{
+			Status: http.StatusNotAcceptable, Code: "not-acceptable", Title: "Export format not acceptable",
+		}},
+		// invalid requests
+		{Err: errInvalidPatent, Type: badRequest("invalid-patent", "Invalid patent")},
+		{Err: errMalformedBatch, Type: badRequest("malformed-batch", "Malformed batch")},
+		{Err: ErrEmptyBatch, Type: badRequest("empty-batch", "Batch is empty")},
+		{Err: ErrBatchTooLarge, Type: badRequest("batch-too-large", "Batch too large")},
+		{Err: ErrUnknownEngine, Type: badRequest("unknown-engine", "Unknown engine")},
+		{Err: ErrInvalidMetadata, Type: badRequest("invalid-metadata", "Invalid job metadata")},
+		{Err: errMalformedMetadataPatch, Type: badRequest("malformed-metadata-patch", "Malformed metadata patch")},
+		{Err: ErrInvalidQuery, Type: badRequest("invalid-query", "Invalid search query")},
+		{Err: currency.ErrUnknownCurrency, Type: badRequest("unknown-currency", "Unknown currency")},
+		{Err: errUnknownPriority, Type: badRequest("unknown-priority", "Unknown priority")},
+		{Err: errUnknownBatchMode, Type: badRequest("unknown-batch-mode", "Unknown batch mode")},
+		{Err: errUnknownExportFormat, Type: badRequest("unknown-export-format", "Unknown export format")},
+		{Err: errUnknownExportColumn, Type: badRequest("unknown-export-column", "Unknown export column")},
+		{Err: errMalformedJobID, Type: badRequest("malformed-job-id", "Malformed job ID")},
+		{Err: errMalformedBatchID, Type: badRequest("malformed-batch-id", "Malformed batch ID")},
+		{Err: errMalformedPatentKey, Type: badRequest("malformed-patent-key", "Malformed patent key")},
+		{Err: errMalformedBoolQueryParam, Type: badRequest("malformed-query-parameter", "Malformed query parameter")},
+		{Err: errMalformedUUIDQueryParam, Type: badRequest("malformed-query-parameter", "Malformed query parameter")},
+		{Err: errMalformedLimit, Type: badRequest("malformed-query-parameter", "Malformed query parameter")},
+	}
+}
+
+func badRequest(code string, title string) problem.Type {
+	return problem.Type{Status: http.StatusBadRequest, Code: code, Title: title}
+}
@@ -0,0 +1,126 @@
+package patents
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/report"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+var errReportsDisabled = errors.New("reports are disabled")
+
+func (h *handler) GetPatentReport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		if h.reports == nil {
+			return errReportsDisabled
+		}
+
+		uuid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedJobID)
+		}
+
+		targetCurrency, err := h.requestedCurrency(c)
+		if err != nil {
+			return err
+		}
+
+		job, err := h.useCase.GetPatentValuationJobByIdentityAndID(identity, uuid)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		analysis, err := h.useCase.GetPatentClaimsByIdentityAndID(identity, uuid)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		dto := JobToDTO(job)
+		if err := h.inCurrency(targetCurrency, &dto); err != nil {
+			return err
+		}
+
+		// rendered into a buffer first, so that a failing template still yields a proper error response
+		var rendered bytes.Buffer
+		if err := h.reports.Render(&rendered, identity.GetID(), report.Report{
+			Job:         JobToReport(dto),
+			Patent:      job.Patent,
+			Claims:      analysis,
+			GeneratedAt: time.Now(),
+		}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.HTMLBlob(http.StatusOK, rendered.Bytes()); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+// JobToReport maps a job, already converted to the requested currency, to what reports show of it.
+func JobToReport(dto JobDTO) report.Job {
+	job := report.Job{
+		ID:                dto.ID,
+		Status:            dto.Status,
+		CreatedAt:         dto.CreatedAt,
+		PatentKey:         dto.PatentKey,
+		TechnicalField:    dto.TechnicalField,
+		Title:             dto.Title,
+		PublicationNumber: dto.PublicationNumber,
+		Labels:            dto.Labels,
+		Tags:              dto.Tags,
+		Note:              dto.Note,
+		Cached:            dto.Cached,
+		Error:             dto.Error,
+	}
+
+	if dto.Document != nil {
+		job.FileName = dto.Document.FileName
+	}
+
+	if valuation := dto.Valuation; valuation != nil {
+		job.Valuation = &report.Valuation{
+			Currency: valuation.Currency,
+			Low:      valuation.Low,
+			Expected: valuation.Expected,
+			High:     valuation.High,
+			Date:     valuation.Date,
+		}
+	}
+
+	if explanation := dto.Explanation; explanation != nil {
+		job.Explanation = &report.Explanation{
+			Engine:  entities.EngineInfo{Name: explanation.Engine.Name, Version: explanation.Engine.Version},
+			Factors: make([]report.ValuationFactor, 0, len(explanation.Factors)),
+		}
+
+		for _, factor := range explanation.Factors {
+			job.Explanation.Factors = append(job.Explanation.Factors, report.ValuationFactor{
+				Name:        factor.Name,
+				Description: factor.Description,
+				Weight:      factor.Weight,
+				Score:       factor.Score,
+			})
+		}
+
+		if explanation.Confidence != nil {
+			job.Explanation.ConfidenceLevel = explanation.Confidence.Level
+		}
+	}
+
+	return job
+}
@@ -1,12 +1,16 @@
-//go:generate mockery --name QueueService|QuotaService
+//go:generate mockery --name QueueService|QuotaService|ResultCache|BatchService|EngineRegistry|SimilarityIndex|SearchService|ReportRenderer
 
 package patents
 
 import (
+	"io"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/report"
 )
 
 var (
@@ -14,16 +18,82 @@ var (
 	ErrCouldNotRetrieveQuota = errors.New("could not retrieve quota token")
 	ErrCouldNotEnqueueJob    = errors.New("could not enqueue job")
 	ErrJobNotFound           = errors.New("job not found")
+	ErrDocumentNotFound      = errors.New("document not found")
+	ErrPriorityNotAllowed    = errors.New("priority not allowed by plan")
+	ErrBatchNotFound         = errors.New("batch not found")
+	ErrEmptyBatch            = errors.New("batch is empty")
+	ErrBatchTooLarge         = errors.New("batch too large")
+	ErrBatchesDisabled       = errors.New("batches are disabled")
+	ErrUnknownEngine         = entities.ErrUnknownEngine
+	ErrSameEngine            = errors.New("job was already valued by this engine")
+	ErrPatentNotFound        = errors.New("patent not found")
+	ErrNoPreviousSubmission  = errors.New("patent has no previous submission")
+	ErrSimilarityDisabled    = errors.New("similarity search is disabled")
+	ErrSearchDisabled        = errors.New("full-text search is disabled")
+	ErrInvalidQuery          = errors.New("invalid search query")
+	ErrInvalidMetadata       = errors.New("invalid job metadata")
 )
 
 type QueueService interface {
-	EnqueueJob(ownerID string, content string) (entities.EvaluationJob, error)
+	EnqueueJob(ownerID string, content string, options entities.JobOptions) (entities.EvaluationJob, error)
 	GetJobsByOwnerID(ownerID string) ([]entities.EvaluationJob, error)
+	// EachJobByOwnerID calls fn with each of the owner's jobs in creation order, one job at a time instead of
+	// collecting all of them; it stops at and returns the first error of fn
+	EachJobByOwnerID(ownerID string, fn func(entities.EvaluationJob) error) error
 	GetJobByID(id uuid.UUID) (entities.EvaluationJob, error)
+	// UpdateJobMetadata replaces the metadata of the job; returns an ErrJobNotFound error if there is no such job
+	UpdateJobMetadata(id uuid.UUID, metadata entities.JobMetadata) (entities.EvaluationJob, error)
+	// DeleteJob removes the job whatever its state, a worker valuing it loses its lease; returns an ErrJobNotFound
+	// error if there is no such job
+	DeleteJob(id uuid.UUID) error
 }
 
 type QuotaService interface {
 	// returns a token that can be used to enqueue a job and returns an ErrQuotaExceeded error if the quota is exceeded
 	GetQuotaToken(ownerID string) (uuid.UUID, error)
+	// returns count tokens at once or an ErrQuotaExceeded error without taking any
+	GetQuotaTokens(ownerID string, count int) ([]uuid.UUID, error)
 	ReturnQuotaToken(token uuid.UUID)
 }
+
+type BatchService interface {
+	CreateBatch(batch entities.Batch) error
+	GetBatchByID(id uuid.UUID) (entities.Batch, error)
+}
+
+type ResultCache interface {
+	// GetCachedResult returns the owner's latest result for the content hash by the given engine finished after
+	// notBefore, false if there is none; results are never shared between owners
+	GetCachedResult(
+		ownerID string, contentHash string, engine entities.EngineInfo, notBefore time.Time,
+	) (entities.CachedResult, bool, error)
+}
+
+type EngineRegistry interface {
+	// Resolve returns the engine for name and version, both empty select the default engine, an empty name alone the
+	// default engine's name and an empty version alone the latest version; it returns an ErrUnknownEngine error if
+	// there is no such engine
+	Resolve(name string, version string) (entities.EngineInfo, error)
+	// Engines returns all engines ordered by name and version
+	Engines() []entities.EngineInfo
+	Default() entities.EngineInfo
+}
+
+type SimilarityIndex interface {
+	// Add indexes the content of the job, jobs are only compared to jobs of the same owner
+	Add(job entities.EvaluationJob)
+	// Similar returns up to limit other jobs of the owner similar to the given job, most similar first; returns an
+	// entities.ErrJobNotIndexed error if the job is not indexed for the owner
+	Similar(ownerID string, id uuid.UUID, limit int) ([]entities.SimilarityMatch, error)
+}
+
+type SearchService interface {
+	// SearchJobs returns the owner's jobs matching the query, best matches first; returns an ErrInvalidQuery error if
+	// the query cannot be parsed
+	SearchJobs(ownerID string, query string) ([]entities.SearchHit, error)
+}
+
+type ReportRenderer interface {
+	// Render writes the report as a self-contained HTML document in the branding of the tenant
+	Render(w io.Writer, tenantID string, report report.Report) error
+}
@@ -3,6 +3,7 @@ package patents
 import (
 	"github.com/labstack/echo/v4"
 
+	"github.com/MyChaOS87/patAi/config"
 	"github.com/MyChaOS87/patAi/internal/api/router"
 	"github.com/MyChaOS87/patAi/internal/authorization"
 	"github.com/MyChaOS87/patAi/pkg/middleware"
@@ -10,6 +11,9 @@ import (
 
 const (
 	patentsBaseURI     = "patents"
+	batchesBaseURI     = "batches"
+	enginesBaseURI     = "engines"
+	historiesBaseURI   = "histories"
 	contextIdentityKey = "patents-identity"
 )
 
@@ -17,19 +21,36 @@ var _ router.Router = &patents{}
 
 type Handler interface {
 	GetPatentValuationJobs() echo.HandlerFunc
+	ExportPatentValuationJobs() echo.HandlerFunc
 	GetPatentValuationJobByID() echo.HandlerFunc
+	GetPatentValuationDocument() echo.HandlerFunc
+	GetPatentClaims() echo.HandlerFunc
+	GetPatentReport() echo.HandlerFunc
+	GetPatentRevaluations() echo.HandlerFunc
+	GetSimilarPatents() echo.HandlerFunc
 	CreatePatentValuationJob() echo.HandlerFunc
+	UpdatePatentValuationJobMetadata() echo.HandlerFunc
+	RevaluePatentValuationJob() echo.HandlerFunc
+	CreatePatentValuationBatch() echo.HandlerFunc
+	GetPatentValuationBatchByID() echo.HandlerFunc
+	GetEngines() echo.HandlerFunc
+	GetPatentHistory() echo.HandlerFunc
+	GetPatentDiff() echo.HandlerFunc
 }
 
 type patents struct {
+	cfg                   *config.APIConfig
 	authorizationProvider middleware.AuthorizationProvider[authorization.Identity]
 	handler               Handler
 }
 
 func NewPatentsRouter(
-	authorizationProvider middleware.AuthorizationProvider[authorization.Identity], handler Handler,
+	cfg *config.APIConfig,
+	authorizationProvider middleware.AuthorizationProvider[authorization.Identity],
+	handler Handler,
 ) router.Router {
 	return &patents{
+		cfg:                   cfg,
 		authorizationProvider: authorizationProvider,
 		handler:               handler,
 	}
@@ -40,6 +61,44 @@ func (p *patents) AddRoutes(baseGroup *echo.Group) {
 	patentsGroup.Use(middleware.APIKey(p.authorizationProvider, contextIdentityKey))
 
 	patentsGroup.GET("", p.handler.GetPatentValuationJobs())
+	patentsGroup.GET("/export", p.handler.ExportPatentValuationJobs())
 	patentsGroup.GET("/:id", p.handler.GetPatentValuationJobByID())
-	patentsGroup.POST("", p.handler.CreatePatentValuationJob())
+	patentsGroup.GET("/:id/document", p.handler.GetPatentValuationDocument())
+	patentsGroup.GET("/:id/claims", p.handler.GetPatentClaims())
+	patentsGroup.GET("/:id/report", p.handler.GetPatentReport())
+	patentsGroup.GET("/:id/revaluations", p.handler.GetPatentRevaluations())
+	patentsGroup.GET("/:id/similar", p.handler.GetSimilarPatents())
+	patentsGroup.POST("", p.handler.CreatePatentValuationJob(),
+		middleware.Idempotency(p.cfg.IdempotencyKeyTTL, identityScope))
+	patentsGroup.PATCH("/:id", p.handler.UpdatePatentValuationJobMetadata())
+	patentsGroup.POST("/batch", p.handler.CreatePatentValuationBatch(),
+		middleware.Idempotency(p.cfg.IdempotencyKeyTTL, identityScope))
+	patentsGroup.POST("/:id/revalue", p.handler.RevaluePatentValuationJob(),
+		middleware.Idempotency(p.cfg.IdempotencyKeyTTL, identityScope))
+
+	batchesGroup := baseGroup.Group(batchesBaseURI)
+	batchesGroup.Use(middleware.APIKey(p.authorizationProvider, contextIdentityKey))
+
+	batchesGroup.GET("/:id", p.handler.GetPatentValuationBatchByID())
+
+	enginesGroup := baseGroup.Group(enginesBaseURI)
+	enginesGroup.Use(middleware.APIKey(p.authorizationProvider, contextIdentityKey))
+
+	enginesGroup.GET("", p.handler.GetEngines())
+
+	historiesGroup := baseGroup.Group(historiesBaseURI)
+	historiesGroup.Use(middleware.APIKey(p.authorizationProvider, contextIdentityKey))
+
+	historiesGroup.GET("/:key", p.handler.GetPatentHistory())
+	historiesGroup.GET("/:key/diff", p.handler.GetPatentDiff())
+}
+
+// identityScope keeps Idempotency-Keys apart per identity.
+func identityScope(c echo.Context) (string, error) {
+	identity, err := getIdentityFromContext(c)
+	if err != nil {
+		return "", err
+	}
+
+	return identity.GetID(), nil
 }
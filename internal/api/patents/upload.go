@@ -0,0 +1,143 @@
+package patents
+
+import (
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patenttext"
+	"github.com/MyChaOS87/patAi/internal/pdftext"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const (
+	uploadFileField    = "file"
+	mimeApplicationPDF = "application/pdf"
+)
+
+var (
+	errNoText           = errors.New("no text found in the document, scanned documents without a text layer are not supported")
+	errDocumentTooLarge = errors.New("document too large")
+)
+
+// readUpload reads a multipart form with the patent as PDF in the file field, optionally completed by metadata
+// fields that take precedence over what is recognized in the text.
+func (h *handler) readUpload(c echo.Context) (submission, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return submission{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	files := form.File[uploadFileField]
+	if len(files) != 1 {
+		return submission{}, problem.Detailed(errInvalidPatent, "expected exactly one %q part", uploadFileField)
+	}
+
+	content, err := readFile(files[0])
+	if err != nil {
+		return submission{}, err
+	}
+
+	if !pdftext.IsPDF(content) {
+		return submission{}, problem.Detailed(errUnsupportedPatentDocument, "only PDF files are supported")
+	}
+
+	text, err := pdftext.Extract(content, pdftext.WithMaxDecodedSize(h.maxPDFDecodedSize))
+	if errors.Is(err, pdftext.ErrEncrypted) {
+		return submission{}, problem.Detailed(errUnsupportedPatentDocument, "%v", err)
+	} else if errors.Is(err, pdftext.ErrTooLarge) {
+		return submission{}, problem.Detailed(errDocumentTooLarge, "%v", err)
+	} else if err != nil {
+		return submission{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return submission{}, problem.Detailed(errInvalidPatent, "%v", errNoText)
+	}
+
+	patent := patenttext.Segment(text)
+	if err := applyMetadata(&patent, form.Value); err != nil {
+		return submission{}, problem.Detailed(errInvalidPatent, "%v", err)
+	}
+
+	return submission{
+		content: patent.Text(),
+		patent:  &patent,
+		document: &entities.Document{
+			FileName:    files[0].Filename,
+			ContentType: mimeApplicationPDF,
+			Content:     content,
+		},
+	}, nil
+}
+
+func readFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open upload")
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read upload")
+	}
+
+	return content, nil
+}
+
+// applyMetadata sets the patent fields given in the form, lists may be repeated or comma separated.
+func applyMetadata(patent *entities.Patent, values map[string][]string) error {
+	first := func(key string) string {
+		if value := values[key]; len(value) > 0 {
+			return strings.TrimSpace(value[0])
+		}
+
+		return ""
+	}
+
+	if title := first("title"); title != "" {
+		patent.Title = title
+	}
+
+	if publicationNumber := first("publicationNumber"); publicationNumber != "" {
+		patent.PublicationNumber = publicationNumber
+	}
+
+	if jurisdiction := first("jurisdiction"); jurisdiction != "" {
+		patent.Jurisdiction = jurisdiction
+	}
+
+	if priorityDate := first("priorityDate"); priorityDate != "" {
+		date, err := time.Parse(time.DateOnly, priorityDate)
+		if err != nil {
+			return errMalformedPriorityDate
+		}
+
+		patent.PriorityDate = date
+	}
+
+	patent.CPCClasses = listValue(values["cpcClasses"])
+	patent.IPCClasses = listValue(values["ipcClasses"])
+
+	return nil
+}
+
+func listValue(values []string) []string {
+	var result []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
@@ -1,45 +1,202 @@
 package patents
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/MyChaOS87/patAi/config"
 	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/claims"
 	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patenttext"
+	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
 )
 
 var ErrValuationUseCase = errors.New("valuation use case error")
 
 type ValuationJobUseCase interface {
-	GetPatentValuationJobsByIdentity(identity authorization.Identity) ([]entities.EvaluationJob, error)
+	// GetPatentValuationJobsByIdentity returns the jobs of the identity selected by the filter in creation order
+	GetPatentValuationJobsByIdentity(
+		identity authorization.Identity, filter JobFilter,
+	) ([]entities.EvaluationJob, error)
+	// EachPatentValuationJobByIdentity calls fn with each job of the identity selected by the filter in creation order,
+	// one job at a time; it stops at and returns the first error of fn
+	EachPatentValuationJobByIdentity(
+		identity authorization.Identity, filter JobFilter, fn func(entities.EvaluationJob) error,
+	) error
 	GetPatentValuationJobByIdentityAndID(identity authorization.Identity, ID uuid.UUID) (entities.EvaluationJob, error)
+	// SearchPatentValuationJobsByIdentity returns the jobs of the identity matching the full-text query, best matches
+	// first, restricted to those selected by the filter; returns an ErrInvalidQuery error for malformed queries and an
+	// ErrSearchDisabled error without search
+	SearchPatentValuationJobsByIdentity(
+		identity authorization.Identity, query string, filter JobFilter,
+	) ([]SearchResult, error)
+	// UpdatePatentValuationJobMetadata applies the patch to the labels, tags and note of the job; returns an
+	// ErrInvalidMetadata error if the resulting metadata is invalid
+	UpdatePatentValuationJobMetadata(
+		identity authorization.Identity, ID uuid.UUID, patch MetadataPatch,
+	) (entities.EvaluationJob, error)
+	// GetPatentValuationDocumentByIdentityAndID returns the file the job was submitted as, or an ErrDocumentNotFound
+	// error for jobs submitted otherwise
+	GetPatentValuationDocumentByIdentityAndID(identity authorization.Identity, ID uuid.UUID) (entities.Document, error)
+	// GetPatentRevaluationsByIdentityAndID returns the job the given one re-values, or the given one if it is no
+	// re-valuation, followed by all of its re-valuations in creation order
+	GetPatentRevaluationsByIdentityAndID(identity authorization.Identity, ID uuid.UUID) ([]entities.EvaluationJob, error)
+	// GetPatentClaimsByIdentityAndID analyzes the claims of the job's patent, taken from the structured submission or
+	// parsed from the content
+	GetPatentClaimsByIdentityAndID(identity authorization.Identity, ID uuid.UUID) (claims.Analysis, error)
+
+	// GetPatentHistoryByIdentity returns all jobs of the logical patent in creation order, re-valuations included;
+	// returns an ErrPatentNotFound error if there are none
+	GetPatentHistoryByIdentity(identity authorization.Identity, patentKey string) ([]entities.EvaluationJob, error)
+	// GetPatentDiffByIdentity compares two submissions of the logical patent, uuid.Nil selects the latest submission
+	// for to and the submission before to for from; returns an ErrJobNotFound error if a job does not belong to
+	// the patent and an ErrNoPreviousSubmission error if there is nothing to compare to
+	GetPatentDiffByIdentity(
+		identity authorization.Identity, patentKey string, from uuid.UUID, to uuid.UUID,
+	) (PatentDiff, error)
+
+	// GetSimilarPatentsByIdentityAndID returns up to limit other jobs of the identity most similar to the given job,
+	// most similar first; returns an ErrSimilarityDisabled error without similarity index
+	GetSimilarPatentsByIdentityAndID(identity authorization.Identity, ID uuid.UUID, limit int) ([]SimilarPatent, error)
+
+	// GetEngines returns the engines jobs can be created for and the one chosen if none is requested, no engines if
+	// engines cannot be selected
+	GetEngines() (engines []entities.EngineInfo, defaultEngine entities.EngineInfo)
 
 	// CreatePatentValuationJob creates a new patent valuation job after checking the users quota
 	// returns an ErrQuotaExceeded error if the user has exceeded their quota
-	CreatePatentValuationJob(identity authorization.Identity, content string) (entities.EvaluationJob, error)
+	// and an ErrPriorityNotAllowed error if the priority is above the user's plan
+	CreatePatentValuationJob(identity authorization.Identity, request CreateJobRequest) (entities.EvaluationJob, error)
+	// RevaluePatentValuationJob creates a follow-up job valuing the patent of the given job with another engine,
+	// linked to the original job; returns an ErrSameEngine error if the engine already valued the job and an
+	// ErrUnknownEngine error if there is no such engine, quota and priority are checked as for new jobs
+	RevaluePatentValuationJob(
+		identity authorization.Identity, ID uuid.UUID, request RevalueJobRequest,
+	) (entities.EvaluationJob, error)
+
+	// CreatePatentValuationBatch creates a job per request grouped in a batch, in atomic mode it returns an
+	// ErrQuotaExceeded or ErrPriorityNotAllowed error without creating any job if not all of them are possible,
+	// in best-effort mode such requests are rejected individually
+	CreatePatentValuationBatch(
+		identity authorization.Identity, requests []CreateJobRequest, mode entities.BatchMode,
+	) (entities.Batch, []entities.EvaluationJob, error)
+	// GetPatentValuationBatchByIdentityAndID returns the batch and its jobs in submission order
+	GetPatentValuationBatchByIdentityAndID(
+		identity authorization.Identity, id uuid.UUID,
+	) (entities.Batch, []entities.EvaluationJob, error)
+}
+
+type CreateJobRequest struct {
+	Content  string
+	Priority entities.JobPriority
+	// Fresh forces a new valuation even if a cached result for the content exists
+	Fresh bool
+	// TechnicalField optionally classifies the patent for portfolio statistics, defaults to the CPC subclass of a
+	// structured patent
+	TechnicalField string
+	// Patent is the structured document the content was taken from, nil for plain text submissions
+	Patent *entities.Patent
+	// Document is the uploaded original file, nil unless the patent was submitted as a file
+	Document *entities.Document
+	// Engine and EngineVersion select the engine valuing the job, empty values select the default engine and its
+	// latest version
+	Engine        string
+	EngineVersion string
+	// PatentKey links the job to a logical patent, defaults to the publication number of a structured patent
+	PatentKey string
+	// Metadata are the owner's labels, tags and note for the job
+	Metadata entities.JobMetadata
+}
+
+type RevalueJobRequest struct {
+	Priority entities.JobPriority
+	// Engine and EngineVersion select the engine as for new jobs
+	Engine        string
+	EngineVersion string
+}
+
+// SimilarPatent is a job similar to another one.
+type SimilarPatent struct {
+	Job entities.EvaluationJob
+	// Score is the similarity from 0 (nothing in common) to 1 (same terms)
+	Score float64
+}
+
+// SearchResult is a job matching a full-text query.
+type SearchResult struct {
+	Job   entities.EvaluationJob
+	Score float64
+	// Snippets show the matches per field with the matching terms highlighted
+	Snippets []entities.Snippet
+}
+
+// PatentDiff compares two submissions of a logical patent.
+type PatentDiff struct {
+	PatentKey string
+	From      entities.EvaluationJob
+	To        entities.EvaluationJob
+	Claims    []claims.Change
 }
 
 type valuationJobUseCase struct {
-	queueService QueueService
-	quotaService QuotaService
+	queueService      QueueService
+	quotaService      QuotaService
+	resultCache       ResultCache
+	resultCacheConfig *config.ResultCacheConfig
+	engines           EngineRegistry
+	similarityIndex   SimilarityIndex
+	searchService     SearchService
+	batchService      BatchService
+	maxBatchSize      int
 }
 
-func NewValuationJobUseCase(queueService QueueService, quotaService QuotaService) ValuationJobUseCase {
-	return &valuationJobUseCase{
+func NewValuationJobUseCase(
+	queueService QueueService, quotaService QuotaService, options ...UseCaseOption,
+) ValuationJobUseCase {
+	v := &valuationJobUseCase{
 		queueService: queueService,
 		quotaService: quotaService,
 	}
+
+	for _, opt := range options {
+		opt(v)
+	}
+
+	return v
 }
 
 func (v *valuationJobUseCase) GetPatentValuationJobsByIdentity(
 	identity authorization.Identity,
+	filter JobFilter,
 ) ([]entities.EvaluationJob, error) {
 	res, err := v.queueService.GetJobsByOwnerID(identity.GetID())
 	if err != nil {
 		return nil, errors.Wrap(err, ErrValuationUseCase.Error())
 	}
 
-	return res, nil
+	return filterJobs(res, filter), nil
+}
+
+func (v *valuationJobUseCase) EachPatentValuationJobByIdentity(
+	identity authorization.Identity,
+	filter JobFilter,
+	fn func(entities.EvaluationJob) error,
+) error {
+	err := v.queueService.EachJobByOwnerID(identity.GetID(), func(job entities.EvaluationJob) error {
+		if !filter.Matches(job) {
+			return nil
+		}
+
+		return fn(job)
+	})
+
+	return errors.Wrap(err, ErrValuationUseCase.Error())
 }
 
 func (v *valuationJobUseCase) GetPatentValuationJobByIdentityAndID(
@@ -58,20 +215,483 @@ func (v *valuationJobUseCase) GetPatentValuationJobByIdentityAndID(
 	return job, nil
 }
 
+func (v *valuationJobUseCase) SearchPatentValuationJobsByIdentity(
+	identity authorization.Identity,
+	query string,
+	filter JobFilter,
+) ([]SearchResult, error) {
+	if v.searchService == nil {
+		return nil, ErrSearchDisabled
+	}
+
+	hits, err := v.searchService.SearchJobs(identity.GetID(), query)
+	if errors.Is(err, ErrInvalidQuery) {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	result := make([]SearchResult, 0, len(hits))
+
+	for _, hit := range hits {
+		job, err := v.GetPatentValuationJobByIdentityAndID(identity, hit.JobID)
+		if err != nil {
+			log.Warnf("matching job %s cannot be retrieved: %v", hit.JobID.String(), err)
+
+			continue
+		}
+
+		if !filter.Matches(job) {
+			continue
+		}
+
+		result = append(result, SearchResult{Job: job, Score: hit.Score, Snippets: hit.Snippets})
+	}
+
+	return result, nil
+}
+
+func (v *valuationJobUseCase) UpdatePatentValuationJobMetadata(
+	identity authorization.Identity,
+	id uuid.UUID,
+	patch MetadataPatch,
+) (entities.EvaluationJob, error) {
+	job, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return entities.EvaluationJob{}, err
+	}
+
+	metadata, err := NormalizeMetadata(patch.Apply(job.Metadata))
+	if err != nil {
+		return entities.EvaluationJob{}, err
+	}
+
+	job, err = v.queueService.UpdateJobMetadata(id, metadata)
+	if err != nil {
+		return entities.EvaluationJob{}, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	return job, nil
+}
+
+func (v *valuationJobUseCase) GetPatentValuationDocumentByIdentityAndID(
+	identity authorization.Identity,
+	id uuid.UUID,
+) (entities.Document, error) {
+	job, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return entities.Document{}, err
+	}
+
+	if job.Document == nil {
+		return entities.Document{}, ErrDocumentNotFound
+	}
+
+	return *job.Document, nil
+}
+
+func (v *valuationJobUseCase) GetPatentClaimsByIdentityAndID(
+	identity authorization.Identity,
+	id uuid.UUID,
+) (claims.Analysis, error) {
+	job, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return claims.Analysis{}, err
+	}
+
+	return claims.Analyze(jobClaims(job)), nil
+}
+
+func (v *valuationJobUseCase) GetPatentRevaluationsByIdentityAndID(
+	identity authorization.Identity,
+	id uuid.UUID,
+) ([]entities.EvaluationJob, error) {
+	job, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := job.ID
+	if job.RevaluationOf != uuid.Nil {
+		root = job.RevaluationOf
+	}
+
+	jobs, err := v.queueService.GetJobsByOwnerID(identity.GetID())
+	if err != nil {
+		return nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	result := []entities.EvaluationJob{}
+
+	for _, job := range jobs {
+		if job.ID == root || job.RevaluationOf == root {
+			result = append(result, job)
+		}
+	}
+
+	// the original job comes first as it was created before its re-valuations
+	slices.SortStableFunc(result, func(a, b entities.EvaluationJob) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (v *valuationJobUseCase) GetPatentHistoryByIdentity(
+	identity authorization.Identity,
+	patentKey string,
+) ([]entities.EvaluationJob, error) {
+	patentKey = PatentKey(patentKey)
+
+	jobs, err := v.queueService.GetJobsByOwnerID(identity.GetID())
+	if err != nil {
+		return nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	result := []entities.EvaluationJob{}
+
+	for _, job := range jobs {
+		if patentKey != "" && job.PatentKey == patentKey {
+			result = append(result, job)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, ErrPatentNotFound
+	}
+
+	slices.SortStableFunc(result, func(a, b entities.EvaluationJob) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (v *valuationJobUseCase) GetPatentDiffByIdentity(
+	identity authorization.Identity, patentKey string, from uuid.UUID, to uuid.UUID,
+) (PatentDiff, error) {
+	history, err := v.GetPatentHistoryByIdentity(identity, patentKey)
+	if err != nil {
+		return PatentDiff{}, err
+	}
+
+	// re-valuations are no submissions of their own, but may be compared explicitly
+	submissions := slices.DeleteFunc(slices.Clone(history), func(job entities.EvaluationJob) bool {
+		return job.RevaluationOf != uuid.Nil
+	})
+
+	toJob, err := findJob(history, submissions, to, len(submissions)-1)
+	if err != nil {
+		return PatentDiff{}, err
+	}
+
+	// from defaults to the last submission before to
+	previous := -1
+
+	for i, job := range submissions {
+		if job.CreatedAt.Before(toJob.CreatedAt) {
+			previous = i
+		}
+	}
+
+	fromJob, err := findJob(history, submissions, from, previous)
+	if err != nil {
+		return PatentDiff{}, err
+	}
+
+	return PatentDiff{
+		PatentKey: PatentKey(patentKey),
+		From:      fromJob,
+		To:        toJob,
+		Claims:    claims.Diff(jobClaims(fromJob), jobClaims(toJob)),
+	}, nil
+}
+
+// findJob returns the job with the given ID from the history or, for uuid.Nil, the submission at the given index.
+func findJob(
+	history []entities.EvaluationJob, submissions []entities.EvaluationJob, id uuid.UUID, index int,
+) (entities.EvaluationJob, error) {
+	if id == uuid.Nil {
+		if index < 0 || index >= len(submissions) {
+			return entities.EvaluationJob{}, ErrNoPreviousSubmission
+		}
+
+		return submissions[index], nil
+	}
+
+	i := slices.IndexFunc(history, func(job entities.EvaluationJob) bool {
+		return job.ID == id
+	})
+	if i < 0 {
+		return entities.EvaluationJob{}, problem.Detailed(ErrJobNotFound, "job %s of patent", id.String())
+	}
+
+	return history[i], nil
+}
+
+func (v *valuationJobUseCase) GetSimilarPatentsByIdentityAndID(
+	identity authorization.Identity,
+	id uuid.UUID,
+	limit int,
+) ([]SimilarPatent, error) {
+	if v.similarityIndex == nil {
+		return nil, ErrSimilarityDisabled
+	}
+
+	job, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// re-valuations are not indexed, their content is that of the original job
+	indexed := job.ID
+	if job.RevaluationOf != uuid.Nil {
+		indexed = job.RevaluationOf
+	}
+
+	matches, err := v.similarityIndex.Similar(identity.GetID(), indexed, limit)
+	if errors.Is(err, entities.ErrJobNotIndexed) {
+		return nil, problem.Detailed(ErrJobNotFound, "%v", err)
+	} else if err != nil {
+		return nil, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	result := make([]SimilarPatent, 0, len(matches))
+
+	for _, match := range matches {
+		similar, err := v.GetPatentValuationJobByIdentityAndID(identity, match.JobID)
+		if err != nil {
+			log.Warnf("similar job %s cannot be retrieved: %v", match.JobID.String(), err)
+
+			continue
+		}
+
+		result = append(result, SimilarPatent{Job: similar, Score: match.Score})
+	}
+
+	return result, nil
+}
+
+func (v *valuationJobUseCase) GetEngines() ([]entities.EngineInfo, entities.EngineInfo) {
+	if v.engines == nil {
+		return []entities.EngineInfo{}, entities.EngineInfo{}
+	}
+
+	return v.engines.Engines(), v.engines.Default()
+}
+
+// jobClaims returns the claims of a structured submission, otherwise those found in the content.
+func jobClaims(job entities.EvaluationJob) []entities.Claim {
+	if job.Patent != nil && len(job.Patent.Claims) > 0 {
+		return job.Patent.Claims
+	}
+
+	return patenttext.Claims(job.PatentContent)
+}
+
 func (v *valuationJobUseCase) CreatePatentValuationJob(
-	identity authorization.Identity, content string,
+	identity authorization.Identity, request CreateJobRequest,
+) (entities.EvaluationJob, error) {
+	options, err := v.jobOptions(identity, request)
+	if err != nil {
+		return entities.EvaluationJob{}, err
+	}
+
+	var token uuid.UUID
+
+	if v.consumesQuota(options) {
+		token, err = v.quotaService.GetQuotaToken(identity.GetID())
+		if err != nil {
+			return entities.EvaluationJob{}, errors.Wrap(err, ErrCouldNotRetrieveQuota.Error())
+		}
+	}
+
+	return v.enqueue(identity.GetID(), request.Content, options, token)
+}
+
+func (v *valuationJobUseCase) RevaluePatentValuationJob(
+	identity authorization.Identity, id uuid.UUID, request RevalueJobRequest,
+) (entities.EvaluationJob, error) {
+	original, err := v.GetPatentValuationJobByIdentityAndID(identity, id)
+	if err != nil {
+		return entities.EvaluationJob{}, err
+	}
+
+	options, err := v.jobOptions(identity, CreateJobRequest{
+		Content:        original.PatentContent,
+		Priority:       request.Priority,
+		TechnicalField: original.TechnicalField,
+		Patent:         original.Patent,
+		Document:       original.Document,
+		Engine:         request.Engine,
+		EngineVersion:  request.EngineVersion,
+		PatentKey:      original.PatentKey,
+		Metadata:       original.Metadata,
+	})
+	if err != nil {
+		return entities.EvaluationJob{}, err
+	}
+
+	if options.Engine == original.Engine {
+		return entities.EvaluationJob{}, problem.Detailed(ErrSameEngine, "%v", options.Engine)
+	}
+
+	// re-valuations of re-valuations are linked to the original job, so that all values can be compared at once
+	options.RevaluationOf = original.ID
+	if original.RevaluationOf != uuid.Nil {
+		options.RevaluationOf = original.RevaluationOf
+	}
+
+	var token uuid.UUID
+
+	if v.consumesQuota(options) {
+		token, err = v.quotaService.GetQuotaToken(identity.GetID())
+		if err != nil {
+			return entities.EvaluationJob{}, errors.Wrap(err, ErrCouldNotRetrieveQuota.Error())
+		}
+	}
+
+	return v.enqueue(identity.GetID(), original.PatentContent, options, token)
+}
+
+// jobOptions checks the request against the identity's plan, resolves its engine, normalizes its metadata and looks
+// up a cached result for its content.
+func (v *valuationJobUseCase) jobOptions(
+	identity authorization.Identity, request CreateJobRequest,
+) (entities.JobOptions, error) {
+	plan := identity.GetPlan()
+	if request.Priority > plan.MaxPriority {
+		return entities.JobOptions{}, ErrPriorityNotAllowed
+	}
+
+	engine, err := v.resolveEngine(request.Engine, request.EngineVersion)
+	if err != nil {
+		return entities.JobOptions{}, err
+	}
+
+	metadata, err := NormalizeMetadata(request.Metadata)
+	if err != nil {
+		return entities.JobOptions{}, err
+	}
+
+	options := entities.JobOptions{
+		Priority:         request.Priority,
+		SchedulingWeight: plan.SchedulingWeight,
+		ContentHash:      ContentHash(request.Content),
+		TechnicalField:   request.TechnicalField,
+		Patent:           request.Patent,
+		Document:         request.Document,
+		Engine:           engine,
+		PatentKey:        PatentKey(request.PatentKey),
+		Metadata:         metadata,
+	}
+
+	if options.PatentKey == "" && request.Patent != nil {
+		options.PatentKey = PatentKey(request.Patent.PublicationNumber)
+	}
+
+	if options.TechnicalField == "" && request.Patent != nil && len(request.Patent.CPCClasses) > 0 {
+		options.TechnicalField = cpcSubclass(request.Patent.CPCClasses[0])
+	}
+
+	if !request.Fresh {
+		options.CachedResult = v.lookupCachedResult(identity.GetID(), options.ContentHash, engine)
+	}
+
+	return options, nil
+}
+
+// resolveEngine returns the zero EngineInfo, leaving the choice to the workers, if engines cannot be selected.
+func (v *valuationJobUseCase) resolveEngine(name string, version string) (entities.EngineInfo, error) {
+	if v.engines == nil {
+		if name != "" || version != "" {
+			return entities.EngineInfo{}, problem.Detailed(ErrUnknownEngine, "engines cannot be selected")
+		}
+
+		return entities.EngineInfo{}, nil
+	}
+
+	engine, err := v.engines.Resolve(name, version)
+	if err != nil {
+		return entities.EngineInfo{}, errors.Wrap(err, ErrValuationUseCase.Error())
+	}
+
+	return engine, nil
+}
+
+func (v *valuationJobUseCase) consumesQuota(options entities.JobOptions) bool {
+	return options.CachedResult == nil || v.resultCacheConfig.ConsumeQuota
+}
+
+// enqueue enqueues and indexes the job and hands the quota token back if that fails, uuid.Nil stands for no token.
+func (v *valuationJobUseCase) enqueue(
+	ownerID string, content string, options entities.JobOptions, token uuid.UUID,
 ) (entities.EvaluationJob, error) {
-	token, err := v.quotaService.GetQuotaToken(identity.GetID())
+	job, err := v.enqueueJob(ownerID, content, options, token)
 	if err != nil {
-		return entities.EvaluationJob{}, errors.Wrap(err, ErrCouldNotRetrieveQuota.Error())
+		return entities.EvaluationJob{}, err
 	}
 
-	job, err := v.queueService.EnqueueJob(identity.GetID(), content)
+	v.indexSimilarity(job)
+
+	return job, nil
+}
+
+// enqueueJob enqueues the job without indexing it, the token is returned if the job cannot be enqueued.
+func (v *valuationJobUseCase) enqueueJob(
+	ownerID string, content string, options entities.JobOptions, token uuid.UUID,
+) (entities.EvaluationJob, error) {
+	job, err := v.queueService.EnqueueJob(ownerID, content, options)
 	if err != nil {
-		v.quotaService.ReturnQuotaToken(token)
+		if token != uuid.Nil {
+			v.quotaService.ReturnQuotaToken(token)
+		}
 
 		return entities.EvaluationJob{}, errors.Wrap(err, ErrValuationUseCase.Error())
 	}
 
 	return job, nil
 }
+
+func (v *valuationJobUseCase) indexSimilarity(job entities.EvaluationJob) {
+	if v.similarityIndex != nil && job.RevaluationOf == uuid.Nil {
+		v.similarityIndex.Add(job)
+	}
+}
+
+// lookupCachedResult returns a recent result for the content hash by the engine, nil if the cache is disabled, the
+// engine is unknown or there is no such result.
+func (v *valuationJobUseCase) lookupCachedResult(
+	ownerID string, contentHash string, engine entities.EngineInfo,
+) *entities.CachedResult {
+	if v.resultCache == nil || v.resultCacheConfig.TTL <= 0 || engine == (entities.EngineInfo{}) {
+		return nil
+	}
+
+	result, ok, err := v.resultCache.GetCachedResult(
+		ownerID, contentHash, engine, time.Now().Add(-v.resultCacheConfig.TTL),
+	)
+	if err != nil {
+		log.Warnf("result cache lookup failed, valuing again: %v", err)
+
+		return nil
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return &result
+}
+
+// cpcSubclass shortens a CPC symbol like "H04L 9/32" to its subclass "H04L".
+func cpcSubclass(class string) string {
+	const subclassLength = 4
+
+	class = strings.TrimSpace(class)
+	if len(class) > subclassLength {
+		return class[:subclassLength]
+	}
+
+	return class
+}
@@ -3,15 +3,19 @@ package patents_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/MyChaOS87/patAi/config"
 	"github.com/MyChaOS87/patAi/internal/api/patents"
 	"github.com/MyChaOS87/patAi/internal/api/patents/mocks"
 	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/claims"
 	"github.com/MyChaOS87/patAi/internal/entities"
 )
 
@@ -25,6 +29,14 @@ func (i *identity) GetID() string {
 	return i.id
 }
 
+func (i *identity) IsOperator() bool {
+	return false
+}
+
+func (i *identity) GetPlan() authorization.Plan {
+	return authorization.PlanFree
+}
+
 func Test_valuationJobUseCase_GetPatentValuationJobsByIdentityAndID(t *testing.T) {
 	t.Parallel()
 
@@ -119,10 +131,153 @@ func Test_valuationJobUseCase_GetPatentValuationJobsByIdentityAndID(t *testing.T
 	}
 }
 
+func Test_valuationJobUseCase_GetPatentValuationDocumentByIdentityAndID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		document = entities.Document{
+			FileName:    "EP1234567B1.pdf",
+			ContentType: "application/pdf",
+			Content:     []byte("%PDF-1.4"),
+		}
+		uploadedJob = entities.EvaluationJob{
+			ID:       uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb"),
+			OwnerID:  "Alice",
+			Document: &document,
+		}
+		textJob = entities.EvaluationJob{
+			ID:      uuid.MustParse("c32c6f83-b06b-4df5-9def-36e8ee5e6cb7"),
+			OwnerID: "Alice",
+		}
+	)
+
+	testCases := []struct {
+		name     string
+		job      entities.EvaluationJob
+		identity authorization.Identity
+		want     entities.Document
+		wantErr  error
+	}{
+		{
+			name:     "Alice gets the document of her upload",
+			job:      uploadedJob,
+			identity: &identity{id: "Alice"},
+			want:     document,
+		},
+		{
+			name:     "text submissions have no document",
+			job:      textJob,
+			identity: &identity{id: "Alice"},
+			wantErr:  patents.ErrDocumentNotFound,
+		},
+		{
+			name:     "Bob does not get Alice's document",
+			job:      uploadedJob,
+			identity: &identity{id: "Bob"},
+			wantErr:  patents.ErrJobNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("GetJobByID", tc.job.ID).Return(tc.job, nil).Once()
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			got, err := useCase.GetPatentValuationDocumentByIdentityAndID(tc.identity, tc.job.ID)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_GetPatentClaimsByIdentityAndID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+
+	testCases := []struct {
+		name        string
+		job         entities.EvaluationJob
+		wantNumbers []int
+		wantMetrics claims.Metrics
+	}{
+		{
+			name: "structured patents keep their claim dependencies",
+			job: entities.EvaluationJob{
+				ID:            id,
+				OwnerID:       "Alice",
+				PatentContent: "ignored",
+				Patent: &entities.Patent{Claims: []entities.Claim{
+					{Number: 1, Text: "A method comprising verifying a signature."},
+					{Number: 2, Text: "A method as above, using ECDSA.", DependsOn: []int{1}},
+				}},
+			},
+			wantNumbers: []int{1},
+			wantMetrics: claims.Metrics{
+				Claims: 2, IndependentClaims: 1, DependentClaims: 1, Breadth: 1, Depth: 2,
+				ShortestIndependentClaimWords: 6, Categories: 1,
+			},
+		},
+		{
+			name: "plain text content is parsed",
+			job: entities.EvaluationJob{
+				ID:      id,
+				OwnerID: "Alice",
+				PatentContent: "Title\nClaims\n1. A system comprising a processor.\n" +
+					"2. The system of claim 1, comprising memory.\n3. A method of computing.",
+			},
+			wantNumbers: []int{1, 3},
+			wantMetrics: claims.Metrics{
+				Claims: 3, IndependentClaims: 2, DependentClaims: 1, Breadth: 2, Depth: 2,
+				ShortestIndependentClaimWords: 4, Categories: 2,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("GetJobByID", id).Return(tc.job, nil).Once()
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			analysis, err := useCase.GetPatentClaimsByIdentityAndID(&identity{id: "Alice"}, id)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantMetrics, analysis.Metrics)
+
+			numbers := make([]int, 0, len(analysis.Roots))
+			for _, root := range analysis.Roots {
+				numbers = append(numbers, root.Claim.Number)
+			}
+
+			assert.Equal(t, tc.wantNumbers, numbers)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_valuationJobUseCase_CreatePatentValuationJob(t *testing.T) {
 	t.Parallel()
 
 	content := "This is a patent content"
+	contentHash := patents.ContentHash(content)
+	engine := entities.EngineInfo{Name: "simulation", Version: "1.0.0"}
 
 	alicesJob := entities.EvaluationJob{
 		ID:                  uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb"),
@@ -132,50 +287,190 @@ func Test_valuationJobUseCase_CreatePatentValuationJob(t *testing.T) {
 		Value:               0,
 	}
 
+	cachedResult := entities.CachedResult{
+		JobID:  uuid.MustParse("c32c6f83-b06b-4df5-9def-36e8ee5e6cb7"),
+		Value:  42,
+		Engine: engine,
+	}
+
+	alicesCachedJob := entities.EvaluationJob{
+		ID:                  uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb"),
+		OwnerID:             "Alice",
+		EvaluationJobStatus: entities.EvaluationJobStatusFinished,
+		PatentContent:       content,
+		Value:               42,
+		Engine:              engine,
+		Cached:              true,
+	}
+
+	freeNormal := entities.JobOptions{
+		Priority:         entities.JobPriorityNormal,
+		SchedulingWeight: 1,
+		ContentHash:      contentHash,
+		Engine:           engine,
+	}
+
+	structuredPatent := entities.Patent{
+		Title:      "Patent",
+		Claims:     []entities.Claim{{Number: 1, Text: content}},
+		CPCClasses: []string{"H04L 9/32", "G06Q 50/18"},
+	}
+
+	freeNormalCached := freeNormal
+	freeNormalCached.CachedResult = &cachedResult
+
+	cacheMiss := func(resultCache *mocks.ResultCache) {
+		resultCache.On("GetCachedResult", "Alice", contentHash, engine, mock.Anything).
+			Return(entities.CachedResult{}, false, nil).Once()
+	}
+
 	testCases := []struct {
 		name        string
-		preparation func(*mocks.QueueService, *mocks.QuotaService)
+		preparation func(*mocks.QueueService, *mocks.QuotaService, *mocks.ResultCache)
 		identity    authorization.Identity
+		request     patents.CreateJobRequest
 		want        entities.EvaluationJob
 		wantErr     error
 	}{
 		{
 			name: "Alice creates a job",
-			preparation: func(queueService *mocks.QueueService, quotaService *mocks.QuotaService) {
-				queueService.On("EnqueueJob", "Alice", content).Return(alicesJob, nil).Once()
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, resultCache *mocks.ResultCache,
+			) {
+				cacheMiss(resultCache)
+				queueService.On("EnqueueJob", "Alice", content, freeNormal).Return(alicesJob, nil).Once()
 				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
 			},
 			identity: &identity{
 				id: "Alice",
 			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal},
 			want:    alicesJob,
 			wantErr: nil,
 		},
 		{
 			name: "Alice is over quota",
-			preparation: func(_ *mocks.QueueService, quotaService *mocks.QuotaService) {
+			preparation: func(_ *mocks.QueueService, quotaService *mocks.QuotaService, resultCache *mocks.ResultCache) {
+				cacheMiss(resultCache)
 				quotaService.On("GetQuotaToken", "Alice").Return(uuid.Nil, patents.ErrQuotaExceeded).Once()
 			},
 			identity: &identity{
 				id: "Alice",
 			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal},
 			want:    entities.EvaluationJob{},
 			wantErr: patents.ErrQuotaExceeded,
 		},
 		{
 			name: "Quota is returned if enqueue fails",
-			preparation: func(queueService *mocks.QueueService, quotaService *mocks.QuotaService) {
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, resultCache *mocks.ResultCache,
+			) {
 				uuid := uuid.MustParse("e9f4ae48-a8bb-4c86-8530-f5756143480e")
-				queueService.On("EnqueueJob", "Alice", content).Return(entities.EvaluationJob{}, errFoo).Once()
+				cacheMiss(resultCache)
+				queueService.On("EnqueueJob", "Alice", content, freeNormal).Return(entities.EvaluationJob{}, errFoo).Once()
 				quotaService.On("GetQuotaToken", "Alice").Return(uuid, nil).Once()
 				quotaService.On("ReturnQuotaToken", uuid).Return().Once()
 			},
 			identity: &identity{
 				id: "Alice",
 			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal},
 			want:    entities.EvaluationJob{},
 			wantErr: errFoo,
 		},
+		{
+			name:        "Alice's plan does not allow high priority",
+			preparation: func(*mocks.QueueService, *mocks.QuotaService, *mocks.ResultCache) {},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityHigh},
+			want:    entities.EvaluationJob{},
+			wantErr: patents.ErrPriorityNotAllowed,
+		},
+		{
+			name: "Cached result finishes the job without quota",
+			preparation: func(queueService *mocks.QueueService, _ *mocks.QuotaService, resultCache *mocks.ResultCache) {
+				resultCache.On("GetCachedResult", "Alice", contentHash, engine, mock.Anything).Return(cachedResult, true, nil).Once()
+				queueService.On("EnqueueJob", "Alice", content, freeNormalCached).Return(alicesCachedJob, nil).Once()
+			},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal},
+			want:    alicesCachedJob,
+			wantErr: nil,
+		},
+		{
+			name: "Structured patent is classified by its CPC subclass",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, resultCache *mocks.ResultCache,
+			) {
+				options := freeNormal
+				options.Patent = &structuredPatent
+				options.TechnicalField = "H04L"
+
+				cacheMiss(resultCache)
+				queueService.On("EnqueueJob", "Alice", content, options).Return(alicesJob, nil).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+			},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{
+				Content: content, Priority: entities.JobPriorityNormal, Patent: &structuredPatent,
+			},
+			want:    alicesJob,
+			wantErr: nil,
+		},
+		{
+			name: "Structured patent is linked by its publication number without kind code",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, resultCache *mocks.ResultCache,
+			) {
+				patent := entities.Patent{PublicationNumber: "EP 1234567 A1"}
+				options := freeNormal
+				options.Patent = &patent
+				options.PatentKey = "EP1234567"
+
+				cacheMiss(resultCache)
+				queueService.On("EnqueueJob", "Alice", content, options).Return(alicesJob, nil).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+			},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{
+				Content: content, Priority: entities.JobPriorityNormal,
+				Patent: &entities.Patent{PublicationNumber: "EP 1234567 A1"},
+			},
+			want:    alicesJob,
+			wantErr: nil,
+		},
+		{
+			name: "Fresh valuation bypasses the cache",
+			preparation: func(queueService *mocks.QueueService, quotaService *mocks.QuotaService, _ *mocks.ResultCache) {
+				queueService.On("EnqueueJob", "Alice", content, freeNormal).Return(alicesJob, nil).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+			},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal, Fresh: true},
+			want:    alicesJob,
+			wantErr: nil,
+		},
+		{
+			name:        "Unknown engine is rejected",
+			preparation: func(*mocks.QueueService, *mocks.QuotaService, *mocks.ResultCache) {},
+			identity: &identity{
+				id: "Alice",
+			},
+			request: patents.CreateJobRequest{Content: content, Priority: entities.JobPriorityNormal, Engine: "unknown"},
+			want:    entities.EvaluationJob{},
+			wantErr: patents.ErrUnknownEngine,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -185,12 +480,18 @@ func Test_valuationJobUseCase_CreatePatentValuationJob(t *testing.T) {
 
 			queueService := new(mocks.QueueService)
 			quotaService := new(mocks.QuotaService)
+			resultCache := new(mocks.ResultCache)
+			engines := new(mocks.EngineRegistry)
 
-			tc.preparation(queueService, quotaService)
+			tc.preparation(queueService, quotaService, resultCache)
+			engines.On("Resolve", "", "").Return(engine, nil).Maybe()
+			engines.On("Resolve", "unknown", "").Return(entities.EngineInfo{}, patents.ErrUnknownEngine).Maybe()
 
-			useCase := patents.NewValuationJobUseCase(queueService, quotaService)
+			useCase := patents.NewValuationJobUseCase(queueService, quotaService,
+				patents.WithResultCache(resultCache, &config.ResultCacheConfig{TTL: time.Hour}),
+				patents.WithEngines(engines))
 
-			job, err := useCase.CreatePatentValuationJob(tc.identity, content)
+			job, err := useCase.CreatePatentValuationJob(tc.identity, tc.request)
 			if tc.wantErr != nil {
 				assert.ErrorIs(t, err, tc.wantErr)
 			} else {
@@ -201,6 +502,820 @@ func Test_valuationJobUseCase_CreatePatentValuationJob(t *testing.T) {
 
 			queueService.AssertExpectations(t)
 			quotaService.AssertExpectations(t)
+			resultCache.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_RevaluePatentValuationJob(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+	rootID := uuid.MustParse("c32c6f83-b06b-4df5-9def-36e8ee5e6cb7")
+	v1 := entities.EngineInfo{Name: "simulation", Version: "1.0.0"}
+	v2 := entities.EngineInfo{Name: "simulation", Version: "2.0.0"}
+
+	original := entities.EvaluationJob{
+		ID:                  id,
+		OwnerID:             "Alice",
+		EvaluationJobStatus: entities.EvaluationJobStatusFinished,
+		PatentContent:       "content",
+		TechnicalField:      "H04L",
+		Engine:              v1,
+		Metadata:            entities.JobMetadata{Labels: map[string]string{"matter": "M-1"}, Tags: []string{"urgent"}},
+	}
+
+	revaluation := original
+	revaluation.RevaluationOf = rootID
+
+	testCases := []struct {
+		name              string
+		job               entities.EvaluationJob
+		engineVersion     string
+		wantRevaluationOf uuid.UUID
+		wantErr           error
+	}{
+		{
+			name:              "follow-up job is linked to the original job",
+			job:               original,
+			engineVersion:     "2.0.0",
+			wantRevaluationOf: id,
+		},
+		{
+			name:              "re-valuation of a re-valuation is linked to the original job",
+			job:               revaluation,
+			engineVersion:     "2.0.0",
+			wantRevaluationOf: rootID,
+		},
+		{
+			name:          "same engine is rejected",
+			job:           original,
+			engineVersion: "1.0.0",
+			wantErr:       patents.ErrSameEngine,
+		},
+		{
+			name:          "other owner's job is not found",
+			job:           entities.EvaluationJob{ID: id, OwnerID: "Bob"},
+			engineVersion: "2.0.0",
+			wantErr:       patents.ErrJobNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			quotaService := new(mocks.QuotaService)
+			engines := new(mocks.EngineRegistry)
+
+			queueService.On("GetJobByID", id).Return(tc.job, nil).Once()
+			engines.On("Resolve", "", "1.0.0").Return(v1, nil).Maybe()
+			engines.On("Resolve", "", "2.0.0").Return(v2, nil).Maybe()
+
+			want := entities.EvaluationJob{}
+
+			if tc.wantErr == nil {
+				want = entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", RevaluationOf: tc.wantRevaluationOf}
+
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+				queueService.On("EnqueueJob", "Alice", "content", mock.MatchedBy(func(options entities.JobOptions) bool {
+					return options.Engine == v2 && options.RevaluationOf == tc.wantRevaluationOf &&
+						options.TechnicalField == "H04L" && assert.ObjectsAreEqual(original.Metadata, options.Metadata)
+				})).Return(want, nil).Once()
+			}
+
+			// re-valuations must not be indexed, the mock fails on any call
+			similarityIndex := new(mocks.SimilarityIndex)
+
+			useCase := patents.NewValuationJobUseCase(queueService, quotaService,
+				patents.WithEngines(engines), patents.WithSimilarityIndex(similarityIndex))
+
+			job, err := useCase.RevaluePatentValuationJob(&identity{id: "Alice"}, id, patents.RevalueJobRequest{
+				Priority:      entities.JobPriorityNormal,
+				EngineVersion: tc.engineVersion,
+			})
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, want, job)
+
+			queueService.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_UpdatePatentValuationJobMetadata(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+	job := entities.EvaluationJob{
+		ID:      id,
+		OwnerID: "Alice",
+		Metadata: entities.JobMetadata{
+			Labels: map[string]string{"matter": "M-1", "project": "alpha"},
+			Tags:   []string{"urgent"},
+			Note:   "check claim 3",
+		},
+	}
+
+	value := func(s string) *string { return &s }
+	tags := func(tags ...string) *[]string { return &tags }
+
+	testCases := []struct {
+		name     string
+		job      entities.EvaluationJob
+		patch    patents.MetadataPatch
+		want     entities.JobMetadata
+		wantCall bool
+		wantErr  error
+	}{
+		{
+			name: "labels are merged, tags and note replaced",
+			job:  job,
+			patch: patents.MetadataPatch{
+				Labels: map[string]*string{"matter": value(" M-2 "), "project": nil, "client": value("ACME")},
+				Tags:   tags("review", "billing", "review"),
+				Note:   value(""),
+			},
+			want: entities.JobMetadata{
+				Labels: map[string]string{"matter": "M-2", "client": "ACME"},
+				Tags:   []string{"billing", "review"},
+			},
+			wantCall: true,
+		},
+		{
+			name:     "empty patch keeps the metadata",
+			job:      job,
+			want:     job.Metadata,
+			wantCall: true,
+		},
+		{
+			name:    "invalid label key is rejected",
+			job:     job,
+			patch:   patents.MetadataPatch{Labels: map[string]*string{"client matter": value("M-2")}},
+			wantErr: patents.ErrInvalidMetadata,
+		},
+		{
+			name:    "other owner's job is not found",
+			job:     entities.EvaluationJob{ID: id, OwnerID: "Bob"},
+			patch:   patents.MetadataPatch{Note: value("mine")},
+			wantErr: patents.ErrJobNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("GetJobByID", id).Return(tc.job, nil).Once()
+
+			want := entities.EvaluationJob{}
+
+			if tc.wantCall {
+				want = tc.job
+				want.Metadata = tc.want
+
+				queueService.On("UpdateJobMetadata", id, tc.want).Return(want, nil).Once()
+			}
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			got, err := useCase.UpdatePatentValuationJobMetadata(&identity{id: "Alice"}, id, tc.patch)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, want, got)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_EachPatentValuationJobByIdentity(t *testing.T) {
+	t.Parallel()
+
+	urgent := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", Metadata: entities.JobMetadata{Tags: []string{"urgent"}}}
+	other := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice"}
+	last := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", Metadata: entities.JobMetadata{Tags: []string{"urgent"}}}
+	errStop := errors.New("stop")
+
+	testCases := []struct {
+		name    string
+		filter  patents.JobFilter
+		stopAt  int
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{name: "all jobs in order", want: []uuid.UUID{urgent.ID, other.ID, last.ID}},
+		{name: "filtered", filter: patents.JobFilter{Tags: []string{"urgent"}}, want: []uuid.UUID{urgent.ID, last.ID}},
+		{name: "stops at the first error", stopAt: 2, want: []uuid.UUID{urgent.ID, other.ID}, wantErr: errStop},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("EachJobByOwnerID", "Alice", mock.Anything).
+				Return(func(_ string, fn func(entities.EvaluationJob) error) error {
+					for _, job := range []entities.EvaluationJob{urgent, other, last} {
+						if err := fn(job); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				}).Once()
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			var got []uuid.UUID
+
+			err := useCase.EachPatentValuationJobByIdentity(&identity{id: "Alice"}, tc.filter,
+				func(job entities.EvaluationJob) error {
+					got = append(got, job.ID)
+					if len(got) == tc.stopAt {
+						return errStop
+					}
+
+					return nil
+				})
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_GetPatentRevaluationsByIdentityAndID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	root := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", CreatedAt: now}
+	first := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", CreatedAt: now.Add(time.Minute), RevaluationOf: root.ID}
+	second := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", CreatedAt: now.Add(time.Hour), RevaluationOf: root.ID}
+	unrelated := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", CreatedAt: now}
+
+	testCases := []struct {
+		name string
+		id   uuid.UUID
+	}{
+		{name: "original job lists its re-valuations", id: root.ID},
+		{name: "re-valuation lists its siblings", id: second.ID},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("GetJobByID", root.ID).Return(root, nil).Maybe()
+			queueService.On("GetJobByID", second.ID).Return(second, nil).Maybe()
+			queueService.On("GetJobsByOwnerID", "Alice").
+				Return([]entities.EvaluationJob{second, unrelated, first, root}, nil).Once()
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			jobs, err := useCase.GetPatentRevaluationsByIdentityAndID(&identity{id: "Alice"}, tc.id)
+			assert.NoError(t, err)
+			assert.Equal(t, []entities.EvaluationJob{root, first, second}, jobs)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_GetPatentDiffByIdentity(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	submission := func(minutes int, claimsText string) entities.EvaluationJob {
+		return entities.EvaluationJob{
+			ID:            uuid.New(),
+			OwnerID:       "Alice",
+			CreatedAt:     now.Add(time.Duration(minutes) * time.Minute),
+			PatentKey:     "EP1234567",
+			PatentContent: "Title\nClaims\n" + claimsText,
+		}
+	}
+
+	filed := submission(0, "1. A method comprising verifying a signature.")
+	amended := submission(10, "1. A method comprising verifying a signature with a key.\n2. The method of claim 1.")
+	granted := submission(20, "1. A method comprising verifying a signature with a key.")
+	revaluation := granted
+	revaluation.ID = uuid.New()
+	revaluation.CreatedAt = now.Add(30 * time.Minute)
+	revaluation.RevaluationOf = granted.ID
+	other := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", CreatedAt: now, PatentKey: "EP7654321"}
+
+	testCases := []struct {
+		name       string
+		patentKey  string
+		history    []entities.EvaluationJob
+		from       uuid.UUID
+		to         uuid.UUID
+		wantFrom   uuid.UUID
+		wantTo     uuid.UUID
+		wantClaims map[int]claims.ChangeKind
+		wantErr    error
+	}{
+		{
+			name:       "latest submission is compared to the previous one, re-valuations are skipped",
+			patentKey:  "ep 1234567 b1",
+			history:    []entities.EvaluationJob{revaluation, granted, other, filed, amended},
+			wantFrom:   amended.ID,
+			wantTo:     granted.ID,
+			wantClaims: map[int]claims.ChangeKind{1: claims.ChangeUnchanged, 2: claims.ChangeRemoved},
+		},
+		{
+			name:       "from defaults to the submission before to",
+			patentKey:  "EP1234567",
+			history:    []entities.EvaluationJob{filed, amended, granted},
+			to:         amended.ID,
+			wantFrom:   filed.ID,
+			wantTo:     amended.ID,
+			wantClaims: map[int]claims.ChangeKind{1: claims.ChangeAmended, 2: claims.ChangeAdded},
+		},
+		{
+			name:       "explicit jobs",
+			patentKey:  "EP1234567",
+			history:    []entities.EvaluationJob{filed, amended, granted},
+			from:       filed.ID,
+			to:         granted.ID,
+			wantFrom:   filed.ID,
+			wantTo:     granted.ID,
+			wantClaims: map[int]claims.ChangeKind{1: claims.ChangeAmended},
+		},
+		{
+			name:      "single submission has nothing to compare to",
+			patentKey: "EP1234567",
+			history:   []entities.EvaluationJob{filed, other},
+			wantErr:   patents.ErrNoPreviousSubmission,
+		},
+		{
+			name:      "job of another patent",
+			patentKey: "EP1234567",
+			history:   []entities.EvaluationJob{filed, amended, other},
+			from:      other.ID,
+			wantErr:   patents.ErrJobNotFound,
+		},
+		{
+			name:      "unknown patent",
+			patentKey: "US1111111",
+			history:   []entities.EvaluationJob{filed, amended, other},
+			wantErr:   patents.ErrPatentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			queueService.On("GetJobsByOwnerID", "Alice").Return(tc.history, nil).Once()
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil)
+
+			diff, err := useCase.GetPatentDiffByIdentity(&identity{id: "Alice"}, tc.patentKey, tc.from, tc.to)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "EP1234567", diff.PatentKey)
+			assert.Equal(t, tc.wantFrom, diff.From.ID)
+			assert.Equal(t, tc.wantTo, diff.To.ID)
+
+			changes := map[int]claims.ChangeKind{}
+			for _, change := range diff.Claims {
+				changes[change.Number] = change.Kind
+			}
+
+			assert.Equal(t, tc.wantClaims, changes)
+
+			queueService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_GetSimilarPatentsByIdentityAndID(t *testing.T) {
+	t.Parallel()
+
+	original := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice"}
+	revaluation := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", RevaluationOf: original.ID}
+	similar := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice"}
+	vanished := uuid.New()
+
+	testCases := []struct {
+		name        string
+		job         entities.EvaluationJob
+		withIndex   bool
+		preparation func(*mocks.QueueService, *mocks.SimilarityIndex)
+		want        []patents.SimilarPatent
+		wantErr     error
+	}{
+		{
+			name:      "matches are resolved to jobs, vanished jobs are skipped",
+			job:       original,
+			withIndex: true,
+			preparation: func(queueService *mocks.QueueService, similarityIndex *mocks.SimilarityIndex) {
+				similarityIndex.On("Similar", "Alice", original.ID, 5).Return([]entities.SimilarityMatch{
+					{JobID: similar.ID, Score: 0.8},
+					{JobID: vanished, Score: 0.4},
+				}, nil).Once()
+				queueService.On("GetJobByID", similar.ID).Return(similar, nil).Once()
+				queueService.On("GetJobByID", vanished).Return(entities.EvaluationJob{}, patents.ErrJobNotFound).Once()
+			},
+			want: []patents.SimilarPatent{{Job: similar, Score: 0.8}},
+		},
+		{
+			name:      "re-valuation is compared by its original job",
+			job:       revaluation,
+			withIndex: true,
+			preparation: func(_ *mocks.QueueService, similarityIndex *mocks.SimilarityIndex) {
+				similarityIndex.On("Similar", "Alice", original.ID, 5).Return([]entities.SimilarityMatch{}, nil).Once()
+			},
+			want: []patents.SimilarPatent{},
+		},
+		{
+			name:      "job that is not indexed is not found",
+			job:       original,
+			withIndex: true,
+			preparation: func(_ *mocks.QueueService, similarityIndex *mocks.SimilarityIndex) {
+				similarityIndex.On("Similar", "Alice", original.ID, 5).
+					Return(nil, fmt.Errorf("job %s: %w", original.ID.String(), entities.ErrJobNotIndexed)).Once()
+			},
+			wantErr: patents.ErrJobNotFound,
+		},
+		{
+			name:        "other owner's job is not found",
+			job:         entities.EvaluationJob{ID: original.ID, OwnerID: "Bob"},
+			withIndex:   true,
+			preparation: func(*mocks.QueueService, *mocks.SimilarityIndex) {},
+			wantErr:     patents.ErrJobNotFound,
+		},
+		{
+			name:        "similarity search is disabled without index",
+			job:         original,
+			preparation: func(*mocks.QueueService, *mocks.SimilarityIndex) {},
+			wantErr:     patents.ErrSimilarityDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			similarityIndex := new(mocks.SimilarityIndex)
+
+			queueService.On("GetJobByID", tc.job.ID).Return(tc.job, nil).Maybe()
+			tc.preparation(queueService, similarityIndex)
+
+			var options []patents.UseCaseOption
+			if tc.withIndex {
+				options = append(options, patents.WithSimilarityIndex(similarityIndex))
+			}
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil, options...)
+
+			got, err := useCase.GetSimilarPatentsByIdentityAndID(&identity{id: "Alice"}, tc.job.ID, 5)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+
+			queueService.AssertExpectations(t)
+			similarityIndex.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_SearchPatentValuationJobsByIdentity(t *testing.T) {
+	t.Parallel()
+
+	found := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice"}
+	vanished := uuid.New()
+	snippets := []entities.Snippet{{Field: "content", Text: "a <mark>signature</mark>"}}
+
+	testCases := []struct {
+		name        string
+		withSearch  bool
+		preparation func(*mocks.QueueService, *mocks.SearchService)
+		want        []patents.SearchResult
+		wantErr     error
+	}{
+		{
+			name:       "hits are resolved to jobs, vanished jobs are skipped",
+			withSearch: true,
+			preparation: func(queueService *mocks.QueueService, searchService *mocks.SearchService) {
+				searchService.On("SearchJobs", "Alice", "signature").Return([]entities.SearchHit{
+					{JobID: found.ID, Score: 1.5, Snippets: snippets},
+					{JobID: vanished, Score: 0.5},
+				}, nil).Once()
+				queueService.On("GetJobByID", found.ID).Return(found, nil).Once()
+				queueService.On("GetJobByID", vanished).Return(entities.EvaluationJob{}, patents.ErrJobNotFound).Once()
+			},
+			want: []patents.SearchResult{{Job: found, Score: 1.5, Snippets: snippets}},
+		},
+		{
+			name:       "invalid query",
+			withSearch: true,
+			preparation: func(_ *mocks.QueueService, searchService *mocks.SearchService) {
+				searchService.On("SearchJobs", "Alice", "signature").
+					Return(nil, fmt.Errorf("%w: unterminated phrase", patents.ErrInvalidQuery)).Once()
+			},
+			wantErr: patents.ErrInvalidQuery,
+		},
+		{
+			name:        "search is disabled without search service",
+			preparation: func(*mocks.QueueService, *mocks.SearchService) {},
+			wantErr:     patents.ErrSearchDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			searchService := new(mocks.SearchService)
+
+			tc.preparation(queueService, searchService)
+
+			var options []patents.UseCaseOption
+			if tc.withSearch {
+				options = append(options, patents.WithSearch(searchService))
+			}
+
+			useCase := patents.NewValuationJobUseCase(queueService, nil, options...)
+
+			got, err := useCase.SearchPatentValuationJobsByIdentity(&identity{id: "Alice"}, "signature", patents.JobFilter{})
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+
+			queueService.AssertExpectations(t)
+			searchService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_valuationJobUseCase_CreatePatentValuationBatch(t *testing.T) {
+	t.Parallel()
+
+	job := func(content string) entities.EvaluationJob {
+		return entities.EvaluationJob{
+			ID:                  uuid.NewSHA1(uuid.Nil, []byte(content)),
+			OwnerID:             "Alice",
+			EvaluationJobStatus: entities.EvaluationJobStatusPending,
+			PatentContent:       content,
+		}
+	}
+
+	enqueue := func(queueService *mocks.QueueService, content string) {
+		queueService.On("EnqueueJob", "Alice", content, mock.MatchedBy(func(options entities.JobOptions) bool {
+			return options.BatchID != uuid.Nil && options.ContentHash == patents.ContentHash(content)
+		})).Return(job(content), nil).Once()
+	}
+
+	requests := []patents.CreateJobRequest{
+		{Content: "first", Priority: entities.JobPriorityNormal},
+		{Content: "second", Priority: entities.JobPriorityNormal},
+		{Content: "third", Priority: entities.JobPriorityNormal},
+	}
+
+	testCases := []struct {
+		name         string
+		preparation  func(*mocks.QueueService, *mocks.QuotaService, *mocks.BatchService)
+		requests     []patents.CreateJobRequest
+		mode         entities.BatchMode
+		wantJobs     []entities.EvaluationJob
+		wantRejected []int
+		// wantRejections are the messages of the rejected items, if given
+		wantRejections []string
+		wantErr        error
+	}{
+		{
+			name: "Atomic batch takes all quota at once",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, batchService *mocks.BatchService,
+			) {
+				quotaService.On("GetQuotaTokens", "Alice", 3).
+					Return([]uuid.UUID{uuid.New(), uuid.New(), uuid.New()}, nil).Once()
+				enqueue(queueService, "first")
+				enqueue(queueService, "second")
+				enqueue(queueService, "third")
+				batchService.On("CreateBatch", mock.Anything).Return(nil).Once()
+			},
+			requests: requests,
+			mode:     entities.BatchModeAtomic,
+			wantJobs: []entities.EvaluationJob{job("first"), job("second"), job("third")},
+		},
+		{
+			name: "Atomic batch failing to enqueue an item rolls back the enqueued jobs and returns all quota",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, _ *mocks.BatchService,
+			) {
+				tokens := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+				quotaService.On("GetQuotaTokens", "Alice", 3).Return(tokens, nil).Once()
+				enqueue(queueService, "first")
+				queueService.On("EnqueueJob", "Alice", "second", mock.Anything).
+					Return(entities.EvaluationJob{}, errFoo).Once()
+				queueService.On("DeleteJob", job("first").ID).Return(nil).Once()
+
+				for _, token := range tokens {
+					quotaService.On("ReturnQuotaToken", token).Return().Once()
+				}
+			},
+			requests: requests,
+			mode:     entities.BatchModeAtomic,
+			wantErr:  errFoo,
+		},
+		{
+			name: "Batch failing to be stored rolls back its jobs and returns their quota",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, batchService *mocks.BatchService,
+			) {
+				token := uuid.New()
+				quotaService.On("GetQuotaToken", "Alice").Return(token, nil).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.Nil, patents.ErrQuotaExceeded).Once()
+				enqueue(queueService, "first")
+				batchService.On("CreateBatch", mock.Anything).Return(errFoo).Once()
+				queueService.On("DeleteJob", job("first").ID).Return(nil).Once()
+				quotaService.On("ReturnQuotaToken", token).Return().Once()
+			},
+			requests: requests,
+			mode:     entities.BatchModeBestEffort,
+			wantErr:  errFoo,
+		},
+		{
+			name: "Atomic batch over quota creates no job",
+			preparation: func(_ *mocks.QueueService, quotaService *mocks.QuotaService, _ *mocks.BatchService) {
+				quotaService.On("GetQuotaTokens", "Alice", 3).Return(nil, patents.ErrQuotaExceeded).Once()
+			},
+			requests: requests,
+			mode:     entities.BatchModeAtomic,
+			wantErr:  patents.ErrQuotaExceeded,
+		},
+		{
+			name:        "Atomic batch with a disallowed priority creates no job",
+			preparation: func(*mocks.QueueService, *mocks.QuotaService, *mocks.BatchService) {},
+			requests: []patents.CreateJobRequest{
+				{Content: "first", Priority: entities.JobPriorityNormal},
+				{Content: "second", Priority: entities.JobPriorityHigh},
+			},
+			mode:    entities.BatchModeAtomic,
+			wantErr: patents.ErrPriorityNotAllowed,
+		},
+		{
+			name: "Best-effort batch rejects the items beyond the quota",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, batchService *mocks.BatchService,
+			) {
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.Nil, patents.ErrQuotaExceeded).Once()
+				enqueue(queueService, "first")
+				batchService.On("CreateBatch", mock.Anything).Return(nil).Once()
+			},
+			requests:     requests,
+			mode:         entities.BatchModeBestEffort,
+			wantJobs:     []entities.EvaluationJob{job("first")},
+			wantRejected: []int{1, 2},
+			wantRejections: []string{
+				"could not retrieve quota token: quota exceeded", "could not retrieve quota token: quota exceeded",
+			},
+		},
+		{
+			name: "Best-effort batch does not reveal quota failures",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, batchService *mocks.BatchService,
+			) {
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.Nil, errFoo).Once()
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Twice()
+				enqueue(queueService, "second")
+				enqueue(queueService, "third")
+				batchService.On("CreateBatch", mock.Anything).Return(nil).Once()
+			},
+			requests:       requests,
+			mode:           entities.BatchModeBestEffort,
+			wantJobs:       []entities.EvaluationJob{job("second"), job("third")},
+			wantRejected:   []int{0},
+			wantRejections: []string{"could not retrieve quota token"},
+		},
+		{
+			name: "Best-effort batch rejects a disallowed priority",
+			preparation: func(
+				queueService *mocks.QueueService, quotaService *mocks.QuotaService, batchService *mocks.BatchService,
+			) {
+				quotaService.On("GetQuotaToken", "Alice").Return(uuid.New(), nil).Once()
+				enqueue(queueService, "first")
+				batchService.On("CreateBatch", mock.Anything).Return(nil).Once()
+			},
+			requests: []patents.CreateJobRequest{
+				{Content: "first", Priority: entities.JobPriorityNormal},
+				{Content: "second", Priority: entities.JobPriorityHigh},
+			},
+			mode:         entities.BatchModeBestEffort,
+			wantJobs:     []entities.EvaluationJob{job("first")},
+			wantRejected: []int{1},
+		},
+		{
+			name:        "Empty batch",
+			preparation: func(*mocks.QueueService, *mocks.QuotaService, *mocks.BatchService) {},
+			requests:    nil,
+			mode:        entities.BatchModeAtomic,
+			wantErr:     patents.ErrEmptyBatch,
+		},
+		{
+			name:        "Batch too large",
+			preparation: func(*mocks.QueueService, *mocks.QuotaService, *mocks.BatchService) {},
+			requests:    append(append([]patents.CreateJobRequest{}, requests...), requests...),
+			mode:        entities.BatchModeAtomic,
+			wantErr:     patents.ErrBatchTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queueService := new(mocks.QueueService)
+			quotaService := new(mocks.QuotaService)
+			batchService := new(mocks.BatchService)
+
+			tc.preparation(queueService, quotaService, batchService)
+
+			//nolint:gomnd // Test preset
+			useCase := patents.NewValuationJobUseCase(queueService, quotaService,
+				patents.WithBatches(batchService, 5))
+
+			batch, jobs, err := useCase.CreatePatentValuationBatch(&identity{id: "Alice"}, tc.requests, tc.mode)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Alice", batch.OwnerID)
+				assert.Len(t, batch.Items, len(tc.requests))
+
+				var (
+					rejected   []int
+					rejections []string
+				)
+
+				for i, item := range batch.Items {
+					if item.JobID == uuid.Nil {
+						assert.NotEmpty(t, item.Rejection)
+
+						rejected = append(rejected, i)
+						rejections = append(rejections, item.Rejection)
+					}
+				}
+
+				assert.Equal(t, tc.wantRejected, rejected)
+
+				if tc.wantRejections != nil {
+					assert.Equal(t, tc.wantRejections, rejections)
+				}
+			}
+
+			assert.Equal(t, tc.wantJobs, jobs)
+
+			queueService.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+			batchService.AssertExpectations(t)
 		})
 	}
 }
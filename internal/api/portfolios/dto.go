@@ -0,0 +1,147 @@
+package portfolios
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+var errMalformedID = errors.New("malformed id")
+
+type CreatePortfolioDTO struct {
+	Name     string   `json:"name"`
+	JobIDs   []string `json:"jobIds"`
+	BatchIDs []string `json:"batchIds"`
+}
+
+type PortfolioDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	JobIDs    []string  `json:"jobIds"`
+	BatchIDs  []string  `json:"batchIds"`
+}
+
+type StatisticsDTO struct {
+	Jobs     int `json:"jobs"`
+	Pending  int `json:"pending"`
+	Running  int `json:"running"`
+	Finished int `json:"finished"`
+	Failed   int `json:"failed"`
+
+	// Currency is the ISO 4217 code of all values, including the valuations of the top jobs
+	Currency   string  `json:"currency"`
+	TotalValue int     `json:"totalValue"`
+	MeanValue  float64 `json:"meanValue"`
+	// Percentiles are keyed p10, p25, ..., absent without finished jobs
+	Percentiles      map[string]float64   `json:"percentiles,omitempty"`
+	ByTechnicalField []FieldStatisticsDTO `json:"byTechnicalField"`
+	Top              []patents.JobDTO     `json:"top"`
+}
+
+type FieldStatisticsDTO struct {
+	// TechnicalField is empty for unclassified patents
+	TechnicalField string  `json:"technicalField"`
+	Count          int     `json:"count"`
+	TotalValue     int     `json:"totalValue"`
+	MeanValue      float64 `json:"meanValue"`
+}
+
+// parseIDs reports malformed IDs by the JSON pointer of the ID in the field of the request body.
+func parseIDs(field string, ids []string) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, 0, len(ids))
+
+	for i, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, problem.Invalid("/"+field+"/"+strconv.Itoa(i), errors.Wrap(errMalformedID, id))
+		}
+
+		result = append(result, parsed)
+	}
+
+	return result, nil
+}
+
+func formatIDs(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+
+	return result
+}
+
+func CreatePortfolioFromDTO(dto CreatePortfolioDTO) (CreatePortfolioRequest, error) {
+	jobIDs, err := parseIDs("jobIds", dto.JobIDs)
+	if err != nil {
+		return CreatePortfolioRequest{}, err
+	}
+
+	batchIDs, err := parseIDs("batchIds", dto.BatchIDs)
+	if err != nil {
+		return CreatePortfolioRequest{}, err
+	}
+
+	return CreatePortfolioRequest{
+		Name:     dto.Name,
+		JobIDs:   jobIDs,
+		BatchIDs: batchIDs,
+	}, nil
+}
+
+func PortfolioToDTO(portfolio entities.Portfolio) PortfolioDTO {
+	return PortfolioDTO{
+		ID:        portfolio.ID.String(),
+		Name:      portfolio.Name,
+		CreatedAt: portfolio.CreatedAt,
+		JobIDs:    formatIDs(portfolio.JobIDs),
+		BatchIDs:  formatIDs(portfolio.BatchIDs),
+	}
+}
+
+func PortfoliosToDTO(portfolios []entities.Portfolio) []PortfolioDTO {
+	result := make([]PortfolioDTO, 0, len(portfolios))
+
+	for _, portfolio := range portfolios {
+		result = append(result, PortfolioToDTO(portfolio))
+	}
+
+	return result
+}
+
+func StatisticsToDTO(statistics Statistics) StatisticsDTO {
+	dto := StatisticsDTO{
+		Jobs:             statistics.Jobs,
+		Pending:          statistics.Pending,
+		Running:          statistics.Running,
+		Finished:         statistics.Finished,
+		Failed:           statistics.Failed,
+		Currency:         statistics.Currency,
+		TotalValue:       statistics.TotalValue,
+		MeanValue:        statistics.MeanValue,
+		ByTechnicalField: make([]FieldStatisticsDTO, 0, len(statistics.ByTechnicalField)),
+		Top:              patents.JobsToDTO(statistics.Top),
+	}
+
+	if len(statistics.Percentiles) > 0 {
+		dto.Percentiles = make(map[string]float64, len(statistics.Percentiles))
+
+		for p, value := range statistics.Percentiles {
+			dto.Percentiles["p"+strconv.Itoa(p)] = value
+		}
+	}
+
+	for _, field := range statistics.ByTechnicalField {
+		dto.ByTechnicalField = append(dto.ByTechnicalField, FieldStatisticsDTO(field))
+	}
+
+	return dto
+}
@@ -0,0 +1,162 @@
+package portfolios
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const (
+	defaultTop = 10
+	maxTop     = 100
+
+	headerAcceptCurrency = "Accept-Currency"
+)
+
+type handler struct {
+	useCase PortfolioUseCase
+}
+
+func NewHandler(useCase PortfolioUseCase) Handler {
+	return &handler{
+		useCase: useCase,
+	}
+}
+
+var (
+	errGetIdentityFailed    = errors.New("cannot get identity from context")
+	errMalformedTop         = errors.New("top must be a number between 0 and 100")
+	errMalformedPortfolio   = errors.New("malformed portfolio")
+	errMalformedPortfolioID = errors.New("malformed portfolio id")
+)
+
+func getIdentityFromContext(c echo.Context) (authorization.Identity, error) {
+	identity, ok := c.Get(contextIdentityKey).(authorization.Identity)
+	if !ok {
+		return nil, errGetIdentityFailed
+	}
+
+	return identity, nil
+}
+
+func (h *handler) CreatePortfolio() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		var dto CreatePortfolioDTO
+		if err := c.Bind(&dto); err != nil {
+			return problem.Detailed(errMalformedPortfolio, "%v", err)
+		}
+
+		request, err := CreatePortfolioFromDTO(dto)
+		if err != nil {
+			return err
+		}
+
+		portfolio, err := h.useCase.CreatePortfolio(identity, request)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusCreated, PortfolioToDTO(portfolio)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPortfolios() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		portfolios, err := h.useCase.GetPortfoliosByIdentity(identity)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, PortfoliosToDTO(portfolios)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPortfolioByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedPortfolioID)
+		}
+
+		portfolio, err := h.useCase.GetPortfolioByIdentityAndID(identity, id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, PortfolioToDTO(portfolio)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
+
+func (h *handler) GetPortfolioStatistics() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		identity, err := getIdentityFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			return problem.Invalid("id", errMalformedPortfolioID)
+		}
+
+		top := defaultTop
+		if value := c.QueryParam("top"); value != "" {
+			top, err = strconv.Atoi(value)
+			if err != nil || top < 0 || top > maxTop {
+				return problem.Invalid("top", errMalformedTop)
+			}
+		}
+
+		currencyCode := c.QueryParam("currency")
+		if currencyCode == "" {
+			currencyCode = c.Request().Header.Get(headerAcceptCurrency)
+		}
+
+		statistics, err := h.useCase.GetPortfolioStatistics(identity, id, top, currencyCode)
+		if errors.Is(err, currency.ErrUnknownCurrency) {
+			return problem.Invalid("currency", errors.Wrap(err, "use one of the currencies of the conversion table"))
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := c.JSON(http.StatusOK, StatisticsToDTO(statistics)); err != nil {
+			return errors.Wrap(err, "cannot write response")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package portfolios
+
+import (
+	"net/http"
+
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// ProblemTypes maps the errors of the package to the problem types they are reported as.
+func ProblemTypes() []problem.Mapping {
+	return []problem.Mapping{
+		{Err: ErrPortfolioNotFound, Type: problem.Type{
+			Status: http.StatusNotFound, Code: "portfolio-not-found", Title: "Portfolio not found",
+		}},
+		{Err: ErrInvalidPortfolio, Type: badRequest("invalid-portfolio", "Invalid portfolio")},
+		{Err: ErrUnknownMember, Type: badRequest("unknown-portfolio-member", "Unknown portfolio member")},
+		{Err: errMalformedPortfolio, Type: badRequest("malformed-portfolio", "Malformed portfolio")},
+		{Err: errMalformedID, Type: badRequest("malformed-portfolio", "Malformed portfolio")},
+		{Err: errMalformedPortfolioID, Type: badRequest("malformed-portfolio-id", "Malformed portfolio ID")},
+		{Err: errMalformedTop, Type: badRequest("malformed-query-parameter", "Malformed query parameter")},
+	}
+}
+
+func badRequest(code string, title string) problem.Type {
+	return problem.Type{Status: http.StatusBadRequest, Code: code, Title: title}
+}
@@ -0,0 +1,18 @@
+//go:generate mockery --name PortfolioService
+
+package portfolios
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var ErrPortfolioNotFound = errors.New("portfolio not found")
+
+type PortfolioService interface {
+	CreatePortfolio(portfolio entities.Portfolio) error
+	GetPortfoliosByOwnerID(ownerID string) ([]entities.Portfolio, error)
+	GetPortfolioByID(id uuid.UUID) (entities.Portfolio, error)
+}
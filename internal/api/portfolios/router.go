@@ -0,0 +1,47 @@
+package portfolios
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/MyChaOS87/patAi/internal/api/router"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/pkg/middleware"
+)
+
+const (
+	portfoliosBaseURI  = "portfolios"
+	contextIdentityKey = "portfolios-identity"
+)
+
+var _ router.Router = &portfolios{}
+
+type Handler interface {
+	CreatePortfolio() echo.HandlerFunc
+	GetPortfolios() echo.HandlerFunc
+	GetPortfolioByID() echo.HandlerFunc
+	GetPortfolioStatistics() echo.HandlerFunc
+}
+
+type portfolios struct {
+	authorizationProvider middleware.AuthorizationProvider[authorization.Identity]
+	handler               Handler
+}
+
+func NewPortfoliosRouter(
+	authorizationProvider middleware.AuthorizationProvider[authorization.Identity], handler Handler,
+) router.Router {
+	return &portfolios{
+		authorizationProvider: authorizationProvider,
+		handler:               handler,
+	}
+}
+
+func (p *portfolios) AddRoutes(baseGroup *echo.Group) {
+	portfoliosGroup := baseGroup.Group(portfoliosBaseURI)
+	portfoliosGroup.Use(middleware.APIKey(p.authorizationProvider, contextIdentityKey))
+
+	portfoliosGroup.GET("", p.handler.GetPortfolios())
+	portfoliosGroup.POST("", p.handler.CreatePortfolio())
+	portfoliosGroup.GET("/:id", p.handler.GetPortfolioByID())
+	portfoliosGroup.GET("/:id/statistics", p.handler.GetPortfolioStatistics())
+}
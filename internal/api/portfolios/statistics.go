@@ -0,0 +1,152 @@
+package portfolios
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+)
+
+// Percentiles reported by the statistics.
+var Percentiles = []int{10, 25, 50, 75, 90} //nolint:gochecknoglobals,gomnd // Constant preset
+
+// Statistics aggregate the finished valuations of a portfolio, unfinished jobs are only counted.
+type Statistics struct {
+	Jobs     int
+	Pending  int
+	Running  int
+	Finished int
+	Failed   int
+
+	// Currency is the ISO 4217 code all values are converted to
+	Currency   string
+	TotalValue int
+	MeanValue  float64
+	// Percentiles holds the value distribution, keyed by the entries of Percentiles
+	Percentiles map[int]float64
+	// ByTechnicalField is ordered by descending total value
+	ByTechnicalField []FieldStatistics
+	// Top holds the most valuable finished jobs, most valuable first, their valuations converted to Currency
+	Top []entities.EvaluationJob
+}
+
+type FieldStatistics struct {
+	// TechnicalField is empty for unclassified patents
+	TechnicalField string
+	Count          int
+	TotalValue     int
+	MeanValue      float64
+}
+
+// ComputeStatistics aggregates the jobs in the target currency, top limits the number of most valuable jobs reported.
+// The values of finished jobs are converted with the currencies table first, so that engines reporting in different
+// currencies can be aggregated.
+func ComputeStatistics(
+	jobs []entities.EvaluationJob, top int, currencies *currency.Table, target string,
+) (Statistics, error) {
+	statistics := Statistics{
+		Jobs:        len(jobs),
+		Currency:    target,
+		Percentiles: map[int]float64{},
+	}
+
+	finished := make([]entities.EvaluationJob, 0, len(jobs))
+	fields := map[string]*FieldStatistics{}
+
+	for _, job := range jobs {
+		switch job.EvaluationJobStatus {
+		case entities.EvaluationJobStatusPending:
+			statistics.Pending++
+		case entities.EvaluationJobStatusRunning:
+			statistics.Running++
+		case entities.EvaluationJobStatusFailed:
+			statistics.Failed++
+		case entities.EvaluationJobStatusFinished:
+			job, err := convertJob(job, currencies, target)
+			if err != nil {
+				return Statistics{}, err
+			}
+
+			statistics.Finished++
+			statistics.TotalValue += job.Value
+
+			finished = append(finished, job)
+
+			field, ok := fields[job.TechnicalField]
+			if !ok {
+				field = &FieldStatistics{TechnicalField: job.TechnicalField}
+				fields[job.TechnicalField] = field
+			}
+
+			field.Count++
+			field.TotalValue += job.Value
+		}
+	}
+
+	if len(finished) == 0 {
+		return statistics, nil
+	}
+
+	statistics.MeanValue = float64(statistics.TotalValue) / float64(len(finished))
+
+	// most valuable first, ties in submission order
+	slices.SortStableFunc(finished, func(a, b entities.EvaluationJob) int {
+		return cmp.Compare(b.Value, a.Value)
+	})
+
+	for _, p := range Percentiles {
+		statistics.Percentiles[p] = percentile(finished, p)
+	}
+
+	statistics.Top = finished[:min(max(top, 0), len(finished))]
+
+	for _, field := range fields {
+		field.MeanValue = float64(field.TotalValue) / float64(field.Count)
+		statistics.ByTechnicalField = append(statistics.ByTechnicalField, *field)
+	}
+
+	slices.SortFunc(statistics.ByTechnicalField, func(a, b FieldStatistics) int {
+		return cmp.Or(cmp.Compare(b.TotalValue, a.TotalValue), cmp.Compare(a.TechnicalField, b.TechnicalField))
+	})
+
+	return statistics, nil
+}
+
+// convertJob converts the value and the confidence interval of a finished job to whole units of the target currency,
+// jobs without currency are taken to be in the base currency of the table.
+func convertJob(job entities.EvaluationJob, currencies *currency.Table, target string) (entities.EvaluationJob, error) {
+	from := job.Currency
+	if from == "" {
+		from = currencies.Base()
+	}
+
+	for _, amount := range []*int{&job.Value, &job.Confidence.Lower, &job.Confidence.Upper} {
+		converted, err := currencies.Convert(float64(*amount), from, target)
+		if err != nil {
+			return entities.EvaluationJob{}, errors.Wrapf(err, "cannot convert value of job %s", job.ID.String())
+		}
+
+		*amount = int(math.Round(converted))
+	}
+
+	job.Currency = target
+
+	return job, nil
+}
+
+// percentile interpolates linearly between the closest ranks of the jobs sorted by descending value.
+func percentile(sorted []entities.EvaluationJob, p int) float64 {
+	//nolint:gomnd // percent
+	rank := float64(p) / 100 * float64(len(sorted)-1)
+	lower, upper := int(math.Floor(rank)), int(math.Ceil(rank))
+
+	// the jobs are sorted descending, so ranks are counted from the end
+	lowerValue := float64(sorted[len(sorted)-1-lower].Value)
+	upperValue := float64(sorted[len(sorted)-1-upper].Value)
+
+	return lowerValue + (upperValue-lowerValue)*(rank-float64(lower))
+}
@@ -0,0 +1,219 @@
+package portfolios
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+var (
+	ErrPortfolioUseCase = errors.New("portfolio use case error")
+	ErrInvalidPortfolio = errors.New("invalid portfolio")
+	ErrUnknownMember    = errors.New("unknown portfolio member")
+)
+
+// PortfolioUseCase manages the portfolios of an identity, portfolios of other identities are reported as
+// ErrPortfolioNotFound. Statistics are computed from the current state of the jobs on every request, in the requested
+// currency or else the base currency of the currency table; an unknown currency is a currency.ErrUnknownCurrency error.
+type PortfolioUseCase interface {
+	// CreatePortfolio returns an ErrUnknownMember error if a job or batch does not exist or belongs to someone else
+	CreatePortfolio(identity authorization.Identity, request CreatePortfolioRequest) (entities.Portfolio, error)
+	GetPortfoliosByIdentity(identity authorization.Identity) ([]entities.Portfolio, error)
+	GetPortfolioByIdentityAndID(identity authorization.Identity, id uuid.UUID) (entities.Portfolio, error)
+	GetPortfolioStatistics(
+		identity authorization.Identity, id uuid.UUID, top int, currencyCode string,
+	) (Statistics, error)
+}
+
+type CreatePortfolioRequest struct {
+	Name     string
+	JobIDs   []uuid.UUID
+	BatchIDs []uuid.UUID
+}
+
+type portfolioUseCase struct {
+	portfolioService PortfolioService
+	queueService     patents.QueueService
+	batchService     patents.BatchService
+	currencies       *currency.Table
+}
+
+// NewPortfolioUseCase aggregates statistics in the currencies of the table.
+func NewPortfolioUseCase(
+	portfolioService PortfolioService, queueService patents.QueueService, batchService patents.BatchService,
+	currencies *currency.Table,
+) PortfolioUseCase {
+	return &portfolioUseCase{
+		portfolioService: portfolioService,
+		queueService:     queueService,
+		batchService:     batchService,
+		currencies:       currencies,
+	}
+}
+
+func (p *portfolioUseCase) CreatePortfolio(
+	identity authorization.Identity, request CreatePortfolioRequest,
+) (entities.Portfolio, error) {
+	if request.Name == "" {
+		return entities.Portfolio{}, problem.Detailed(ErrInvalidPortfolio, "name is required")
+	}
+
+	if len(request.JobIDs) == 0 && len(request.BatchIDs) == 0 {
+		return entities.Portfolio{}, problem.Detailed(ErrInvalidPortfolio, "at least one job or batch is required")
+	}
+
+	portfolio := entities.Portfolio{
+		ID:        uuid.New(),
+		OwnerID:   identity.GetID(),
+		Name:      request.Name,
+		CreatedAt: time.Now(),
+		JobIDs:    request.JobIDs,
+		BatchIDs:  request.BatchIDs,
+	}
+
+	for _, id := range request.JobIDs {
+		if _, err := p.getJob(identity, id); err != nil {
+			return entities.Portfolio{}, err
+		}
+	}
+
+	for _, id := range request.BatchIDs {
+		if _, err := p.getBatch(identity, id); err != nil {
+			return entities.Portfolio{}, err
+		}
+	}
+
+	if err := p.portfolioService.CreatePortfolio(portfolio); err != nil {
+		return entities.Portfolio{}, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	return portfolio, nil
+}
+
+func (p *portfolioUseCase) GetPortfoliosByIdentity(identity authorization.Identity) ([]entities.Portfolio, error) {
+	portfolios, err := p.portfolioService.GetPortfoliosByOwnerID(identity.GetID())
+	if err != nil {
+		return nil, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	return portfolios, nil
+}
+
+func (p *portfolioUseCase) GetPortfolioByIdentityAndID(
+	identity authorization.Identity, id uuid.UUID,
+) (entities.Portfolio, error) {
+	portfolio, err := p.portfolioService.GetPortfolioByID(id)
+	if err != nil {
+		return entities.Portfolio{}, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	if portfolio.OwnerID != identity.GetID() {
+		return entities.Portfolio{}, ErrPortfolioNotFound
+	}
+
+	return portfolio, nil
+}
+
+func (p *portfolioUseCase) GetPortfolioStatistics(
+	identity authorization.Identity, id uuid.UUID, top int, currencyCode string,
+) (Statistics, error) {
+	target := p.currencies.Base()
+
+	if currencyCode != "" {
+		normalized, err := p.currencies.Normalize(currencyCode)
+		if err != nil {
+			return Statistics{}, errors.WithStack(err)
+		}
+
+		target = normalized
+	}
+
+	portfolio, err := p.GetPortfolioByIdentityAndID(identity, id)
+	if err != nil {
+		return Statistics{}, err
+	}
+
+	jobs, err := p.getMembers(identity, portfolio)
+	if err != nil {
+		return Statistics{}, err
+	}
+
+	statistics, err := ComputeStatistics(jobs, top, p.currencies, target)
+	if err != nil {
+		return Statistics{}, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	return statistics, nil
+}
+
+// getMembers returns the jobs of the portfolio, a job listed directly and via a batch is returned once.
+func (p *portfolioUseCase) getMembers(
+	identity authorization.Identity, portfolio entities.Portfolio,
+) ([]entities.EvaluationJob, error) {
+	ids := make([]uuid.UUID, 0, len(portfolio.JobIDs))
+	seen := map[uuid.UUID]bool{}
+
+	add := func(id uuid.UUID) {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range portfolio.JobIDs {
+		add(id)
+	}
+
+	for _, batchID := range portfolio.BatchIDs {
+		batch, err := p.getBatch(identity, batchID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range batch.Items {
+			add(item.JobID)
+		}
+	}
+
+	jobs := make([]entities.EvaluationJob, 0, len(ids))
+
+	for _, id := range ids {
+		job, err := p.getJob(identity, id)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (p *portfolioUseCase) getJob(identity authorization.Identity, id uuid.UUID) (entities.EvaluationJob, error) {
+	job, err := p.queueService.GetJobByID(id)
+	if errors.Is(err, patents.ErrJobNotFound) || (err == nil && job.OwnerID != identity.GetID()) {
+		return entities.EvaluationJob{}, problem.Detailed(ErrUnknownMember, "job %s", id.String())
+	} else if err != nil {
+		return entities.EvaluationJob{}, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	return job, nil
+}
+
+func (p *portfolioUseCase) getBatch(identity authorization.Identity, id uuid.UUID) (entities.Batch, error) {
+	batch, err := p.batchService.GetBatchByID(id)
+	if errors.Is(err, patents.ErrBatchNotFound) || (err == nil && batch.OwnerID != identity.GetID()) {
+		return entities.Batch{}, problem.Detailed(ErrUnknownMember, "batch %s", id.String())
+	} else if err != nil {
+		return entities.Batch{}, errors.Wrap(err, ErrPortfolioUseCase.Error())
+	}
+
+	return batch, nil
+}
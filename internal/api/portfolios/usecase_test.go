@@ -0,0 +1,403 @@
+//nolint:funlen // Test functions are long, due to test cases
+package portfolios_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	patentsMocks "github.com/MyChaOS87/patAi/internal/api/patents/mocks"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios/mocks"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+)
+
+type identity struct {
+	id string
+}
+
+func (i *identity) GetID() string {
+	return i.id
+}
+
+func (i *identity) IsOperator() bool {
+	return false
+}
+
+func (i *identity) GetPlan() authorization.Plan {
+	return authorization.PlanFree
+}
+
+func finishedJob(value int, field string) entities.EvaluationJob {
+	return entities.EvaluationJob{
+		ID:                  uuid.New(),
+		OwnerID:             "Alice",
+		EvaluationJobStatus: entities.EvaluationJobStatusFinished,
+		Value:               value,
+		Currency:            "EUR",
+		TechnicalField:      field,
+	}
+}
+
+func currencies(t *testing.T) *currency.Table {
+	t.Helper()
+
+	table, err := currency.NewTable("EUR", map[string]float64{"USD": 2, "JPY": 100})
+	assert.NoError(t, err)
+
+	return table
+}
+
+func TestComputeStatistics(t *testing.T) {
+	t.Parallel()
+
+	a := finishedJob(10, "H04L")
+	b := finishedJob(40, "A61K")
+	c := finishedJob(20, "H04L")
+	d := finishedJob(30, "")
+	pending := entities.EvaluationJob{ID: uuid.New(), EvaluationJobStatus: entities.EvaluationJobStatusPending}
+	failed := entities.EvaluationJob{ID: uuid.New(), EvaluationJobStatus: entities.EvaluationJobStatusFailed}
+
+	// the same values as a, b and c, reported by engines in other currencies or without currency
+	inDollars := finishedJob(20, "H04L")
+	inDollars.ID, inDollars.Currency = a.ID, "USD"
+	inDollars.Confidence = entities.ConfidenceInterval{Level: 0.9, Lower: 16, Upper: 30}
+	inYen := finishedJob(4000, "A61K")
+	inYen.ID, inYen.Currency = b.ID, "JPY"
+	inBase := c
+	inBase.Currency = ""
+
+	inEuros := a
+	inEuros.Confidence = entities.ConfidenceInterval{Level: 0.9, Lower: 8, Upper: 15}
+
+	testCases := []struct {
+		name     string
+		jobs     []entities.EvaluationJob
+		top      int
+		currency string
+		want     portfolios.Statistics
+		wantErr  error
+	}{
+		{
+			name: "no finished jobs",
+			jobs: []entities.EvaluationJob{pending, failed},
+			top:  3,
+			want: portfolios.Statistics{
+				Jobs:        2,
+				Pending:     1,
+				Failed:      1,
+				Currency:    "EUR",
+				Percentiles: map[int]float64{},
+			},
+		},
+		{
+			name: "finished jobs are aggregated, others counted",
+			jobs: []entities.EvaluationJob{a, pending, b, c, failed, d},
+			top:  2,
+			want: portfolios.Statistics{
+				Jobs:       6,
+				Pending:    1,
+				Finished:   4,
+				Failed:     1,
+				Currency:   "EUR",
+				TotalValue: 100,
+				MeanValue:  25,
+				Percentiles: map[int]float64{
+					10: 13,
+					25: 17.5,
+					50: 25,
+					75: 32.5,
+					90: 37,
+				},
+				ByTechnicalField: []portfolios.FieldStatistics{
+					{TechnicalField: "A61K", Count: 1, TotalValue: 40, MeanValue: 40},
+					{TechnicalField: "", Count: 1, TotalValue: 30, MeanValue: 30},
+					{TechnicalField: "H04L", Count: 2, TotalValue: 30, MeanValue: 15},
+				},
+				Top: []entities.EvaluationJob{b, d},
+			},
+		},
+		{
+			name: "single job",
+			jobs: []entities.EvaluationJob{a},
+			top:  10,
+			want: portfolios.Statistics{
+				Jobs:       1,
+				Finished:   1,
+				Currency:   "EUR",
+				TotalValue: 10,
+				MeanValue:  10,
+				Percentiles: map[int]float64{
+					10: 10,
+					25: 10,
+					50: 10,
+					75: 10,
+					90: 10,
+				},
+				ByTechnicalField: []portfolios.FieldStatistics{
+					{TechnicalField: "H04L", Count: 1, TotalValue: 10, MeanValue: 10},
+				},
+				Top: []entities.EvaluationJob{a},
+			},
+		},
+		{
+			name: "mixed currencies are converted before aggregating",
+			jobs: []entities.EvaluationJob{inDollars, inYen, inBase, d},
+			top:  4,
+			want: portfolios.Statistics{
+				Jobs:       4,
+				Finished:   4,
+				Currency:   "EUR",
+				TotalValue: 100,
+				MeanValue:  25,
+				Percentiles: map[int]float64{
+					10: 13,
+					25: 17.5,
+					50: 25,
+					75: 32.5,
+					90: 37,
+				},
+				ByTechnicalField: []portfolios.FieldStatistics{
+					{TechnicalField: "A61K", Count: 1, TotalValue: 40, MeanValue: 40},
+					{TechnicalField: "", Count: 1, TotalValue: 30, MeanValue: 30},
+					{TechnicalField: "H04L", Count: 2, TotalValue: 30, MeanValue: 15},
+				},
+				Top: []entities.EvaluationJob{b, d, c, inEuros},
+			},
+		},
+		{
+			name:     "in the requested currency",
+			jobs:     []entities.EvaluationJob{inDollars, a},
+			top:      1,
+			currency: "USD",
+			want: portfolios.Statistics{
+				Jobs:       2,
+				Finished:   2,
+				Currency:   "USD",
+				TotalValue: 40,
+				MeanValue:  20,
+				Percentiles: map[int]float64{
+					10: 20,
+					25: 20,
+					50: 20,
+					75: 20,
+					90: 20,
+				},
+				ByTechnicalField: []portfolios.FieldStatistics{
+					{TechnicalField: "H04L", Count: 2, TotalValue: 40, MeanValue: 20},
+				},
+				Top: []entities.EvaluationJob{inDollars},
+			},
+		},
+		{
+			name:     "currency missing in the table",
+			jobs:     []entities.EvaluationJob{inDollars},
+			currency: "GBP",
+			wantErr:  currency.ErrUnknownCurrency,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := tc.currency
+			if target == "" {
+				target = "EUR"
+			}
+
+			got, err := portfolios.ComputeStatistics(tc.jobs, tc.top, currencies(t), target)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			assert.InDeltaMapValues(t, tc.want.Percentiles, got.Percentiles, 1e-9)
+
+			tc.want.Percentiles, got.Percentiles = nil, nil
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_portfolioUseCase_GetPortfolioStatistics(t *testing.T) {
+	t.Parallel()
+
+	alice := &identity{id: "Alice"}
+	direct := finishedJob(10, "H04L")
+	batched := finishedJob(20, "H04L")
+	batch := entities.Batch{
+		ID:      uuid.New(),
+		OwnerID: "Alice",
+		Items:   []entities.BatchItem{{JobID: direct.ID}, {JobID: batched.ID}, {Rejection: "quota exceeded"}},
+	}
+	portfolio := entities.Portfolio{
+		ID:       uuid.New(),
+		OwnerID:  "Alice",
+		Name:     "Telecom",
+		JobIDs:   []uuid.UUID{direct.ID},
+		BatchIDs: []uuid.UUID{batch.ID},
+	}
+
+	inEuros, err := portfolios.ComputeStatistics([]entities.EvaluationJob{direct, batched}, 1, currencies(t), "EUR")
+	assert.NoError(t, err)
+
+	inDollars, err := portfolios.ComputeStatistics([]entities.EvaluationJob{direct, batched}, 1, currencies(t), "USD")
+	assert.NoError(t, err)
+
+	members := func(
+		portfolioService *mocks.PortfolioService,
+		queueService *patentsMocks.QueueService,
+		batchService *patentsMocks.BatchService,
+	) {
+		portfolioService.On("GetPortfolioByID", portfolio.ID).Return(portfolio, nil).Once()
+		batchService.On("GetBatchByID", batch.ID).Return(batch, nil).Once()
+		queueService.On("GetJobByID", direct.ID).Return(direct, nil).Once()
+		queueService.On("GetJobByID", batched.ID).Return(batched, nil).Once()
+	}
+
+	testCases := []struct {
+		name        string
+		preparation func(*mocks.PortfolioService, *patentsMocks.QueueService, *patentsMocks.BatchService)
+		identity    authorization.Identity
+		currency    string
+		want        portfolios.Statistics
+		wantErr     error
+	}{
+		{
+			name:        "jobs listed directly and via a batch are counted once",
+			preparation: members,
+			identity:    alice,
+			want:        inEuros,
+		},
+		{
+			name:        "in the requested currency",
+			preparation: members,
+			identity:    alice,
+			currency:    "usd",
+			want:        inDollars,
+		},
+		{
+			name: "unknown currency",
+			preparation: func(*mocks.PortfolioService, *patentsMocks.QueueService, *patentsMocks.BatchService) {
+			},
+			identity: alice,
+			currency: "XYZ",
+			wantErr:  currency.ErrUnknownCurrency,
+		},
+		{
+			name: "Bob does not get Alice's portfolio",
+			preparation: func(portfolioService *mocks.PortfolioService, _ *patentsMocks.QueueService,
+				_ *patentsMocks.BatchService,
+			) {
+				portfolioService.On("GetPortfolioByID", portfolio.ID).Return(portfolio, nil).Once()
+			},
+			identity: &identity{id: "Bob"},
+			wantErr:  portfolios.ErrPortfolioNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			portfolioService := new(mocks.PortfolioService)
+			queueService := new(patentsMocks.QueueService)
+			batchService := new(patentsMocks.BatchService)
+
+			tc.preparation(portfolioService, queueService, batchService)
+
+			useCase := portfolios.NewPortfolioUseCase(portfolioService, queueService, batchService, currencies(t))
+
+			got, err := useCase.GetPortfolioStatistics(tc.identity, portfolio.ID, 1, tc.currency)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.want, got)
+
+			portfolioService.AssertExpectations(t)
+			queueService.AssertExpectations(t)
+			batchService.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_portfolioUseCase_CreatePortfolio(t *testing.T) {
+	t.Parallel()
+
+	alicesJob := finishedJob(10, "")
+	bobsJob := finishedJob(10, "")
+	bobsJob.OwnerID = "Bob"
+
+	testCases := []struct {
+		name        string
+		preparation func(*mocks.PortfolioService, *patentsMocks.QueueService)
+		request     portfolios.CreatePortfolioRequest
+		wantErr     error
+	}{
+		{
+			name: "Alice creates a portfolio of her jobs",
+			preparation: func(portfolioService *mocks.PortfolioService, queueService *patentsMocks.QueueService) {
+				queueService.On("GetJobByID", alicesJob.ID).Return(alicesJob, nil).Once()
+				portfolioService.On("CreatePortfolio", mock.MatchedBy(func(p entities.Portfolio) bool {
+					return p.OwnerID == "Alice" && p.Name == "Mine"
+				})).Return(nil).Once()
+			},
+			request: portfolios.CreatePortfolioRequest{Name: "Mine", JobIDs: []uuid.UUID{alicesJob.ID}},
+		},
+		{
+			name: "Alice cannot add Bob's job",
+			preparation: func(_ *mocks.PortfolioService, queueService *patentsMocks.QueueService) {
+				queueService.On("GetJobByID", bobsJob.ID).Return(bobsJob, nil).Once()
+			},
+			request: portfolios.CreatePortfolioRequest{Name: "Mine", JobIDs: []uuid.UUID{bobsJob.ID}},
+			wantErr: portfolios.ErrUnknownMember,
+		},
+		{
+			name: "Alice cannot add a non-existent job",
+			preparation: func(_ *mocks.PortfolioService, queueService *patentsMocks.QueueService) {
+				queueService.On("GetJobByID", bobsJob.ID).Return(entities.EvaluationJob{}, patents.ErrJobNotFound).Once()
+			},
+			request: portfolios.CreatePortfolioRequest{Name: "Mine", JobIDs: []uuid.UUID{bobsJob.ID}},
+			wantErr: portfolios.ErrUnknownMember,
+		},
+		{
+			name:        "portfolio without members",
+			preparation: func(*mocks.PortfolioService, *patentsMocks.QueueService) {},
+			request:     portfolios.CreatePortfolioRequest{Name: "Empty"},
+			wantErr:     portfolios.ErrInvalidPortfolio,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			portfolioService := new(mocks.PortfolioService)
+			queueService := new(patentsMocks.QueueService)
+
+			tc.preparation(portfolioService, queueService)
+
+			useCase := portfolios.NewPortfolioUseCase(portfolioService, queueService, new(patentsMocks.BatchService),
+				currencies(t))
+
+			_, err := useCase.CreatePortfolio(&identity{id: "Alice"}, tc.request)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			portfolioService.AssertExpectations(t)
+			queueService.AssertExpectations(t)
+		})
+	}
+}
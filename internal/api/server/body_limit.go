@@ -0,0 +1,36 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const defaultBodyLimit = "2M"
+
+// routeBodyLimit applies the body limit configured for the matched route, or limit for all other routes.
+//
+// Route keys are compared case-insensitively, as the configuration lowercases map keys.
+func routeBodyLimit(limit string, routeLimits map[string]string) echo.MiddlewareFunc {
+	if limit == "" {
+		limit = defaultBodyLimit
+	}
+
+	defaultLimit := middleware.BodyLimit(limit)
+	limits := make(map[string]echo.MiddlewareFunc, len(routeLimits))
+
+	for route, routeLimit := range routeLimits {
+		limits[strings.ToLower(route)] = middleware.BodyLimit(routeLimit)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if routeLimit, ok := limits[strings.ToLower(c.Request().Method+" "+c.Path())]; ok {
+				return routeLimit(next)(c)
+			}
+
+			return defaultLimit(next)(c)
+		}
+	}
+}
@@ -0,0 +1,844 @@
+//nolint:funlen // Test functions are long, due to test cases
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/api/admin"
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
+	"github.com/MyChaOS87/patAi/internal/api/server"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/engines"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/report"
+	"github.com/MyChaOS87/patAi/internal/similarity"
+	"github.com/MyChaOS87/patAi/internal/simulation"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const (
+	specFile         = "../../../patAi.openapi3.yaml"
+	reportTemplate   = "../../../templates/report.html.tmpl"
+	currencyTable    = "../../../config/currencies.yml"
+	pdfDocument      = "../../pdftext/testdata/simple.pdf"
+	unsupportedXML   = "../../patentxml/testdata/unsupported.xml"
+	apiBasePath      = "/api/v0"
+	unknownID        = "0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11"
+	settleTimeout    = 5 * time.Second
+	settlePollPeriod = 10 * time.Millisecond
+
+	// the simulation grants 5 jobs per identity, so the identities split the work
+	ownerKey    = "owner"
+	batcherKey  = "batcher"
+	freeKey     = "free"
+	operatorKey = "operator"
+)
+
+var (
+	errUnknownAPIKey     = errors.New("unknown API key")
+	errEvaluationFailure = errors.New("evaluation failed")
+)
+
+type contractIdentity struct {
+	id       string
+	operator bool
+	plan     authorization.Plan
+}
+
+func (i *contractIdentity) GetID() string {
+	return i.id
+}
+
+func (i *contractIdentity) IsOperator() bool {
+	return i.operator
+}
+
+func (i *contractIdentity) GetPlan() authorization.Plan {
+	return i.plan
+}
+
+type contractIdentities map[string]authorization.Identity
+
+func (c contractIdentities) GetByAPIKey(key string) (authorization.Identity, error) {
+	identity, ok := c[key]
+	if !ok {
+		return nil, errUnknownAPIKey
+	}
+
+	return identity, nil
+}
+
+// failingEngine fills the dead-letter queue for the operator endpoints.
+type failingEngine struct{}
+
+func (failingEngine) Name() string {
+	return "failing"
+}
+
+func (failingEngine) Version() string {
+	return "1.0.0"
+}
+
+func (failingEngine) Evaluate(context.Context, entities.EvaluationJob) (entities.Valuation, error) {
+	return entities.Valuation{}, errEvaluationFailure
+}
+
+type contractServer struct {
+	handler  http.Handler
+	routes   []*echo.Route
+	document *openapi.Document
+
+	mutex  sync.Mutex
+	drifts []string
+}
+
+// newContractServer wires the API like cmd/patAi does, minimal leaves out the optional features.
+func newContractServer(t *testing.T, minimal bool) *contractServer {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := &config.Config{
+		API: config.APIConfig{
+			OpenAPIFile:       specFile,
+			ServerBaseURL:     "http://localhost",
+			IdempotencyKeyTTL: time.Minute,
+			MaxBatchSize:      10,
+			BodyLimit:         "64K",
+			RouteBodyLimits:   map[string]string{"POST /api/v0/patents": "256K"},
+			ValidateResponses: true,
+		},
+		Worker:      config.WorkerConfig{Count: 2, MaxAttempts: 1, DefaultExecutionTimeout: time.Second},
+		ResultCache: config.ResultCacheConfig{TTL: time.Hour},
+		Report: config.ReportConfig{
+			TemplateFile: reportTemplate,
+			Branding:     config.BrandingConfig{Name: "patAi", PrimaryColor: "#1f3a5f", AccentColor: "#e07a1f"},
+		},
+		Simulation: config.SimulationConfig{EvaluationDuration: 10 * time.Millisecond},
+	}
+
+	document, err := openapi.LoadDocument(cfg.API.OpenAPIFile, struct{ ServerBaseURL string }{})
+	assert.NoError(t, err)
+
+	patentSchema, err := document.Schema("PatentSubmission")
+	assert.NoError(t, err)
+
+	currencies, err := currency.Load(currencyTable)
+	assert.NoError(t, err)
+
+	backend := simulation.NewInMemoryQueueAndQuotaServiceSimulation()
+	registry := engines.NewRegistry(simulation.NewEngine(&cfg.Simulation),
+		simulation.NewWeightedEngine(&cfg.Simulation), failingEngine{})
+
+	options := []patents.UseCaseOption{
+		patents.WithResultCache(backend, &cfg.ResultCache),
+		patents.WithBatches(backend, cfg.API.MaxBatchSize),
+		patents.WithEngines(registry),
+	}
+
+	var reports patents.ReportRenderer
+
+	if !minimal {
+		options = append(options, patents.WithSimilarityIndex(similarity.NewIndex()), patents.WithSearch(backend))
+
+		reports, err = report.NewRenderer(&cfg.Report)
+		assert.NoError(t, err)
+	}
+
+	identities := contractIdentities{
+		ownerKey:    &contractIdentity{id: "owner-id", plan: authorization.PlanPro},
+		batcherKey:  &contractIdentity{id: "batcher-id", plan: authorization.PlanPro},
+		freeKey:     &contractIdentity{id: "free-id", plan: authorization.PlanFree},
+		operatorKey: &contractIdentity{id: "operator-id", operator: true, plan: authorization.PlanPro},
+	}
+
+	useCase := patents.NewValuationJobUseCase(backend, backend, options...)
+	handler := patents.NewHandler(useCase, patentSchema, currencies, reports)
+
+	s := &contractServer{document: document}
+
+	srv := server.NewServer(
+		server.API(&cfg.API),
+		server.ChildRouters(
+			patents.NewPatentsRouter(&cfg.API, identities, handler),
+			portfolios.NewPortfoliosRouter(identities,
+				portfolios.NewHandler(portfolios.NewPortfolioUseCase(backend, backend, backend, currencies))),
+			admin.NewAdminRouter(identities, admin.NewHandler(admin.NewDeadLetterUseCase(backend))),
+		),
+		server.OpenAPIDocument(document),
+		server.ProblemTypes(patents.ProblemTypes()...),
+		server.ProblemTypes(portfolios.ProblemTypes()...),
+		server.ProblemTypes(admin.ProblemTypes()...),
+		server.OnResponseDrift(func(c echo.Context, err error) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			s.drifts = append(s.drifts, fmt.Sprintf("%s %s: %v", c.Request().Method, c.Request().URL, err))
+		}),
+	)
+
+	s.handler, err = srv.Handler()
+	assert.NoError(t, err)
+
+	s.routes = srv.Routes()
+
+	go worker.NewWorker(&cfg.Worker, backend, registry).Run(ctx)
+
+	return s
+}
+
+type contractRequest struct {
+	key         string
+	header      map[string]string
+	contentType string
+	body        string
+}
+
+func (s *contractServer) do(method string, target string, request contractRequest) *httptest.ResponseRecorder {
+	httpRequest := httptest.NewRequest(method, apiBasePath+target, strings.NewReader(request.body))
+
+	if request.key != "" {
+		httpRequest.Header.Set("X-API-Key", request.key)
+	}
+
+	if request.contentType != "" {
+		httpRequest.Header.Set(echo.HeaderContentType, request.contentType)
+	}
+
+	for name, value := range request.header {
+		httpRequest.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, httpRequest)
+
+	return recorder
+}
+
+func (s *contractServer) takeDrifts() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	drifts := s.drifts
+	s.drifts = nil
+
+	return drifts
+}
+
+// example encodes the generated example of a documented request body, properties in set replace the generated ones.
+func (s *contractServer) example(t *testing.T, method string, path string, mediaType string, set map[string]any) string {
+	t.Helper()
+
+	operation := s.document.Paths[path][strings.ToLower(method)]
+	if !assert.NotNil(t, operation, "%s %s", method, path) || !assert.NotNil(t, operation.RequestBody) {
+		return ""
+	}
+
+	content := operation.RequestBody.Content[mediaType]
+	if !assert.NotNil(t, content, "%s %s %s", method, path, mediaType) {
+		return ""
+	}
+
+	example := s.document.Example(content.Schema)
+
+	if properties, ok := example.(map[string]any); ok {
+		for name, value := range set {
+			properties[name] = value
+		}
+	}
+
+	encoded, err := json.Marshal(example)
+	assert.NoError(t, err)
+
+	return string(encoded)
+}
+
+// create submits a fixture and returns the ID of the created job or batch.
+func (s *contractServer) create(t *testing.T, target string, request contractRequest) string {
+	t.Helper()
+
+	recorder := s.do(http.MethodPost, target, request)
+	if !assert.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String()) {
+		return ""
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+
+	return created.ID
+}
+
+// awaitStatus waits for the workers to settle a job.
+func (s *contractServer) awaitStatus(t *testing.T, key string, id string, status string) {
+	t.Helper()
+
+	deadline := time.Now().Add(settleTimeout)
+
+	for time.Now().Before(deadline) {
+		var job struct {
+			Status string `json:"status"`
+		}
+
+		recorder := s.do(http.MethodGet, "/patents/"+id, contractRequest{key: key})
+		if recorder.Code == http.StatusOK && json.Unmarshal(recorder.Body.Bytes(), &job) == nil && job.Status == status {
+			return
+		}
+
+		time.Sleep(settlePollPeriod)
+	}
+
+	t.Errorf("job %s did not become %s within %s", id, status, settleTimeout)
+}
+
+func pdfUpload(t *testing.T) contractRequest {
+	t.Helper()
+
+	content, err := os.ReadFile(pdfDocument)
+	assert.NoError(t, err)
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "simple.pdf")
+	assert.NoError(t, err)
+
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteField("title", "Uploaded patent"))
+	assert.NoError(t, writer.Close())
+
+	return contractRequest{key: ownerKey, contentType: writer.FormDataContentType(), body: body.String()}
+}
+
+// createFixtures submits the jobs, batch and portfolio the contract cases refer to by placeholder, e.g. {job}.
+func (s *contractServer) createFixtures(t *testing.T) *strings.Replacer {
+	t.Helper()
+
+	xml, err := os.ReadFile(unsupportedXML)
+	assert.NoError(t, err)
+
+	// three of the owner's five jobs
+	job := s.create(t, "/patents?patentKey=EP1234567", contractRequest{
+		key: ownerKey, contentType: echo.MIMEApplicationJSON,
+		body: `{"title": "Contract", "claims": ["1. A method for valuing patents."], "publicationNumber": "EP1234567A1"}`,
+	})
+	amendedJob := s.create(t, "/patents", contractRequest{
+		key: ownerKey, contentType: echo.MIMEApplicationJSON,
+		body: `{"title": "Contract", "claims": ["1. A method for valuing granted patents."], ` +
+			`"publicationNumber": "EP1234567B1"}`,
+	})
+	documentJob := s.create(t, "/patents", pdfUpload(t))
+	batch := s.create(t, "/patents/batch", contractRequest{
+		key: batcherKey, contentType: echo.MIMEApplicationJSON, body: `[{"content": "A batched patent"}]`,
+	})
+	freeJob := s.create(t, "/patents", contractRequest{key: freeKey, contentType: "text/plain", body: "A free patent"})
+	failedJob := s.create(t, "/patents?engine=failing", contractRequest{
+		key: operatorKey, contentType: "text/plain", body: "A failing patent",
+	})
+	portfolio := s.create(t, "/portfolios", contractRequest{
+		key: ownerKey, contentType: echo.MIMEApplicationJSON, body: `{"name": "Contract", "jobIds": ["` + job + `"]}`,
+	})
+
+	for _, id := range []string{job, amendedJob, documentJob} {
+		s.awaitStatus(t, ownerKey, id, "finished")
+	}
+
+	s.awaitStatus(t, operatorKey, failedJob, "failed")
+
+	return strings.NewReplacer(
+		"{job}", job, "{amendedJob}", amendedJob, "{documentJob}", documentJob, "{batch}", batch,
+		"{freeJob}", freeJob, "{failedJob}", failedJob, "{portfolio}", portfolio, "{unknown}", unknownID,
+		"{unsupportedXML}", string(xml),
+	)
+}
+
+type contractCase struct {
+	// operation is the method and path as documented, e.g. GET /patents/{patentId}
+	operation string
+	status    int
+	// target is the path and query below the base path, placeholders like {job} are replaced by the fixtures
+	target  string
+	request contractRequest
+	// minimal runs the case against a server without the optional features
+	minimal bool
+}
+
+// TestContract exercises every documented status of every operation against the wired server, in order: the cases
+// share the backend, so that the quota cases come after the jobs using up the quota. Each response has to match the
+// document, and statuses that are documented but not exercised fail the test.
+func TestContract(t *testing.T) {
+	t.Parallel()
+
+	s := newContractServer(t, false)
+	minimal := newContractServer(t, true)
+	fixtures := s.createFixtures(t)
+
+	owner := contractRequest{key: ownerKey}
+	operator := contractRequest{key: operatorKey}
+	jsonBody := func(key string, body string) contractRequest {
+		return contractRequest{key: key, contentType: echo.MIMEApplicationJSON, body: body}
+	}
+	withIdempotencyKey := func(request contractRequest, idempotencyKey string) contractRequest {
+		request.header = map[string]string{"Idempotency-Key": idempotencyKey}
+
+		return request
+	}
+
+	testCases := []contractCase{
+		// patents
+		{operation: "GET /patents", status: http.StatusOK, target: "/patents?label=missing&tag=x", request: owner},
+		{operation: "GET /patents", status: http.StatusOK, target: "/patents?q=valuing", request: owner},
+		{operation: "GET /patents", status: http.StatusBadRequest, target: "/patents?currency=XXX", request: owner},
+		{operation: "GET /patents", status: http.StatusNotImplemented, target: "/patents?q=x", request: owner, minimal: true},
+		{
+			operation: "POST /patents", status: http.StatusCreated, target: "/patents",
+			request: jsonBody(ownerKey, s.example(t, http.MethodPost, "/patents", echo.MIMEApplicationJSON, nil)),
+		},
+		{
+			operation: "POST /patents", status: http.StatusBadRequest, target: "/patents?priority=urgent",
+			request: contractRequest{key: ownerKey, contentType: "text/plain", body: "A patent"},
+		},
+		{
+			operation: "POST /patents", status: http.StatusRequestEntityTooLarge, target: "/patents",
+			request: contractRequest{key: ownerKey, contentType: "text/plain", body: strings.Repeat("x", 300*1024)},
+		},
+		{
+			operation: "POST /patents", status: http.StatusUnsupportedMediaType, target: "/patents",
+			request: contractRequest{key: ownerKey, contentType: "application/xml", body: "{unsupportedXML}"},
+		},
+		{
+			operation: "POST /patents", status: http.StatusForbidden, target: "/patents?priority=high",
+			request: withIdempotencyKey(contractRequest{key: freeKey, contentType: "text/plain", body: "A patent"}, "k1"),
+		},
+		{
+			operation: "POST /patents", status: http.StatusUnprocessableEntity, target: "/patents?priority=normal",
+			request: withIdempotencyKey(contractRequest{key: freeKey, contentType: "text/plain", body: "A patent"}, "k1"),
+		},
+		{operation: "GET /patents/export", status: http.StatusOK, target: "/patents/export", request: owner},
+		{
+			operation: "GET /patents/export", status: http.StatusOK, target: "/patents/export?format=ndjson&q=valuing",
+			request: owner,
+		},
+		{operation: "GET /patents/export", status: http.StatusBadRequest, target: "/patents/export?format=pdf", request: owner},
+		{
+			operation: "GET /patents/export", status: http.StatusNotAcceptable, target: "/patents/export",
+			request: contractRequest{key: ownerKey, header: map[string]string{echo.HeaderAccept: "image/png"}},
+		},
+		{
+			operation: "GET /patents/export", status: http.StatusNotImplemented, target: "/patents/export?q=x",
+			request: owner, minimal: true,
+		},
+		{operation: "GET /patents/{patentId}", status: http.StatusOK, target: "/patents/{job}?currency=USD", request: owner},
+		{operation: "GET /patents/{patentId}", status: http.StatusBadRequest, target: "/patents/not-a-uuid", request: owner},
+		{operation: "GET /patents/{patentId}", status: http.StatusNotFound, target: "/patents/{unknown}", request: owner},
+		{
+			operation: "PATCH /patents/{patentId}", status: http.StatusOK, target: "/patents/{job}",
+			request: jsonBody(ownerKey, s.example(t, http.MethodPatch, "/patents/{patentId}", echo.MIMEApplicationJSON, nil)),
+		},
+		{
+			operation: "PATCH /patents/{patentId}", status: http.StatusBadRequest, target: "/patents/{job}",
+			request: jsonBody(ownerKey, `{"tags": "urgent"}`),
+		},
+		{
+			operation: "PATCH /patents/{patentId}", status: http.StatusNotFound, target: "/patents/{unknown}",
+			request: jsonBody(ownerKey, `{}`),
+		},
+		{operation: "GET /patents/{patentId}/claims", status: http.StatusOK, target: "/patents/{job}/claims", request: owner},
+		{
+			operation: "GET /patents/{patentId}/claims", status: http.StatusBadRequest, target: "/patents/not-a-uuid/claims",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/claims", status: http.StatusNotFound, target: "/patents/{unknown}/claims",
+			request: owner,
+		},
+		{operation: "GET /patents/{patentId}/report", status: http.StatusOK, target: "/patents/{job}/report", request: owner},
+		{
+			operation: "GET /patents/{patentId}/report", status: http.StatusBadRequest, target: "/patents/not-a-uuid/report",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/report", status: http.StatusNotFound, target: "/patents/{unknown}/report",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/report", status: http.StatusNotImplemented, target: "/patents/{unknown}/report",
+			request: owner, minimal: true,
+		},
+		{
+			operation: "GET /patents/{patentId}/document", status: http.StatusOK, target: "/patents/{documentJob}/document",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/document", status: http.StatusBadRequest,
+			target: "/patents/not-a-uuid/document", request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/document", status: http.StatusNotFound, target: "/patents/{job}/document",
+			request: owner,
+		},
+		{operation: "GET /patents/{patentId}/similar", status: http.StatusOK, target: "/patents/{job}/similar", request: owner},
+		{
+			operation: "GET /patents/{patentId}/similar", status: http.StatusBadRequest, target: "/patents/{job}/similar?limit=0",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/similar", status: http.StatusNotFound, target: "/patents/{unknown}/similar",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/similar", status: http.StatusNotImplemented,
+			target: "/patents/{unknown}/similar", request: owner, minimal: true,
+		},
+		// revaluations, the fifth job of the owner comes last before the quota cases
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusBadRequest,
+			target: "/patents/not-a-uuid/revalue", request: owner,
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusNotFound, target: "/patents/{unknown}/revalue",
+			request: withIdempotencyKey(owner, "k2"),
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusUnprocessableEntity,
+			target: "/patents/{unknown}/revalue?priority=low", request: withIdempotencyKey(owner, "k2"),
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusForbidden,
+			target: "/patents/{freeJob}/revalue?priority=high&engineVersion=2.0.0", request: contractRequest{key: freeKey},
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusConflict,
+			target: "/patents/{job}/revalue?engineVersion=1.0.0", request: owner,
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusCreated,
+			target: "/patents/{job}/revalue?engineVersion=2.0.0", request: owner,
+		},
+		{
+			operation: "POST /patents/{patentId}/revalue", status: http.StatusTooManyRequests,
+			target: "/patents/{amendedJob}/revalue?engineVersion=2.0.0", request: owner,
+		},
+		{
+			operation: "POST /patents", status: http.StatusTooManyRequests, target: "/patents",
+			request: contractRequest{key: ownerKey, contentType: "text/plain", body: "One patent too many"},
+		},
+		{
+			operation: "GET /patents/{patentId}/revaluations", status: http.StatusOK, target: "/patents/{job}/revaluations",
+			request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/revaluations", status: http.StatusBadRequest,
+			target: "/patents/not-a-uuid/revaluations", request: owner,
+		},
+		{
+			operation: "GET /patents/{patentId}/revaluations", status: http.StatusNotFound,
+			target: "/patents/{unknown}/revaluations", request: owner,
+		},
+		// batches
+		{
+			operation: "POST /patents/batch", status: http.StatusCreated, target: "/patents/batch",
+			request: jsonBody(batcherKey, s.example(t, http.MethodPost, "/patents/batch", echo.MIMEApplicationJSON, nil)),
+		},
+		{
+			operation: "POST /patents/batch", status: http.StatusBadRequest, target: "/patents/batch",
+			request: jsonBody(batcherKey, `[]`),
+		},
+		{
+			operation: "POST /patents/batch", status: http.StatusUnsupportedMediaType, target: "/patents/batch",
+			request: contractRequest{key: batcherKey, contentType: "text/csv", body: "content"},
+		},
+		{
+			operation: "POST /patents/batch", status: http.StatusForbidden, target: "/patents/batch",
+			request: withIdempotencyKey(jsonBody(freeKey, `[{"content": "A patent", "priority": "high"}]`), "k3"),
+		},
+		{
+			operation: "POST /patents/batch", status: http.StatusUnprocessableEntity, target: "/patents/batch",
+			request: withIdempotencyKey(jsonBody(freeKey, `[{"content": "A patent"}]`), "k3"),
+		},
+		{
+			operation: "POST /patents/batch", status: http.StatusTooManyRequests, target: "/patents/batch",
+			request: jsonBody(ownerKey, `[{"content": "One batch too many"}]`),
+		},
+		{operation: "GET /batches/{batchId}", status: http.StatusOK, target: "/batches/{batch}", request: contractRequest{
+			key: batcherKey,
+		}},
+		{operation: "GET /batches/{batchId}", status: http.StatusBadRequest, target: "/batches/not-a-uuid", request: owner},
+		{operation: "GET /batches/{batchId}", status: http.StatusNotFound, target: "/batches/{batch}", request: owner},
+		// engines and histories
+		{operation: "GET /engines", status: http.StatusOK, target: "/engines", request: owner},
+		{operation: "GET /histories/{patentKey}", status: http.StatusOK, target: "/histories/EP1234567", request: owner},
+		{
+			operation: "GET /histories/{patentKey}", status: http.StatusBadRequest, target: "/histories/EP1234567?currency=XXX",
+			request: owner,
+		},
+		{operation: "GET /histories/{patentKey}", status: http.StatusNotFound, target: "/histories/EP7654321", request: owner},
+		{
+			operation: "GET /histories/{patentKey}/diff", status: http.StatusOK, target: "/histories/EP1234567/diff",
+			request: owner,
+		},
+		{
+			operation: "GET /histories/{patentKey}/diff", status: http.StatusBadRequest,
+			target: "/histories/EP1234567/diff?from=not-a-uuid", request: owner,
+		},
+		{
+			operation: "GET /histories/{patentKey}/diff", status: http.StatusNotFound, target: "/histories/EP7654321/diff",
+			request: owner,
+		},
+		// portfolios
+		{operation: "GET /portfolios", status: http.StatusOK, target: "/portfolios", request: owner},
+		{
+			operation: "POST /portfolios", status: http.StatusCreated, target: "/portfolios",
+			request: jsonBody(ownerKey, s.example(t, http.MethodPost, "/portfolios", echo.MIMEApplicationJSON,
+				map[string]any{"jobIds": []string{"{job}"}})),
+		},
+		{
+			operation: "POST /portfolios", status: http.StatusBadRequest, target: "/portfolios",
+			request: jsonBody(ownerKey, `{"name": "Unknown jobs", "jobIds": ["{unknown}"]}`),
+		},
+		{operation: "GET /portfolios/{portfolioId}", status: http.StatusOK, target: "/portfolios/{portfolio}", request: owner},
+		{
+			operation: "GET /portfolios/{portfolioId}", status: http.StatusBadRequest, target: "/portfolios/not-a-uuid",
+			request: owner,
+		},
+		{
+			operation: "GET /portfolios/{portfolioId}", status: http.StatusNotFound, target: "/portfolios/{unknown}",
+			request: owner,
+		},
+		{
+			operation: "GET /portfolios/{portfolioId}/statistics", status: http.StatusOK,
+			target: "/portfolios/{portfolio}/statistics", request: owner,
+		},
+		{
+			operation: "GET /portfolios/{portfolioId}/statistics", status: http.StatusBadRequest,
+			target: "/portfolios/not-a-uuid/statistics", request: owner,
+		},
+		{
+			operation: "GET /portfolios/{portfolioId}/statistics", status: http.StatusNotFound,
+			target: "/portfolios/{unknown}/statistics", request: owner,
+		},
+		// operator endpoints, requeuing comes last as it takes the job out of the dead-letter queue
+		{operation: "GET /admin/dead-letters", status: http.StatusOK, target: "/admin/dead-letters", request: operator},
+		{operation: "GET /admin/dead-letters", status: http.StatusForbidden, target: "/admin/dead-letters", request: owner},
+		{
+			operation: "GET /admin/dead-letters/{jobId}", status: http.StatusOK, target: "/admin/dead-letters/{failedJob}",
+			request: operator,
+		},
+		{
+			operation: "GET /admin/dead-letters/{jobId}", status: http.StatusBadRequest, target: "/admin/dead-letters/not-a-uuid",
+			request: operator,
+		},
+		{
+			operation: "GET /admin/dead-letters/{jobId}", status: http.StatusForbidden, target: "/admin/dead-letters/{failedJob}",
+			request: owner,
+		},
+		{
+			operation: "GET /admin/dead-letters/{jobId}", status: http.StatusNotFound, target: "/admin/dead-letters/{unknown}",
+			request: operator,
+		},
+		{
+			operation: "POST /admin/dead-letters/{jobId}/requeue", status: http.StatusBadRequest,
+			target: "/admin/dead-letters/not-a-uuid/requeue", request: operator,
+		},
+		{
+			operation: "POST /admin/dead-letters/{jobId}/requeue", status: http.StatusForbidden,
+			target: "/admin/dead-letters/{failedJob}/requeue", request: owner,
+		},
+		{
+			operation: "POST /admin/dead-letters/{jobId}/requeue", status: http.StatusNotFound,
+			target: "/admin/dead-letters/{unknown}/requeue", request: operator,
+		},
+		{
+			operation: "POST /admin/dead-letters/{jobId}/requeue", status: http.StatusOK,
+			target: "/admin/dead-letters/{failedJob}/requeue", request: operator,
+		},
+		// infrastructure
+		{operation: "GET /health", status: http.StatusOK, target: "/health"},
+	}
+
+	testCases = append(testCases, unauthenticatedCases(s.document)...)
+
+	exercised := map[string]bool{}
+
+	for _, tc := range testCases {
+		tc := tc
+		exercised[tc.operation+" "+strconv.Itoa(tc.status)] = true
+
+		t.Run(fmt.Sprintf("%s %d %s", tc.operation, tc.status, tc.target), func(t *testing.T) {
+			target := s
+			if tc.minimal {
+				target = minimal
+			}
+
+			method, _, _ := strings.Cut(tc.operation, " ")
+
+			request := tc.request
+			request.body = fixtures.Replace(request.body)
+
+			recorder := target.do(method, fixtures.Replace(tc.target), request)
+
+			assert.Equal(t, tc.status, recorder.Code, recorder.Body.String())
+
+			if tc.status >= http.StatusBadRequest {
+				assert.Equal(t, problem.MIMEType, recorder.Header().Get(echo.HeaderContentType))
+				assert.NotContains(t, recorder.Body.String(), "use case error", "details must not reveal internal layers")
+			}
+
+			if tc.status == http.StatusTooManyRequests {
+				assert.NotEmpty(t, recorder.Header().Get(echo.HeaderRetryAfter), "quota problems tell when to retry")
+			}
+
+			assert.Empty(t, target.takeDrifts())
+		})
+	}
+
+	for _, documented := range documentedStatuses(s.document) {
+		assert.True(t, exercised[documented], "%s is documented but not exercised", documented)
+	}
+}
+
+// unauthenticatedCases sends every operation requiring an API key without one.
+func unauthenticatedCases(document *openapi.Document) []contractCase {
+	var testCases []contractCase
+
+	for _, operation := range documentedOperations(document) {
+		method, path, _ := strings.Cut(operation, " ")
+		if len(document.Paths[path][strings.ToLower(method)].Security) == 0 {
+			continue
+		}
+
+		testCases = append(testCases, contractCase{
+			operation: operation,
+			status:    http.StatusUnauthorized,
+			target:    pathParameter.ReplaceAllString(path, unknownID),
+		})
+	}
+
+	return testCases
+}
+
+//nolint:gochecknoglobals // compiled once for all tests
+var pathParameter = regexp.MustCompile(`\{[^}]+\}`)
+
+// documentedOperations lists the operations as method and path, e.g. GET /patents/{patentId}, in a stable order.
+func documentedOperations(document *openapi.Document) []string {
+	var operations []string
+
+	for path, item := range document.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	slices.Sort(operations)
+
+	return operations
+}
+
+func documentedStatuses(document *openapi.Document) []string {
+	var statuses []string
+
+	for _, operation := range documentedOperations(document) {
+		method, path, _ := strings.Cut(operation, " ")
+
+		for status := range document.Paths[path][strings.ToLower(method)].Responses {
+			statuses = append(statuses, operation+" "+status)
+		}
+	}
+
+	slices.Sort(statuses)
+
+	return statuses
+}
+
+// TestContract_Routes fails for routes that are not documented and for documented operations without route; path
+// parameters are compared by position, as the routes name them differently than the document.
+func TestContract_Routes(t *testing.T) {
+	t.Parallel()
+
+	s := newContractServer(t, false)
+
+	var routes []string
+
+	for _, route := range s.routes {
+		path, ok := strings.CutPrefix(route.Path, apiBasePath)
+		// routes below /openapi serve the document itself, echo adds routes for its not found handling
+		if !ok || strings.HasPrefix(path, "/openapi") || route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		routes = append(routes, route.Method+" "+routeParameter.ReplaceAllString(path, "{}"))
+	}
+
+	var operations []string
+
+	for _, operation := range documentedOperations(s.document) {
+		operations = append(operations, pathParameter.ReplaceAllString(operation, "{}"))
+	}
+
+	slices.Sort(routes)
+	routes = slices.Compact(routes)
+
+	assert.Equal(t, operations, routes)
+}
+
+func TestContract_CORSPreflight(t *testing.T) {
+	t.Parallel()
+
+	s := newContractServer(t, false)
+
+	var methods []string
+
+	for _, route := range s.routes {
+		if strings.HasPrefix(route.Path, apiBasePath) && route.Method != echo.RouteNotFound {
+			methods = append(methods, route.Method)
+		}
+	}
+
+	slices.Sort(methods)
+
+	for _, method := range slices.Compact(methods) {
+		method := method
+		t.Run(method, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := s.do(http.MethodOptions, "/patents/"+unknownID, contractRequest{header: map[string]string{
+				echo.HeaderOrigin:                     "https://app.example",
+				echo.HeaderAccessControlRequestMethod: method,
+			}})
+
+			assert.Equal(t, http.StatusNoContent, recorder.Code)
+			assert.Contains(t, strings.Split(recorder.Header().Get(echo.HeaderAccessControlAllowMethods), ","), method)
+		})
+	}
+}
+
+//nolint:gochecknoglobals // compiled once for all tests
+var routeParameter = regexp.MustCompile(`:[^/]+`)
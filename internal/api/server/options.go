@@ -1,8 +1,12 @@
 package server
 
 import (
+	"github.com/labstack/echo/v4"
+
 	"github.com/MyChaOS87/patAi/config"
 	"github.com/MyChaOS87/patAi/internal/api/router"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
 )
 
 type (
@@ -10,6 +14,10 @@ type (
 	Config struct {
 		api          *config.APIConfig
 		childRouters []router.Router
+		problemTypes []problem.Mapping
+		document     *openapi.Document
+		// onResponseDrift is called with the deviations of responses from the document, see openapi.ValidatorConfig
+		onResponseDrift func(echo.Context, error)
 	}
 )
 
@@ -28,3 +36,25 @@ func ChildRouters(routers ...router.Router) Option {
 		c.childRouters = append(c.childRouters, routers...)
 	}
 }
+
+// ProblemTypes maps domain errors to the problem types they are reported as, errors that are not mapped are internal
+// server errors.
+func ProblemTypes(mappings ...problem.Mapping) Option {
+	return func(c *Config) {
+		c.problemTypes = append(c.problemTypes, mappings...)
+	}
+}
+
+// OpenAPIDocument validates requests, and with API.ValidateResponses responses, against the document.
+func OpenAPIDocument(document *openapi.Document) Option {
+	return func(c *Config) {
+		c.document = document
+	}
+}
+
+// OnResponseDrift replaces logging the deviations of responses from the OpenAPI document, e.g. to fail tests on them.
+func OnResponseDrift(onDrift func(c echo.Context, err error)) Option {
+	return func(c *Config) {
+		c.onResponseDrift = onDrift
+	}
+}
@@ -2,12 +2,14 @@ package server
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 
 	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
 )
 
 const (
@@ -15,19 +17,42 @@ const (
 	v0Health  = "health"
 	V0OpenAPI = "openapi"
 
-	bodyLimit = "2M"
+	// ProblemTypeURIPrefix precedes the codes of the problem types, the type URIs stay the same across deployments.
+	ProblemTypeURIPrefix = "urn:patai:problem:"
 )
 
 func (s *Server) mapHandlers() error {
+	problemTypes := s.problemTypes
+	if s.document != nil {
+		problemTypes = append(problemTypes, openapi.ProblemTypes()...)
+	}
+
+	s.echo.HTTPErrorHandler = problem.NewErrorHandler(problem.Config{
+		TypeURIPrefix:        ProblemTypeURIPrefix,
+		Mappings:             problemTypes,
+		ExposeInternalErrors: s.api.ExposeInternalErrors,
+	})
+
 	s.echo.Use(middleware.RequestID())
 	s.echo.Use(middleware.Secure())
-	s.echo.Use(middleware.BodyLimit(bodyLimit))
+	s.echo.Use(routeBodyLimit(s.api.BodyLimit, s.api.RouteBodyLimits))
 	s.echo.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: s.api.AllowedOrigins,
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		AllowHeaders: []string{
+			echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "Idempotency-Key",
+			"Accept-Currency",
+		},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
 	}))
 
+	if s.document != nil {
+		s.echo.Use(s.document.Validator(openapi.ValidatorConfig{
+			BasePath:          strings.TrimSuffix(v0BaseURI, "/"),
+			ValidateResponses: s.api.ValidateResponses,
+			OnResponseDrift:   s.onDrift,
+		}))
+	}
+
 	v0 := s.echo.Group(v0BaseURI)
 
 	// health
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
@@ -10,6 +11,8 @@ import (
 	"github.com/MyChaOS87/patAi/config"
 	"github.com/MyChaOS87/patAi/internal/api/router"
 	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
 )
 
 const (
@@ -20,6 +23,12 @@ type Server struct {
 	api          *config.APIConfig
 	echo         *echo.Echo
 	childRouters []router.Router
+	problemTypes []problem.Mapping
+	document     *openapi.Document
+	onDrift      func(echo.Context, error)
+
+	mapOnce sync.Once
+	mapErr  error
 }
 
 func NewServer(options ...Option) *Server {
@@ -31,10 +40,28 @@ func NewServer(options ...Option) *Server {
 	return &Server{
 		echo:         echo.New(),
 		childRouters: cfg.childRouters,
+		problemTypes: cfg.problemTypes,
+		document:     cfg.document,
+		onDrift:      cfg.onResponseDrift,
 		api:          cfg.api,
 	}
 }
 
+// Handler maps the routes on first use and returns the handler Run serves, so that the API can be tested without a
+// listening socket.
+func (s *Server) Handler() (http.Handler, error) {
+	s.mapOnce.Do(func() {
+		s.mapErr = s.mapHandlers()
+	})
+
+	return s.echo, s.mapErr
+}
+
+// Routes returns the mapped routes.
+func (s *Server) Routes() []*echo.Route {
+	return s.echo.Routes()
+}
+
 // Run runs the server.
 func (s *Server) Run(ctx context.Context) error {
 	server := &http.Server{
@@ -45,7 +72,7 @@ func (s *Server) Run(ctx context.Context) error {
 		MaxHeaderBytes:    maxHeaderBytes,
 	}
 
-	if err := s.mapHandlers(); err != nil {
+	if _, err := s.Handler(); err != nil {
 		return err
 	}
 
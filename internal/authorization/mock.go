@@ -4,10 +4,15 @@ import "github.com/MyChaOS87/patAi/pkg/middleware"
 
 type Identity interface {
 	GetID() string
+	// IsOperator reports whether the identity may use the operator endpoints
+	IsOperator() bool
+	GetPlan() Plan
 }
 
 type identity struct {
-	id string
+	id       string
+	operator bool
+	plan     Plan
 }
 
 type provider struct{}
@@ -18,16 +23,33 @@ func (i *identity) GetID() string {
 	return i.id
 }
 
+func (i *identity) IsOperator() bool {
+	return i.operator
+}
+
+func (i *identity) GetPlan() Plan {
+	return i.plan
+}
+
 // Static mock as this is out of scope for this example.
 func (a provider) GetByAPIKey(key string) (Identity, error) {
-	if key == "user2" {
+	switch key {
+	case "user2":
+		return &identity{
+			id:   "mock-user2-id",
+			plan: PlanPro,
+		}, nil
+	case "operator":
 		return &identity{
-			id: "mock-user2-id",
+			id:       "mock-operator-id",
+			operator: true,
+			plan:     PlanPro,
 		}, nil
 	}
 
 	return &identity{
-		id: "mock-default-id",
+		id:   "mock-default-id",
+		plan: PlanFree,
 	}, nil
 }
 
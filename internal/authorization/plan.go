@@ -0,0 +1,25 @@
+package authorization
+
+import "github.com/MyChaOS87/patAi/internal/entities"
+
+// Plan is the subscription an identity is on; it bounds how its jobs are scheduled.
+type Plan struct {
+	Name        string
+	MaxPriority entities.JobPriority
+	// SchedulingWeight is the share of the workers relative to other identities with queued jobs
+	SchedulingWeight int
+}
+
+//nolint:gochecknoglobals // static plans as billing is out of scope for this example
+var (
+	PlanFree = Plan{
+		Name:             "free",
+		MaxPriority:      entities.JobPriorityNormal,
+		SchedulingWeight: 1,
+	}
+	PlanPro = Plan{
+		Name:             "pro",
+		MaxPriority:      entities.JobPriorityHigh,
+		SchedulingWeight: 2,
+	}
+)
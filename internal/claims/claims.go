@@ -0,0 +1,96 @@
+// Package claims parses patent claims and analyzes their structure: which claims are independent, which claims
+// they refer to, the resulting dependency tree and metrics derived from it.
+package claims
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var (
+	claimStart = regexp.MustCompile(`^(\d+)\s*[.)]\s+`)
+	// claimReference matches "claim 3", "claims 1 or 2", "claims 1, 4 and 5" and ranges like "claims 1 to 3"
+	claimReference = regexp.MustCompile(
+		`(?i)\bclaims?\s+(\d+(?:\s*(?:,|or|and|to|-|–)\s*\d+)*)`,
+	)
+	claimRange      = regexp.MustCompile(`(\d+)\s*(?:to|-|–)\s*(\d+)`)
+	number          = regexp.MustCompile(`\d+`)
+	precedingClaims = regexp.MustCompile(`(?i)\b(?:any\s+(?:one\s+)?of\s+the\s+preceding\s+claims|any\s+preceding\s+claim)`)
+)
+
+// Parse splits text into numbered claims, e.g. the claims section of a patent.
+//
+// A claim starts at a line numbered one above the previous claim, starting at 1, other lines continue the current
+// claim. Text before the first claim is ignored. Dependencies are taken from the references in the claim texts.
+func Parse(text string) []entities.Claim {
+	var result []entities.Claim
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if match := claimStart.FindStringSubmatch(line); match != nil {
+			if claimNumber, err := strconv.Atoi(match[1]); err == nil && claimNumber == len(result)+1 {
+				result = append(result, entities.Claim{Number: claimNumber, Text: line})
+
+				continue
+			}
+		}
+
+		if len(result) > 0 {
+			result[len(result)-1].Text += " " + line
+		}
+	}
+
+	for i := range result {
+		result[i].DependsOn = References(result[i])
+	}
+
+	return result
+}
+
+// References finds the preceding claims a claim refers to, e.g. "according to claim 1 or 2", "of claims 1 to 3" or
+// "according to any one of the preceding claims". References to the claim itself or later claims are ignored.
+func References(claim entities.Claim) []int {
+	var result []int
+
+	add := func(referenced int) {
+		if referenced > 0 && referenced < claim.Number && !slices.Contains(result, referenced) {
+			result = append(result, referenced)
+		}
+	}
+
+	if precedingClaims.MatchString(claim.Text) {
+		for referenced := 1; referenced < claim.Number; referenced++ {
+			add(referenced)
+		}
+	}
+
+	for _, match := range claimReference.FindAllStringSubmatch(claim.Text, -1) {
+		list := match[1]
+
+		for _, rangeMatch := range claimRange.FindAllStringSubmatch(list, -1) {
+			from, _ := strconv.Atoi(rangeMatch[1])
+			to, _ := strconv.Atoi(rangeMatch[2])
+
+			for referenced := from; referenced <= to && referenced < claim.Number; referenced++ {
+				add(referenced)
+			}
+		}
+
+		for _, reference := range number.FindAllString(list, -1) {
+			referenced, _ := strconv.Atoi(reference)
+			add(referenced)
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,196 @@
+//nolint:funlen // Test functions are long, due to test cases
+package claims_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		text string
+		want []entities.Claim
+	}{
+		{
+			name: "numbered claims with continuation lines",
+			text: "What is claimed is:\n" +
+				"1. A method comprising\n" +
+				"verifying a signature.\n" +
+				"2) The method of claim 1, wherein the signature is\n" +
+				"10. characters long.\n" +
+				"3. A system comprising a processor.",
+			want: []entities.Claim{
+				{Number: 1, Text: "1. A method comprising verifying a signature."},
+				{
+					Number:    2,
+					Text:      "2) The method of claim 1, wherein the signature is 10. characters long.",
+					DependsOn: []int{1},
+				},
+				{Number: 3, Text: "3. A system comprising a processor."},
+			},
+		},
+		{
+			name: "no claims",
+			text: "Some description\n2. not a claim",
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, claims.Parse(tc.text))
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text string
+		want []int
+	}{
+		{text: "A method for verifying a signature.", want: nil},
+		{text: "The method according to claim 3.", want: []int{3}},
+		{text: "The method of claims 1 or 2.", want: []int{1, 2}},
+		{text: "The method of claims 1, 2 and 4.", want: []int{1, 2, 4}},
+		{text: "The method of any of claims 2 to 4.", want: []int{2, 3, 4}},
+		{text: "The method according to claims 1-3.", want: []int{1, 2, 3}},
+		{text: "The method according to any one of the preceding claims.", want: []int{1, 2, 3, 4}},
+		{text: "The method of claim 5 or claim 7.", want: nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.text, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, claims.References(entities.Claim{Number: 5, Text: tc.text}))
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	analysis := claims.Analyze([]entities.Claim{
+		{Number: 1, Text: "1. A method of ranging, comprising emitting laser light and detecting its reflection."},
+		{Number: 2, Text: "2. The method of claim 1, wherein the light is modulated."},
+		{Number: 3, Text: "3. The method of claim 2, wherein the modulation is a frequency modulation."},
+		{Number: 4, Text: "4. A ranging system comprising a laser source.", DependsOn: nil},
+		{Number: 5, Text: "5. The system according to claims 1 or 4.", DependsOn: []int{4}},
+		{Number: 6, Text: "6. The system of claim 9, further comprising a detector."},
+	})
+
+	assert.Equal(t, claims.Metrics{
+		Claims:                        6,
+		IndependentClaims:             3,
+		DependentClaims:               3,
+		Breadth:                       3,
+		Depth:                         3,
+		ShortestIndependentClaimWords: 7,
+		Categories:                    2,
+	}, analysis.Metrics)
+
+	if !assert.Len(t, analysis.Roots, 3) {
+		return
+	}
+
+	method, system, unresolved := analysis.Roots[0], analysis.Roots[1], analysis.Roots[2]
+
+	assert.Equal(t, claims.CategoryMethod, method.Category)
+	assert.True(t, method.Independent())
+
+	if assert.Len(t, method.Children, 1) && assert.Len(t, method.Children[0].Children, 1) {
+		assert.Equal(t, 3, method.Children[0].Children[0].Claim.Number)
+		assert.Equal(t, claims.CategoryMethod, method.Children[0].Children[0].Category)
+	}
+
+	assert.Equal(t, claims.CategoryApparatus, system.Category)
+
+	if assert.Len(t, system.Children, 1) {
+		// known dependencies take precedence over references in the text
+		assert.Equal(t, []int{4}, system.Children[0].Claim.DependsOn)
+	}
+
+	// references to unknown claims are dropped
+	assert.Equal(t, 6, unresolved.Claim.Number)
+	assert.Nil(t, unresolved.Claim.DependsOn)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	before := claims.Parse("1. A method comprising verifying a signature.\n" +
+		"2. The method of claim 1, using RSA.\n" +
+		"3. A system comprising a processor.")
+
+	testCases := []struct {
+		name  string
+		after []entities.Claim
+		want  map[int]claims.ChangeKind
+	}{
+		{
+			name: "reformatted claims without number are unchanged",
+			after: []entities.Claim{
+				{Number: 1, Text: "A method comprising   verifying a\nsignature."},
+				{Number: 2, Text: "The Method of claim 1, using RSA."},
+				{Number: 3, Text: "A system comprising a processor."},
+			},
+			want: map[int]claims.ChangeKind{
+				1: claims.ChangeUnchanged, 2: claims.ChangeUnchanged, 3: claims.ChangeUnchanged,
+			},
+		},
+		{
+			name: "amended, removed and added claims",
+			after: claims.Parse("1. A method comprising verifying a signature with a public key.\n" +
+				"2. The method of claim 1, using RSA."),
+			want: map[int]claims.ChangeKind{
+				1: claims.ChangeAmended, 2: claims.ChangeUnchanged, 3: claims.ChangeRemoved,
+			},
+		},
+		{
+			name: "added claims",
+			after: claims.Parse("1. A method comprising verifying a signature.\n" +
+				"2. The method of claim 1, using RSA.\n" +
+				"3. A system comprising a processor.\n" +
+				"4. The system of claim 3, comprising memory."),
+			want: map[int]claims.ChangeKind{
+				1: claims.ChangeUnchanged, 2: claims.ChangeUnchanged, 3: claims.ChangeUnchanged, 4: claims.ChangeAdded,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			changes := claims.Diff(before, tc.after)
+
+			got := map[int]claims.ChangeKind{}
+			for i, change := range changes {
+				got[change.Number] = change.Kind
+
+				if i > 0 {
+					assert.Less(t, changes[i-1].Number, change.Number)
+				}
+
+				assert.Equal(t, change.Kind == claims.ChangeAdded, change.Before == nil)
+				assert.Equal(t, change.Kind == claims.ChangeRemoved, change.After == nil)
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package claims
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+// ChangeKind tells how a claim differs between two versions of a patent.
+type ChangeKind string
+
+const (
+	ChangeUnchanged ChangeKind = "unchanged"
+	ChangeAmended   ChangeKind = "amended"
+	ChangeAdded     ChangeKind = "added"
+	ChangeRemoved   ChangeKind = "removed"
+)
+
+// Change compares a claim between two versions, Before is nil for added and After for removed claims.
+type Change struct {
+	Number int
+	Kind   ChangeKind
+	Before *entities.Claim
+	After  *entities.Claim
+}
+
+// Diff compares the claims of two versions of a patent by their numbers, ordered by number.
+//
+// Claim texts are compared ignoring case, whitespace and their leading number, so that reformatting or taking the
+// claims from another kind of document does not count as amendment.
+func Diff(before, after []entities.Claim) []Change {
+	changes := map[int]*Change{}
+
+	for i := range before {
+		changes[before[i].Number] = &Change{Number: before[i].Number, Kind: ChangeRemoved, Before: &before[i]}
+	}
+
+	for i := range after {
+		change, ok := changes[after[i].Number]
+		if !ok {
+			changes[after[i].Number] = &Change{Number: after[i].Number, Kind: ChangeAdded, After: &after[i]}
+
+			continue
+		}
+
+		change.After = &after[i]
+		change.Kind = ChangeAmended
+
+		if normalize(change.Before.Text) == normalize(after[i].Text) {
+			change.Kind = ChangeUnchanged
+		}
+	}
+
+	result := make([]Change, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, *change)
+	}
+
+	slices.SortFunc(result, func(a, b Change) int {
+		return a.Number - b.Number
+	})
+
+	return result
+}
+
+func normalize(text string) string {
+	text = claimStart.ReplaceAllString(strings.TrimSpace(text), "")
+
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,156 @@
+package claims
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+type Category int
+
+const (
+	CategoryOther Category = iota
+	CategoryMethod
+	CategoryApparatus
+	CategoryComposition
+	CategoryUse
+	CategoryComputerProgram
+)
+
+// categoryPatterns are checked in order against the preamble of independent claims.
+var categoryPatterns = []struct {
+	category Category
+	pattern  *regexp.Regexp
+}{
+	{CategoryComputerProgram, regexp.MustCompile(`(?i)\b(computer[- ]program|computer[- ]readable|storage medium|software)`)},
+	{CategoryUse, regexp.MustCompile(`(?i)^(the\s+)?use\s+of\b`)},
+	{CategoryMethod, regexp.MustCompile(`(?i)\b(method|process|procedure)\b`)},
+	{CategoryComposition, regexp.MustCompile(`(?i)\b(composition|compound|formulation|mixture|alloy)\b`)},
+	{
+		CategoryApparatus,
+		regexp.MustCompile(`(?i)\b(apparatus|device|system|assembly|machine|arrangement|circuit|unit|vehicle|` +
+			`sensor|equipment|kit)\b`),
+	},
+}
+
+// preambleWords is the number of leading words the category is recognized from.
+const preambleWords = 12
+
+// Node is a claim in the dependency tree, dependent claims are children of the first claim they refer to.
+type Node struct {
+	Claim entities.Claim
+	// Category is the category of the independent claim the node descends from
+	Category Category
+	Children []*Node
+}
+
+func (n *Node) Independent() bool {
+	return len(n.Claim.DependsOn) == 0
+}
+
+type Metrics struct {
+	Claims            int
+	IndependentClaims int
+	DependentClaims   int
+	// Breadth is the number of independent claims, i.e. the number of separate lines of protection
+	Breadth int
+	// Depth is the length of the longest chain of dependent claims, 1 if all claims are independent
+	Depth int
+	// ShortestIndependentClaimWords counts the words of the shortest independent claim, shorter claims tend to
+	// protect more broadly
+	ShortestIndependentClaimWords int
+	// Categories counts the distinct categories of the independent claims
+	Categories int
+}
+
+// Analysis is the dependency tree of the claims of a patent.
+type Analysis struct {
+	Roots   []*Node
+	Metrics Metrics
+}
+
+// Analyze builds the dependency tree. Claims without known dependencies are checked for references in their text,
+// references to unknown claims are dropped, so claims referring only to those become independent.
+func Analyze(claims []entities.Claim) Analysis {
+	var analysis Analysis
+
+	nodes := make(map[int]*Node, len(claims))
+	categories := map[Category]bool{}
+
+	for _, claim := range claims {
+		dependsOn := claim.DependsOn
+		if len(dependsOn) == 0 {
+			dependsOn = References(claim)
+		}
+
+		claim.DependsOn = nil
+
+		for _, referenced := range dependsOn {
+			if _, ok := nodes[referenced]; ok {
+				claim.DependsOn = append(claim.DependsOn, referenced)
+			}
+		}
+
+		node := &Node{Claim: claim}
+		nodes[claim.Number] = node
+
+		if node.Independent() {
+			node.Category = category(claim.Text)
+			categories[node.Category] = true
+			analysis.Roots = append(analysis.Roots, node)
+
+			analysis.Metrics.IndependentClaims++
+			if words := wordCount(claim.Text); analysis.Metrics.ShortestIndependentClaimWords == 0 ||
+				words < analysis.Metrics.ShortestIndependentClaimWords {
+				analysis.Metrics.ShortestIndependentClaimWords = words
+			}
+
+			continue
+		}
+
+		parent := nodes[claim.DependsOn[0]]
+		node.Category = parent.Category
+		parent.Children = append(parent.Children, node)
+		analysis.Metrics.DependentClaims++
+	}
+
+	analysis.Metrics.Claims = len(claims)
+	analysis.Metrics.Breadth = analysis.Metrics.IndependentClaims
+	analysis.Metrics.Categories = len(categories)
+
+	for _, root := range analysis.Roots {
+		analysis.Metrics.Depth = max(analysis.Metrics.Depth, depth(root))
+	}
+
+	return analysis
+}
+
+func category(text string) Category {
+	preamble := strings.Fields(claimStart.ReplaceAllString(text, ""))
+	if len(preamble) > preambleWords {
+		preamble = preamble[:preambleWords]
+	}
+
+	for _, candidate := range categoryPatterns {
+		if candidate.pattern.MatchString(strings.Join(preamble, " ")) {
+			return candidate.category
+		}
+	}
+
+	return CategoryOther
+}
+
+// wordCount counts the words of a claim without its number.
+func wordCount(text string) int {
+	return len(strings.Fields(claimStart.ReplaceAllString(text, "")))
+}
+
+func depth(node *Node) int {
+	result := 0
+	for _, child := range node.Children {
+		result = max(result, depth(child))
+	}
+
+	return result + 1
+}
@@ -0,0 +1,204 @@
+// Package cli is the command-line client for the patents API, see cmd/patai-cli.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/client"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+
+	outputTable = "table"
+	outputJSON  = "json"
+
+	defaultPollInterval = 2 * time.Second
+)
+
+var (
+	errUsage         = errors.New("usage")
+	errUnknownOutput = errors.New("unknown output mode, use one of table, json")
+	errJobsFailed    = errors.New("not all jobs finished")
+)
+
+const usage = `Usage: patai-cli [global flags] <command> [flags] [arguments]
+
+Commands:
+  submit [file|glob|- ...]  submit patents, several text files as one batch, others one by one
+  list                      list jobs
+  get <job ID>              show a job
+  wait <job ID>...          wait until jobs are finished or failed
+
+Global flags:
+`
+
+// app is a single invocation of the CLI.
+type app struct {
+	client *client.Client
+	output string
+	// interval is how often batches are polled for their progress
+	interval time.Duration
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+//nolint:gochecknoglobals // command table
+var commands = map[string]command{
+	"submit": submit,
+	"list":   list,
+	"get":    get,
+	"wait":   wait,
+}
+
+// Run executes the command line args and returns the exit code: 1 for failed commands, 2 for usage errors.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("patai-cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	configFile := flags.String("config", defaultConfigFile(), "configuration file with the profiles, or $"+configFileEnv)
+	profileName := flags.String("profile", "", "profile to use, defaults to $"+profileEnv+" or the default profile")
+	output := flags.String("output", outputTable, "output mode, table or json")
+	interval := flags.Duration("interval", defaultPollInterval, "how often to ask for the status while waiting")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+
+		return exitUsage
+	}
+
+	run, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q, use one of %s\n", flags.Arg(0), strings.Join(commandNames(), ", "))
+
+		return exitUsage
+	}
+
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintln(stderr, errUnknownOutput)
+
+		return exitUsage
+	}
+
+	profile, err := LoadProfile(*configFile, *profileName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	apiClient, err := client.New(profile.BaseURL, append(profile.clientOptions(), client.WithPollInterval(*interval))...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	a := &app{client: apiClient, output: *output, interval: *interval, stdin: stdin, stdout: stdout, stderr: stderr}
+
+	switch err := run(ctx, a, flags.Args()[1:]); {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp), errors.Is(err, errUsage):
+		return exitUsage
+	default:
+		fmt.Fprintln(stderr, describe(err))
+
+		return exitError
+	}
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// describe adds the invalid fields reported by the API to the message of an error.
+func describe(err error) string {
+	message := err.Error()
+
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		for _, field := range apiErr.Fields() {
+			message += fmt.Sprintf("\n  %s: %s", field.Field, field.Message)
+		}
+	}
+
+	return message
+}
+
+// newFlagSet reports usage errors of a command with the command's usage line.
+func (a *app) newFlagSet(name string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: patai-cli %s [flags] %s\n\nFlags:\n", name, arguments)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+// usageError prints the usage of a command along with what was wrong.
+func usageError(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), format+"\n", args...)
+	flags.Usage()
+
+	return errUsage
+}
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+
+	return nil
+}
+
+// labels parses repeated key:value flags, a key alone has an empty value.
+func labels(values stringList) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(values))
+
+	for _, value := range values {
+		key, labelValue, _ := strings.Cut(value, ":")
+		result[key] = labelValue
+	}
+
+	return result
+}
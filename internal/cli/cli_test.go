@@ -0,0 +1,410 @@
+//nolint:funlen // Test functions are long, due to test cases
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/cli"
+)
+
+const (
+	jobID     = "0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11"
+	failedJob = "9d2a4f36-61c1-4d7e-a4d2-8f3e5b1c7a20"
+	batchID   = "3f8e1a52-9c4b-4e1f-8d3a-2b6c7e9f0a14"
+	finished  = `{"id": "` + jobID + `", "status": "finished", "priority": "normal", "title": "Valuing patents", ` +
+		`"valuation": {"currency": "EUR", "low": 900, "expected": 1000, "high": 1100, "date": "2024-01-31"}}`
+	failed     = `{"id": "` + failedJob + `", "status": "failed", "priority": "normal", "error": "engine unavailable"}`
+	apiKey     = "secret"
+	profileTpl = `
+defaultProfile: local
+profiles:
+  local:
+    baseURL: %s
+    apiKey: ` + apiKey + `
+  broken:
+    apiKey: ` + apiKey + `
+`
+)
+
+// stubAPI answers like the patents API and records the submissions it gets.
+type stubAPI struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	submissions []submission
+	batchPolls  atomic.Int32
+}
+
+type submission struct {
+	path        string
+	query       string
+	contentType string
+	body        string
+}
+
+func newStubAPI(t *testing.T) *stubAPI {
+	t.Helper()
+
+	api := &stubAPI{}
+	mux := http.NewServeMux()
+
+	record := func(r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		api.mutex.Lock()
+		defer api.mutex.Unlock()
+
+		api.submissions = append(api.submissions, submission{
+			path: r.URL.Path, query: r.URL.RawQuery, contentType: r.Header.Get("Content-Type"), body: string(body),
+		})
+	}
+
+	respond := func(w http.ResponseWriter, status int, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+
+	mux.HandleFunc("POST /api/v0/patents", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		respond(w, http.StatusCreated, `{"id": "`+jobID+`", "status": "pending", "priority": "normal"}`)
+	})
+	mux.HandleFunc("GET /api/v0/patents", func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, http.StatusOK, "["+finished+", "+failed+"]")
+	})
+	mux.HandleFunc("GET /api/v0/patents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("id") {
+		case jobID:
+			respond(w, http.StatusOK, finished)
+		case failedJob:
+			respond(w, http.StatusOK, failed)
+		default:
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"title": "Job not found", "status": 404, "code": "job-not-found"}`))
+		}
+	})
+	mux.HandleFunc("POST /api/v0/patents/batch", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		respond(w, http.StatusCreated, batch(false))
+	})
+	mux.HandleFunc("GET /api/v0/batches/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, http.StatusOK, batch(api.batchPolls.Add(1) > 1))
+	})
+
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != apiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func batch(done bool) string {
+	if !done {
+		return `{"id": "` + batchID + `", "mode": "atomic", "progress": {"total": 2, "accepted": 2, "pending": 2}, ` +
+			`"items": [{"index": 0, "job": {"id": "` + jobID + `", "status": "pending"}}, ` +
+			`{"index": 1, "job": {"id": "` + failedJob + `", "status": "pending"}}]}`
+	}
+
+	return `{"id": "` + batchID + `", "mode": "atomic", ` +
+		`"progress": {"total": 2, "accepted": 2, "finished": 1, "failed": 1, "done": true}, ` +
+		`"items": [{"index": 0, "job": ` + finished + `}, {"index": 1, "job": ` + failed + `}]}`
+}
+
+func (api *stubAPI) recorded() []submission {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	return api.submissions
+}
+
+// writeFiles creates the files in a temporary directory, along with the configuration file cli.yml.
+func writeFiles(t *testing.T, baseURL string, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["cli.yml"] = fmt.Sprintf(profileTpl, baseURL)
+
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		stdin    string
+		files    map[string]string
+		wantCode int
+		// wantStdout and wantStderr are expected to be contained in the output
+		wantStdout      []string
+		wantStderr      []string
+		wantSubmissions []submission
+	}{
+		{
+			name:  "submit stdin",
+			args:  []string{"submit", "-label", "team:ip", "-tag", "urgent", "-priority", "high"},
+			stdin: "A patent",
+			wantStdout: []string{
+				"ID:        " + jobID, "Status:    pending",
+			},
+			wantSubmissions: []submission{{
+				path: "/api/v0/patents", query: "label=team%3Aip&priority=high&tag=urgent",
+				contentType: "text/plain; charset=utf-8", body: "A patent",
+			}},
+		},
+		{
+			name:  "submit structured patent and wait",
+			args:  []string{"submit", "-wait", "{dir}/patent.json"},
+			files: map[string]string{"patent.json": `{"title": "Valuing patents", "claims": ["1. A method."]}`},
+			wantStdout: []string{
+				"Status:    finished", "Patent:    Valuing patents", "Value:     1000 EUR", "Range:     900 - 1100 EUR",
+			},
+			wantSubmissions: []submission{{
+				path: "/api/v0/patents", contentType: "application/json",
+				body: `{"title":"Valuing patents","claims":["1. A method."]}`,
+			}},
+		},
+		{
+			name:     "malformed structured patent",
+			args:     []string{"submit", "{dir}/patent.json"},
+			files:    map[string]string{"patent.json": `{"name": "Valuing patents"}`},
+			wantCode: 1,
+			wantStderr: []string{
+				`malformed patent`, `unknown field "name"`,
+			},
+		},
+		{
+			name:     "submit glob as batch and wait",
+			args:     []string{"-interval", "1ms", "submit", "-wait", "-mode", "best-effort", "{dir}/*.txt"},
+			files:    map[string]string{"a.txt": "First patent", "b.txt": "Second patent"},
+			wantCode: 1,
+			wantStdout: []string{
+				"Batch " + batchID + ": 2 of 2 jobs settled, 1 finished, 1 failed, 0 rejected",
+				"0     " + jobID + "  finished  1000 EUR", "engine unavailable",
+			},
+			wantStderr: []string{
+				"0 of 2 jobs settled", "2 of 2 jobs settled", "1 failed: not all jobs finished",
+			},
+			wantSubmissions: []submission{{
+				path: "/api/v0/patents/batch", query: "mode=best-effort", contentType: "application/json",
+				body: `[{"content":"First patent"},{"content":"Second patent"}]`,
+			}},
+		},
+		{
+			name:       "submit files one by one and wait",
+			args:       []string{"-interval", "1ms", "submit", "-wait", "{dir}/a.txt", "{dir}/b.xml"},
+			files:      map[string]string{"a.txt": "First patent", "b.xml": "<patent/>"},
+			wantStdout: []string{jobID + "  finished  normal"},
+			wantStderr: []string{
+				"1 of 2 files submitted, 0 rejected", "2 of 2 files submitted, 0 rejected",
+				"0 of 2 jobs settled", "2 of 2 jobs settled, 2 finished, 0 failed, 0 rejected",
+			},
+			wantSubmissions: []submission{
+				{path: "/api/v0/patents", contentType: "text/plain; charset=utf-8", body: "First patent"},
+				{path: "/api/v0/patents", contentType: "application/xml", body: "<patent/>"},
+			},
+		},
+		{
+			name:       "submit files one by one despite a malformed one",
+			args:       []string{"submit", "{dir}/a.json", "{dir}/b.txt"},
+			files:      map[string]string{"a.json": `{"name": "Valuing patents"}`, "b.txt": "Second patent"},
+			wantCode:   1,
+			wantStdout: []string{jobID + "  pending"},
+			wantStderr: []string{
+				`a.json: json: unknown field "name": malformed patent`, "2 of 2 files submitted, 1 rejected",
+				"1 rejected: not all files were submitted",
+			},
+			wantSubmissions: []submission{
+				{path: "/api/v0/patents", contentType: "text/plain; charset=utf-8", body: "Second patent"},
+			},
+		},
+		{
+			name:       "no matching files",
+			args:       []string{"submit", "{dir}/*.xml"},
+			wantCode:   1,
+			wantStderr: []string{"no files match"},
+		},
+		{
+			name: "list as table",
+			args: []string{"list", "-label", "team"},
+			wantStdout: []string{
+				"ID                                    STATUS    PRIORITY  CREATED              PATENT           VALUE",
+				jobID + "  finished  normal", "Valuing patents  1000 EUR",
+			},
+		},
+		{
+			name:       "list as JSON",
+			args:       []string{"-output", "json", "list"},
+			wantStdout: []string{`"id": "` + jobID + `"`, `"expected": 1000`, `"error": "engine unavailable"`},
+		},
+		{
+			name:       "get unknown job",
+			args:       []string{"get", "6c1d7e3a-0f4b-4b8e-9a5d-2e7f1c3b9d80"},
+			wantCode:   1,
+			wantStderr: []string{"Job not found"},
+		},
+		{
+			name:       "get without ID",
+			args:       []string{"get"},
+			wantCode:   2,
+			wantStderr: []string{"a single job ID is required", "Usage: patai-cli get [flags] <job ID>"},
+		},
+		{
+			name:       "wait for failing job",
+			args:       []string{"-interval", "1ms", "wait", jobID, failedJob},
+			wantCode:   1,
+			wantStdout: []string{jobID + "  finished", failedJob + "  failed"},
+			wantStderr: []string{"1 failed: not all jobs finished"},
+		},
+		{
+			name:       "unknown profile",
+			args:       []string{"-profile", "remote", "list"},
+			wantCode:   1,
+			wantStderr: []string{"profile remote in", "unknown profile"},
+		},
+		{
+			name:       "profile without base URL",
+			args:       []string{"-profile", "broken", "list"},
+			wantCode:   1,
+			wantStderr: []string{"profile broken: profile has no baseURL"},
+		},
+		{
+			name:       "unknown output mode",
+			args:       []string{"-output", "xml", "list"},
+			wantCode:   2,
+			wantStderr: []string{"unknown output mode"},
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"delete"},
+			wantCode:   2,
+			wantStderr: []string{`unknown command "delete", use one of get, list, submit, wait`},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			api := newStubAPI(t)
+
+			files := map[string]string{}
+			for name, content := range tc.files {
+				files[name] = content
+			}
+
+			dir := writeFiles(t, api.URL+"/api/v0", files)
+
+			args := []string{"-config", filepath.Join(dir, "cli.yml")}
+			for _, arg := range tc.args {
+				args = append(args, strings.ReplaceAll(arg, "{dir}", dir))
+			}
+
+			var stdout, stderr bytes.Buffer
+
+			code := cli.Run(context.Background(), args, strings.NewReader(tc.stdin), &stdout, &stderr)
+
+			assert.Equal(t, tc.wantCode, code, stderr.String())
+
+			for _, want := range tc.wantStdout {
+				assert.Contains(t, stdout.String(), want)
+			}
+
+			for _, want := range tc.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+
+			assert.Equal(t, tc.wantSubmissions, api.recorded())
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Parallel()
+
+	dir := writeFiles(t, "http://localhost:8080/api/v0", map[string]string{
+		"token.yml": "profiles:\n  default:\n    baseURL: https://patai.example.com/api/v0\n    token: jwt\n",
+	})
+
+	testCases := []struct {
+		name        string
+		file        string
+		profile     string
+		wantProfile cli.Profile
+		wantErr     error
+	}{
+		{
+			name: "configured default profile", file: "cli.yml",
+			wantProfile: cli.Profile{BaseURL: "http://localhost:8080/api/v0", APIKey: apiKey},
+		},
+		{
+			name: "profile named default", file: "token.yml",
+			wantProfile: cli.Profile{BaseURL: "https://patai.example.com/api/v0", Token: "jwt"},
+		},
+		{
+			name: "unknown profile", file: "token.yml", profile: "local", wantErr: cli.ErrUnknownProfile,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			profile, err := cli.LoadProfile(filepath.Join(dir, tc.file), tc.profile)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantProfile, profile)
+		})
+	}
+}
+
+// the JSON output is the API's representation of the jobs.
+func TestRun_JSONOutput(t *testing.T) {
+	t.Parallel()
+
+	api := newStubAPI(t)
+	dir := writeFiles(t, api.URL+"/api/v0", map[string]string{})
+
+	var stdout, stderr bytes.Buffer
+
+	code := cli.Run(context.Background(), []string{"-config", filepath.Join(dir, "cli.yml"), "-output", "json", "get", jobID},
+		strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, 0, code, stderr.String())
+
+	var job map[string]any
+
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &job))
+	assert.Equal(t, jobID, job["id"])
+	assert.Equal(t, "finished", job["status"])
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/client"
+)
+
+func list(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("list", "")
+
+	var filter client.JobFilter
+
+	var labelFlags, tagFlags stringList
+
+	flags.StringVar(&filter.Query, "q", "", "full-text query, results are ranked by relevance")
+	flags.Var(&labelFlags, "label", "label as key:value or key, may be repeated")
+	flags.Var(&tagFlags, "tag", "tag, may be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if flags.NArg() > 0 {
+		return usageError(flags, "unexpected arguments %v", flags.Args())
+	}
+
+	filter.Labels = labels(labelFlags)
+	filter.Tags = tagFlags
+
+	jobs, err := a.client.ListJobs(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return a.printJobs(jobs)
+}
+
+func get(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("get", "<job ID>")
+
+	if err := flags.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if flags.NArg() != 1 {
+		return usageError(flags, "a single job ID is required")
+	}
+
+	job, err := a.client.GetJob(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return a.printJob(job)
+}
+
+// wait prints the jobs once all of them are settled, it fails if any of them failed.
+func wait(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("wait", "<job ID>...")
+
+	timeout := flags.Duration("timeout", 0, "give up after this long, zero waits forever")
+
+	if err := flags.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if flags.NArg() == 0 {
+		return usageError(flags, "at least one job ID is required")
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	jobs := make([]client.Job, 0, flags.NArg())
+	failed := 0
+
+	for _, id := range flags.Args() {
+		job, err := a.client.WaitForJob(ctx, id)
+		if errors.Is(err, client.ErrJobFailed) {
+			failed++
+		} else if err != nil {
+			return err
+		}
+
+		jobs = append(jobs, *job)
+	}
+
+	if err := a.printJobs(jobs); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Wrapf(errJobsFailed, "%d failed", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/client"
+)
+
+const progressBarWidth = 30
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd // padding between columns
+}
+
+func (a *app) printJSON(value any) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(value), "cannot write output")
+}
+
+func (a *app) printJobs(jobs []client.Job) error {
+	if a.output == outputJSON {
+		return a.printJSON(jobs)
+	}
+
+	table := newTable(a.stdout)
+	fmt.Fprintln(table, "ID\tSTATUS\tPRIORITY\tCREATED\tPATENT\tVALUE")
+
+	for i := range jobs {
+		job := &jobs[i]
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Status, job.Priority,
+			job.CreatedAt.Local().Format(time.DateTime), patentName(job), value(job))
+	}
+
+	return errors.Wrap(table.Flush(), "cannot write output")
+}
+
+func (a *app) printJob(job *client.Job) error {
+	if a.output == outputJSON {
+		return a.printJSON(job)
+	}
+
+	table := newTable(a.stdout)
+	row := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(table, "%s:\t%s\n", name, value)
+		}
+	}
+
+	row("ID", job.ID)
+	row("Status", job.Status)
+	row("Priority", job.Priority)
+	row("Created", job.CreatedAt.Local().Format(time.DateTime))
+	row("Patent", patentName(job))
+	row("Patent key", job.PatentKey)
+	row("Technical field", job.TechnicalField)
+
+	if job.Engine != nil {
+		row("Engine", job.Engine.Name+" "+job.Engine.Version)
+	}
+
+	if job.Valuation != nil {
+		row("Value", value(job))
+		row("Range", fmt.Sprintf("%.0f - %.0f %s", job.Valuation.Low, job.Valuation.High, job.Valuation.Currency))
+	}
+
+	row("Error", job.Error)
+	row("Labels", formatLabels(job.Labels))
+	row("Tags", strings.Join(job.Tags, ", "))
+	row("Note", job.Note)
+
+	return errors.Wrap(table.Flush(), "cannot write output")
+}
+
+func (a *app) printBatch(batch *client.Batch) error {
+	if a.output == outputJSON {
+		return a.printJSON(batch)
+	}
+
+	fmt.Fprintf(a.stdout, "Batch %s: %s\n\n", batch.ID, progressSummary(batch.Progress))
+
+	table := newTable(a.stdout)
+	fmt.Fprintln(table, "ITEM\tJOB\tSTATUS\tVALUE\tERROR")
+
+	for _, item := range batch.Items {
+		if item.Job == nil {
+			fmt.Fprintf(table, "%d\t-\trejected\t-\t%s\n", item.Index, item.Error)
+
+			continue
+		}
+
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", item.Index, item.Job.ID, item.Job.Status, value(item.Job),
+			item.Job.Error)
+	}
+
+	return errors.Wrap(table.Flush(), "cannot write output")
+}
+
+// patentName names the patent of a job by what is known about it.
+func patentName(job *client.Job) string {
+	switch {
+	case job.Title != "" && job.PublicationNumber != "":
+		return job.PublicationNumber + " " + job.Title
+	case job.Title != "":
+		return job.Title
+	case job.PatentKey != "":
+		return job.PatentKey
+	case job.Document != nil:
+		return job.Document.FileName
+	default:
+		return "-"
+	}
+}
+
+func value(job *client.Job) string {
+	if job.Valuation == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.0f %s", job.Valuation.Expected, job.Valuation.Currency)
+}
+
+func formatLabels(labels map[string]string) string {
+	formatted := make([]string, 0, len(labels))
+
+	for key, value := range labels {
+		formatted = append(formatted, key+":"+value)
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func progressSummary(progress client.BatchProgress) string {
+	return fmt.Sprintf("%d of %d jobs settled, %d finished, %d failed, %d rejected",
+		progress.Finished+progress.Failed, progress.Accepted, progress.Finished, progress.Failed, progress.Rejected)
+}
+
+// progressPrinter shows the progress of a batch, redrawing a bar on terminals and printing a line per change otherwise.
+type progressPrinter struct {
+	w        io.Writer
+	terminal bool
+	last     string
+}
+
+func newProgressPrinter(w io.Writer) *progressPrinter {
+	file, ok := w.(*os.File)
+	if !ok {
+		return &progressPrinter{w: w}
+	}
+
+	info, err := file.Stat()
+
+	return &progressPrinter{w: w, terminal: err == nil && info.Mode()&os.ModeCharDevice != 0}
+}
+
+func (p *progressPrinter) update(progress client.BatchProgress) {
+	line := progressSummary(progress)
+	if line == p.last {
+		return
+	}
+
+	p.last = line
+
+	if !p.terminal {
+		fmt.Fprintln(p.w, line)
+
+		return
+	}
+
+	filled := 0
+	if progress.Accepted > 0 {
+		filled = (progress.Finished + progress.Failed) * progressBarWidth / progress.Accepted
+	}
+
+	fmt.Fprintf(p.w, "\r[%s%s] %s", strings.Repeat("#", filled), strings.Repeat(".", progressBarWidth-filled), line)
+
+	if progress.Done {
+		fmt.Fprintln(p.w)
+	}
+}
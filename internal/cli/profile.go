@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/MyChaOS87/patAi/pkg/client"
+)
+
+const (
+	configFileEnv  = "PATAI_CLI_CONFIG"
+	profileEnv     = "PATAI_PROFILE"
+	defaultProfile = "default"
+)
+
+var (
+	ErrUnknownProfile = errors.New("unknown profile")
+	errMissingBaseURL = errors.New("profile has no baseURL")
+)
+
+// Config is the CLI configuration file, it holds the credentials of one or more API deployments.
+type Config struct {
+	// DefaultProfile is used unless another profile is selected, defaults to "default"
+	DefaultProfile string             `yaml:"defaultProfile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// Profile is an API deployment and the credentials for it, either an API key or a bearer token.
+type Profile struct {
+	// BaseURL includes the API version, e.g. http://localhost:8080/api/v0
+	BaseURL string `yaml:"baseURL"`
+	APIKey  string `yaml:"apiKey"`
+	Token   string `yaml:"token"`
+	// Currency optionally converts all valuations
+	Currency string `yaml:"currency"`
+}
+
+// defaultConfigFile is patai/cli.yml in the user's configuration directory, e.g. ~/.config/patai/cli.yml.
+func defaultConfigFile() string {
+	if file := os.Getenv(configFileEnv); file != "" {
+		return file
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("patai", "cli.yml")
+	}
+
+	return filepath.Join(dir, "patai", "cli.yml")
+}
+
+// LoadProfile reads a profile from the configuration file, an empty name selects the profile of the PATAI_PROFILE
+// environment variable or else the default profile.
+func LoadProfile(file string, name string) (Profile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return Profile{}, errors.Wrap(err, "cannot read configuration")
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return Profile{}, errors.Wrapf(err, "cannot parse configuration %s", file)
+	}
+
+	if name == "" {
+		name = os.Getenv(profileEnv)
+	}
+
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+
+	if name == "" {
+		name = defaultProfile
+	}
+
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return Profile{}, errors.Wrapf(ErrUnknownProfile, "profile %s in %s", name, file)
+	}
+
+	if profile.BaseURL == "" {
+		return Profile{}, errors.Wrapf(errMissingBaseURL, "profile %s", name)
+	}
+
+	return profile, nil
+}
+
+// clientOptions authenticates by API key or token, whichever the profile has.
+func (p Profile) clientOptions() []client.Option {
+	var options []client.Option
+
+	if p.APIKey != "" {
+		options = append(options, client.WithAPIKey(p.APIKey))
+	}
+
+	if p.Token != "" {
+		options = append(options, client.WithBearerToken(p.Token))
+	}
+
+	if p.Currency != "" {
+		options = append(options, client.WithCurrency(p.Currency))
+	}
+
+	return options
+}
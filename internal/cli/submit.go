@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/client"
+)
+
+// Kinds of submissions, by file extension unless given with -type.
+const (
+	kindText = "text"
+	kindJSON = "json"
+	kindXML  = "xml"
+	kindPDF  = "pdf"
+
+	stdinName = "-"
+)
+
+var (
+	errUnknownKind      = errors.New("unknown type, use one of text, json, xml, pdf")
+	errNoFiles          = errors.New("no files match")
+	errNotSubmitted     = errors.New("not all files were submitted")
+	errMalformedPatent  = errors.New("malformed patent")
+	errStdinInBatch     = errors.New("stdin cannot be submitted along with files")
+	errUnknownBatchMode = errors.New("unknown batch mode, use one of atomic, best-effort")
+)
+
+// source is a patent to submit, read from a file or stdin.
+type source struct {
+	name    string
+	kind    string
+	content []byte
+}
+
+func submit(ctx context.Context, a *app, args []string) error {
+	flags := a.newFlagSet("submit", "[file|glob|- ...]")
+
+	kind := flags.String("type", "", "type of the submissions, text, json, xml or pdf; defaults to the file extension")
+	wait := flags.Bool("wait", false, "wait until the jobs are finished or failed")
+	mode := flags.String("mode", "", "mode of batches of text files, atomic (the default) or best-effort")
+
+	var options client.SubmitOptions
+
+	var labelFlags, tagFlags stringList
+
+	flags.StringVar(&options.Priority, "priority", "", "priority, low, normal or high")
+	flags.BoolVar(&options.Fresh, "fresh", false, "value again even if a cached result exists")
+	flags.StringVar(&options.TechnicalField, "technical-field", "", "technical field of the patents")
+	flags.StringVar(&options.Engine, "engine", "", "valuation engine")
+	flags.StringVar(&options.EngineVersion, "engine-version", "", "version of the valuation engine")
+	flags.StringVar(&options.PatentKey, "patent-key", "", "logical patent the job belongs to")
+	flags.StringVar(&options.Note, "note", "", "note on the jobs")
+	flags.StringVar(&options.IdempotencyKey, "idempotency-key", "", "key that makes retrying the submission safe")
+	flags.Var(&labelFlags, "label", "label as key:value, may be repeated")
+	flags.Var(&tagFlags, "tag", "tag, may be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if *kind != "" && *kind != kindText && *kind != kindJSON && *kind != kindXML && *kind != kindPDF {
+		return usageError(flags, "%v", errUnknownKind)
+	}
+
+	if *mode != "" && *mode != client.BatchModeAtomic && *mode != client.BatchModeBestEffort {
+		return usageError(flags, "%v", errUnknownBatchMode)
+	}
+
+	options.Labels = labels(labelFlags)
+	options.Tags = tagFlags
+
+	sources, err := a.readSources(flags.Args(), *kind)
+	if err != nil {
+		return err
+	}
+
+	if len(sources) == 1 {
+		return a.submitJob(ctx, sources[0], options, *wait)
+	}
+
+	for _, s := range sources {
+		if s.kind != kindText {
+			return a.submitEach(ctx, sources, options, *wait)
+		}
+	}
+
+	return a.submitBatch(ctx, sources, options, *mode, *wait)
+}
+
+// readSources reads stdin without arguments or for "-", and the files matching the arguments otherwise.
+func (a *app) readSources(args []string, kind string) ([]source, error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == stdinName) {
+		content, err := io.ReadAll(a.stdin)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read stdin")
+		}
+
+		if kind == "" {
+			kind = kindText
+		}
+
+		return []source{{name: "stdin", kind: kind, content: content}}, nil
+	}
+
+	var sources []source
+
+	for _, arg := range args {
+		if arg == stdinName {
+			return nil, errStdinInBatch
+		}
+
+		files, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, errors.Wrap(err, arg)
+		}
+
+		if len(files) == 0 {
+			return nil, errors.Wrap(errNoFiles, arg)
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot read patent")
+			}
+
+			sources = append(sources, source{name: file, kind: kindOf(file, kind), content: content})
+		}
+	}
+
+	return sources, nil
+}
+
+func kindOf(file string, kind string) string {
+	if kind != "" {
+		return kind
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return kindJSON
+	case ".xml":
+		return kindXML
+	case ".pdf":
+		return kindPDF
+	default:
+		return kindText
+	}
+}
+
+func (a *app) submitJob(ctx context.Context, s source, options client.SubmitOptions, wait bool) error {
+	job, err := a.submitSource(ctx, s, options)
+	if err != nil {
+		return err
+	}
+
+	if wait {
+		job, err = a.client.WaitForJob(ctx, job.ID)
+		if job == nil {
+			return err
+		}
+
+		if printErr := a.printJob(job); printErr != nil {
+			return printErr
+		}
+
+		return err
+	}
+
+	return a.printJob(job)
+}
+
+// submitSource creates the job for the source according to its kind.
+func (a *app) submitSource(ctx context.Context, s source, options client.SubmitOptions) (*client.Job, error) {
+	var (
+		job *client.Job
+		err error
+	)
+
+	switch s.kind {
+	case kindJSON:
+		var patent client.PatentSubmission
+
+		decoder := json.NewDecoder(bytes.NewReader(s.content))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&patent); err != nil {
+			return nil, errors.Wrapf(errMalformedPatent, "%s: %v", s.name, err)
+		}
+
+		job, err = a.client.SubmitPatent(ctx, patent, options)
+	case kindXML:
+		job, err = a.client.SubmitXML(ctx, s.content, options)
+	case kindPDF:
+		job, err = a.client.SubmitDocument(ctx, client.DocumentUpload{FileName: filepath.Base(s.name), Content: s.content},
+			options)
+	default:
+		job, err = a.client.SubmitText(ctx, string(s.content), options)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, s.name)
+	}
+
+	return job, nil
+}
+
+// submitEach submits the files as jobs of their own, as only text files can be batched. A file that cannot be submitted
+// is reported on stderr without stopping the others, it fails the command in the end.
+func (a *app) submitEach(ctx context.Context, sources []source, options client.SubmitOptions, wait bool) error {
+	jobs := make([]client.Job, 0, len(sources))
+	rejected := 0
+
+	for i, s := range sources {
+		itemOptions := options
+		if options.IdempotencyKey != "" {
+			itemOptions.IdempotencyKey = fmt.Sprintf("%s-%d", options.IdempotencyKey, i)
+		}
+
+		job, err := a.submitSource(ctx, s, itemOptions)
+		if ctx.Err() != nil {
+			return errors.WithStack(ctx.Err())
+		}
+
+		if err != nil {
+			rejected++
+
+			fmt.Fprintln(a.stderr, describe(err))
+		} else {
+			jobs = append(jobs, *job)
+		}
+
+		fmt.Fprintf(a.stderr, "%d of %d files submitted, %d rejected\n", i+1, len(sources), rejected)
+	}
+
+	failed := 0
+
+	if wait && len(jobs) > 0 {
+		var err error
+		if failed, err = a.waitForJobs(ctx, jobs, rejected); err != nil {
+			return err
+		}
+	}
+
+	if len(jobs) > 0 {
+		if err := a.printJobs(jobs); err != nil {
+			return err
+		}
+	}
+
+	if rejected > 0 {
+		return errors.Wrapf(errNotSubmitted, "%d rejected", rejected)
+	}
+
+	if failed > 0 {
+		return errors.Wrapf(errJobsFailed, "%d failed", failed)
+	}
+
+	return nil
+}
+
+// waitForJobs waits for the jobs one after another, replacing them by their settled state and showing the progress
+// on stderr; it returns how many of them failed.
+func (a *app) waitForJobs(ctx context.Context, jobs []client.Job, rejected int) (int, error) {
+	progress := newProgressPrinter(a.stderr)
+	state := client.BatchProgress{
+		Total: len(jobs) + rejected, Accepted: len(jobs), Rejected: rejected, Pending: len(jobs),
+	}
+
+	progress.update(state)
+
+	for i := range jobs {
+		job, err := a.client.WaitForJob(ctx, jobs[i].ID)
+
+		switch {
+		case errors.Is(err, client.ErrJobFailed):
+			state.Failed++
+		case err != nil:
+			return 0, err
+		default:
+			state.Finished++
+		}
+
+		jobs[i] = *job
+		state.Pending--
+		state.Done = i == len(jobs)-1
+
+		progress.update(state)
+	}
+
+	return state.Failed, nil
+}
+
+// submitBatch submits text files as one batch, the options apply to all of its items.
+func (a *app) submitBatch(
+	ctx context.Context, sources []source, options client.SubmitOptions, mode string, wait bool,
+) error {
+	items := make([]client.BatchItemRequest, 0, len(sources))
+
+	for _, s := range sources {
+		items = append(items, client.BatchItemRequest{
+			Content:        string(s.content),
+			Priority:       options.Priority,
+			Fresh:          options.Fresh,
+			TechnicalField: options.TechnicalField,
+			Engine:         options.Engine,
+			EngineVersion:  options.EngineVersion,
+			PatentKey:      options.PatentKey,
+			Labels:         options.Labels,
+			Tags:           options.Tags,
+			Note:           options.Note,
+		})
+	}
+
+	batch, err := a.client.CreateBatch(ctx, items, client.BatchOptions{Mode: mode, IdempotencyKey: options.IdempotencyKey})
+	if err != nil {
+		return err
+	}
+
+	if wait {
+		if batch, err = a.waitForBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	if err := a.printBatch(batch); err != nil {
+		return err
+	}
+
+	if wait && batch.Progress.Failed > 0 {
+		return errors.Wrapf(errJobsFailed, "%d failed", batch.Progress.Failed)
+	}
+
+	return nil
+}
+
+// waitForBatch polls the batch until it is done, showing its progress on stderr.
+func (a *app) waitForBatch(ctx context.Context, batch *client.Batch) (*client.Batch, error) {
+	progress := newProgressPrinter(a.stderr)
+
+	for {
+		progress.update(batch.Progress)
+
+		if batch.Progress.Done {
+			return batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.WithStack(ctx.Err())
+		case <-time.After(a.interval):
+		}
+
+		var err error
+		if batch, err = a.client.GetBatch(ctx, batch.ID); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package engines keeps the valuation engines available to the workers, so that jobs can be created for a specific
+// engine name and version.
+package engines
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// Registry serves the workers as well as the use cases creating jobs.
+type Registry interface {
+	// Engine returns the engine a job was created for, the zero EngineInfo selects the default engine
+	Engine(info entities.EngineInfo) (worker.Engine, error)
+	// Resolve returns the engine for name and version, both empty select the default engine, an empty name alone the
+	// default engine's name and an empty version alone the latest version; it returns an entities.ErrUnknownEngine
+	// error if there is no such engine
+	Resolve(name string, version string) (entities.EngineInfo, error)
+	// Engines returns all engines ordered by name and version
+	Engines() []entities.EngineInfo
+	Default() entities.EngineInfo
+}
+
+type registry struct {
+	defaultEngine entities.EngineInfo
+	// engines by name, ordered by version
+	engines map[string][]worker.Engine
+}
+
+// NewRegistry registers the engines, later ones replace earlier ones of the same name and version.
+func NewRegistry(defaultEngine worker.Engine, others ...worker.Engine) Registry {
+	r := &registry{
+		defaultEngine: info(defaultEngine),
+		engines:       map[string][]worker.Engine{},
+	}
+
+	for _, engine := range append([]worker.Engine{defaultEngine}, others...) {
+		versions := slices.DeleteFunc(r.engines[engine.Name()], func(e worker.Engine) bool {
+			return e.Version() == engine.Version()
+		})
+		versions = append(versions, engine)
+
+		slices.SortFunc(versions, func(a, b worker.Engine) int {
+			return compareVersions(a.Version(), b.Version())
+		})
+
+		r.engines[engine.Name()] = versions
+	}
+
+	return r
+}
+
+func (r *registry) Engine(info entities.EngineInfo) (worker.Engine, error) {
+	if info == (entities.EngineInfo{}) {
+		info = r.defaultEngine
+	}
+
+	return r.lookup(info.Name, info.Version)
+}
+
+func (r *registry) Resolve(name string, version string) (entities.EngineInfo, error) {
+	if name == "" && version == "" {
+		return r.defaultEngine, nil
+	}
+
+	if name == "" {
+		name = r.defaultEngine.Name
+	}
+
+	engine, err := r.lookup(name, version)
+	if err != nil {
+		return entities.EngineInfo{}, err
+	}
+
+	return info(engine), nil
+}
+
+func (r *registry) Engines() []entities.EngineInfo {
+	names := make([]string, 0, len(r.engines))
+	for name := range r.engines {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	result := []entities.EngineInfo{}
+
+	for _, name := range names {
+		for _, engine := range r.engines[name] {
+			result = append(result, info(engine))
+		}
+	}
+
+	return result
+}
+
+func (r *registry) Default() entities.EngineInfo {
+	return r.defaultEngine
+}
+
+func (r *registry) lookup(name string, version string) (worker.Engine, error) {
+	versions := r.engines[name]
+	if len(versions) == 0 {
+		return nil, problem.Detailed(entities.ErrUnknownEngine, "engine %q", name)
+	}
+
+	if version == "" {
+		return versions[len(versions)-1], nil
+	}
+
+	for _, engine := range versions {
+		if engine.Version() == version {
+			return engine, nil
+		}
+	}
+
+	return nil, problem.Detailed(entities.ErrUnknownEngine, "engine %q in version %q", name, version)
+}
+
+func info(engine worker.Engine) entities.EngineInfo {
+	return entities.EngineInfo{Name: engine.Name(), Version: engine.Version()}
+}
+
+// compareVersions orders dotted versions part by part, numerically where both parts are numbers, so that 1.10.0
+// follows 1.9.0.
+func compareVersions(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	for i := 0; i < min(len(partsA), len(partsB)); i++ {
+		numberA, errA := strconv.Atoi(partsA[i])
+		numberB, errB := strconv.Atoi(partsB[i])
+
+		var result int
+		if errA == nil && errB == nil {
+			result = numberA - numberB
+		} else {
+			result = strings.Compare(partsA[i], partsB[i])
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return len(partsA) - len(partsB)
+}
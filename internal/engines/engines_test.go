@@ -0,0 +1,102 @@
+//nolint:funlen // Test functions are long, due to test cases
+package engines_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/engines"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/worker/mocks"
+)
+
+func engine(t *testing.T, name string, version string) *mocks.Engine {
+	t.Helper()
+
+	e := mocks.NewEngine(t)
+	e.On("Name").Return(name).Maybe()
+	e.On("Version").Return(version).Maybe()
+
+	return e
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	simulation := entities.EngineInfo{Name: "simulation", Version: "1.0.0"}
+
+	testCases := []struct {
+		name    string
+		engine  string
+		version string
+		want    entities.EngineInfo
+		wantErr error
+	}{
+		{
+			name: "empty name and version select the default engine",
+			want: simulation,
+		},
+		{
+			name:   "empty version selects the latest version",
+			engine: "simulation",
+			want:   entities.EngineInfo{Name: "simulation", Version: "1.10.0"},
+		},
+		{
+			name:    "version of the default engine is matched exactly",
+			version: "1.9.0",
+			want:    entities.EngineInfo{Name: "simulation", Version: "1.9.0"},
+		},
+		{
+			name:   "other engine in its latest version",
+			engine: "market",
+			want:   entities.EngineInfo{Name: "market", Version: "0.1"},
+		},
+		{
+			name:    "unknown version",
+			version: "3.0.0",
+			wantErr: entities.ErrUnknownEngine,
+		},
+		{
+			name:    "unknown engine",
+			engine:  "oracle",
+			wantErr: entities.ErrUnknownEngine,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := engines.NewRegistry(engine(t, "simulation", "1.0.0"),
+				engine(t, "simulation", "1.10.0"), engine(t, "simulation", "1.9.0"), engine(t, "market", "0.1"))
+
+			info, err := registry.Resolve(tc.engine, tc.version)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, info)
+
+			worker, err := registry.Engine(info)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want.Version, worker.Version())
+
+			defaultWorker, err := registry.Engine(entities.EngineInfo{})
+			assert.NoError(t, err)
+			assert.Equal(t, simulation.Version, defaultWorker.Version())
+
+			assert.Equal(t, simulation, registry.Default())
+			assert.Equal(t, []entities.EngineInfo{
+				{Name: "market", Version: "0.1"},
+				simulation,
+				{Name: "simulation", Version: "1.9.0"},
+				{Name: "simulation", Version: "1.10.0"},
+			}, registry.Engines())
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BatchMode int
+
+const (
+	// BatchModeAtomic creates all jobs of a batch or none of them
+	BatchModeAtomic BatchMode = iota
+	// BatchModeBestEffort creates as many jobs as the quota allows and rejects the rest
+	BatchModeBestEffort
+)
+
+// Batch groups the jobs submitted together.
+type Batch struct {
+	ID        uuid.UUID
+	OwnerID   string
+	Mode      BatchMode
+	CreatedAt time.Time
+	// Items in submission order
+	Items []BatchItem
+}
+
+type BatchItem struct {
+	// JobID is uuid.Nil if the item was rejected
+	JobID uuid.UUID
+	// Rejection explains why no job was created for the item
+	Rejection string
+}
@@ -0,0 +1,8 @@
+package entities
+
+// Document is an original file a patent was submitted as, kept alongside the text extracted from it.
+type Document struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
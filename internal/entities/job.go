@@ -1,21 +1,132 @@
 package entities
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
+// ErrUnknownEngine is returned for an engine name and version that no engine has.
+var ErrUnknownEngine = errors.New("unknown engine")
+
 type EvaluationJobStatus int
 
 const (
 	EvaluationJobStatusPending EvaluationJobStatus = iota
 	EvaluationJobStatusFinished
 	EvaluationJobStatusFailed
+	EvaluationJobStatusRunning
 )
 
+type JobPriority int
+
+const (
+	JobPriorityLow JobPriority = iota
+	JobPriorityNormal
+	JobPriorityHigh
+)
+
+// EngineInfo identifies an engine and its version.
+type EngineInfo struct {
+	Name    string
+	Version string
+}
+
+func (e EngineInfo) String() string {
+	return e.Name + "@" + e.Version
+}
+
+// CachedResult is the result of an earlier valuation of the same content.
+type CachedResult struct {
+	JobID      uuid.UUID
+	Value      int
+	Currency   string
+	Factors    []ValuationFactor
+	Confidence ConfidenceInterval
+	Engine     EngineInfo
+	FinishedAt time.Time
+}
+
+// JobOptions are the parameters a job is enqueued with.
+type JobOptions struct {
+	Priority JobPriority
+	// SchedulingWeight is the owner's share of the workers relative to other owners with queued jobs
+	SchedulingWeight int
+	// ContentHash identifies the normalized content, jobs with equal hashes share results
+	ContentHash string
+	// CachedResult, if set, finishes the job immediately with that result instead of queueing it
+	CachedResult *CachedResult
+	// BatchID links the job to the batch it was submitted with, uuid.Nil for single submissions
+	BatchID uuid.UUID
+	// TechnicalField optionally classifies the patent, empty for unclassified patents
+	TechnicalField string
+	// Patent is the structured document the content was taken from, nil for plain text submissions
+	Patent *Patent
+	// Document is the uploaded original file, nil unless the patent was submitted as a file
+	Document *Document
+	// Engine values the job, the zero value leaves the choice to the workers
+	Engine EngineInfo
+	// RevaluationOf links a follow-up job to the job it values again with another engine, uuid.Nil otherwise
+	RevaluationOf uuid.UUID
+	// PatentKey links the job to a logical patent across amended submissions, empty if unknown
+	PatentKey string
+	// Metadata is the owner's labels, tags and note for the job
+	Metadata JobMetadata
+}
+
 type EvaluationJob struct {
 	ID                  uuid.UUID
 	EvaluationJobStatus EvaluationJobStatus
 	PatentContent       string
 	Value               int
 	OwnerID             string
+	CreatedAt           time.Time
+	Priority            JobPriority
+	SchedulingWeight    int
+	ContentHash         string
+	BatchID             uuid.UUID
+	TechnicalField      string
+	// Patent is the structured document the content was taken from, nil for plain text submissions
+	Patent *Patent
+	// Document is the uploaded original file, nil unless the patent was submitted as a file
+	Document *Document
+	// RevaluationOf links a follow-up job to the job it values again with another engine, uuid.Nil otherwise
+	RevaluationOf uuid.UUID
+	// PatentKey links the job to a logical patent across amended submissions, empty if unknown
+	PatentKey string
+	// Metadata is the owner's labels, tags and note, it may change at any time
+	Metadata JobMetadata
+
+	// Currency is the ISO 4217 code of the value, set once it is finished
+	Currency string
+	// Factors and Confidence explain the value, set once it is finished
+	Factors    []ValuationFactor
+	Confidence ConfidenceInterval
+	// Engine values the job, chosen at creation and replaced by the engine that actually valued it once finished
+	Engine     EngineInfo
+	FinishedAt time.Time
+	// Cached reports whether the result was taken over from an earlier job with the same content
+	Cached bool
+
+	// Attempts counts the evaluations that have been started for this job
+	Attempts int
+	// Errors holds the history of failed attempts, oldest first
+	Errors []JobError
+	// NextAttemptAt is the earliest point in time the job may be picked up again after a failed attempt
+	NextAttemptAt time.Time
+	// QueuedAt is the point in time the job last became ready for a worker, zero while it is not waiting in the queue
+	QueuedAt time.Time
+	// WorkerID identifies the worker currently evaluating the job, empty unless the job is running
+	WorkerID string
+	// HeartbeatAt is the last sign of life of the worker evaluating the job
+	HeartbeatAt time.Time
+	// FailureReason explains why a job ended up failed
+	FailureReason string
+}
+
+type JobError struct {
+	Attempt    int
+	Message    string
+	OccurredAt time.Time
 }
@@ -0,0 +1,11 @@
+package entities
+
+// JobMetadata organises jobs for their owner, it can be changed at any time without affecting the valuation.
+type JobMetadata struct {
+	// Labels are key/value pairs such as a client matter number or project
+	Labels map[string]string
+	// Tags are free-form, sorted and without duplicates
+	Tags []string
+	// Note is a free-form comment
+	Note string
+}
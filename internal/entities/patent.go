@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Patent is a structured patent document as submitted, as opposed to opaque text content.
+type Patent struct {
+	Title             string
+	Abstract          string
+	Claims            []Claim
+	Description       string
+	PublicationNumber string
+	ApplicationNumber string
+	// PriorityDate, FilingDate and PublicationDate are zero if unknown
+	PriorityDate    time.Time
+	FilingDate      time.Time
+	PublicationDate time.Time
+	CPCClasses      []string
+	IPCClasses      []string
+	// CitedReferences are the publication numbers of the cited patent documents
+	CitedReferences []string
+	// Jurisdiction is an ISO 3166 country or a regional office code such as EP or WO
+	Jurisdiction string
+}
+
+type Claim struct {
+	Number int
+	Text   string
+	// DependsOn lists the numbers of the claims referred to, empty for independent claims or if unknown
+	DependsOn []int
+}
+
+// Text flattens the patent into the plain text content the engines value.
+func (p *Patent) Text() string {
+	var parts []string
+
+	add := func(part string) {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	add(p.Title)
+	add(p.Abstract)
+
+	for _, claim := range p.Claims {
+		add(claim.Text)
+	}
+
+	add(p.Description)
+
+	return strings.Join(parts, "\n\n")
+}
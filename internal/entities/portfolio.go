@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Portfolio is a named collection of jobs, given directly or by the batches they were submitted with.
+type Portfolio struct {
+	ID        uuid.UUID
+	OwnerID   string
+	Name      string
+	CreatedAt time.Time
+	JobIDs    []uuid.UUID
+	BatchIDs  []uuid.UUID
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "github.com/google/uuid"
+
+// SearchHit is a job matching a search query.
+type SearchHit struct {
+	JobID uuid.UUID
+	// Score is the relevance of the job, only comparable within the results of the same query
+	Score float64
+	// Snippets show the matches in the fields of the job
+	Snippets []Snippet
+}
+
+// Snippet is an excerpt of a field with the matches marked.
+type Snippet struct {
+	Field string
+	// Text is HTML escaped with the matches wrapped in <mark> elements
+	Text string
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ErrJobNotIndexed is returned for jobs that a similarity index does not hold.
+var ErrJobNotIndexed = errors.New("job is not indexed")
+
+// SimilarityMatch is a job found similar to another one.
+type SimilarityMatch struct {
+	JobID uuid.UUID
+	// Score is the cosine similarity of the patent texts from 0 (nothing in common) to 1 (same terms)
+	Score float64
+}
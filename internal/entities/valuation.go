@@ -0,0 +1,29 @@
+package entities
+
+// Valuation is the result of an engine valuing a patent.
+type Valuation struct {
+	// Value is the expected value in Currency
+	Value int
+	// Currency is the ISO 4217 code the value and the confidence interval are given in
+	Currency string
+	// Factors explain what contributed to the value, empty if the engine cannot explain its results
+	Factors    []ValuationFactor
+	Confidence ConfidenceInterval
+}
+
+// ValuationFactor is an aspect of the patent the engine rated.
+type ValuationFactor struct {
+	Name        string
+	Description string
+	// Weight is the factor's share of the value, the weights of all factors add up to 1
+	Weight float64
+	// Score rates the patent in this aspect from 0 to 100
+	Score float64
+}
+
+// ConfidenceInterval bounds the value with the given confidence level, a zero level means the engine gave none.
+type ConfidenceInterval struct {
+	Level float64
+	Lower int
+	Upper int
+}
@@ -0,0 +1,96 @@
+// Package patenttext segments the plain text of a patent document, e.g. as extracted from a PDF, into its sections.
+//
+// Sections are recognized by their usual headings, the first line is taken as title. Text that cannot be assigned
+// to a section ends up in the description.
+package patenttext
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+type section int
+
+const (
+	sectionDescription section = iota
+	sectionAbstract
+	sectionClaims
+)
+
+var (
+	abstractHeadings = []string{"abstract", "abstract of the disclosure", "zusammenfassung"}
+	// descriptionHeadings are kept in the description as they structure it
+	descriptionHeadings = []string{
+		"background", "background of the invention", "field", "field of the invention", "technical field",
+		"summary", "summary of the invention", "brief description of the drawings", "detailed description",
+	}
+	// plainDescriptionHeadings only mark the start of the description
+	plainDescriptionHeadings = []string{"description", "beschreibung"}
+	claimsHeadings           = []string{
+		"claims", "what is claimed is", "what is claimed", "we claim", "i claim", "the invention claimed is",
+		"patentansprüche", "patent claims",
+	}
+)
+
+// Segment splits text into title, abstract, description and numbered claims.
+func Segment(text string) entities.Patent {
+	var (
+		patent      entities.Patent
+		abstract    []string
+		description []string
+		claimLines  []string
+		current     = sectionDescription
+		// started is set after the first line, which is the title unless it is a heading
+		started bool
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		heading := strings.TrimRight(strings.ToLower(line), ": ")
+		title := !started
+		started = true
+
+		switch {
+		case slices.Contains(abstractHeadings, heading):
+			current = sectionAbstract
+		case slices.Contains(plainDescriptionHeadings, heading):
+			current = sectionDescription
+		case slices.Contains(descriptionHeadings, heading):
+			current = sectionDescription
+			description = append(description, line)
+		case slices.Contains(claimsHeadings, heading):
+			current = sectionClaims
+		case title:
+			patent.Title = line
+		case current == sectionAbstract:
+			abstract = append(abstract, line)
+		case current == sectionClaims:
+			claimLines = append(claimLines, line)
+		default:
+			description = append(description, line)
+		}
+	}
+
+	patent.Abstract = strings.Join(abstract, "\n")
+	patent.Description = strings.Join(description, "\n")
+	patent.Claims = claims.Parse(strings.Join(claimLines, "\n"))
+
+	return patent
+}
+
+// Claims returns the claims of the claims section of text or, without such a section, the numbered claims found
+// anywhere in text.
+func Claims(text string) []entities.Claim {
+	if result := Segment(text).Claims; len(result) > 0 {
+		return result
+	}
+
+	return claims.Parse(text)
+}
@@ -0,0 +1,87 @@
+//nolint:funlen // Test functions are long, due to test cases
+package patenttext_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patenttext"
+)
+
+func TestSegment(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		text string
+		want entities.Patent
+	}{
+		{
+			name: "sections with headings",
+			text: "Sealed bearing assembly\n" +
+				"Abstract\n" +
+				"A bearing assembly comprising a seal\n" +
+				"preventing the ingress of moisture.\n" +
+				"Description\n" +
+				"The invention relates to bearings.\n" +
+				"\n" +
+				"Claims\n" +
+				"1. A bearing assembly comprising an outer ring and\n" +
+				"an inner ring.\n" +
+				"2. The bearing assembly of claim 1, further comprising a seal.\n" +
+				"3) The bearing assembly according to claims 1 or 2, wherein the seal is a lip seal.",
+			want: entities.Patent{
+				Title:       "Sealed bearing assembly",
+				Abstract:    "A bearing assembly comprising a seal\npreventing the ingress of moisture.",
+				Description: "The invention relates to bearings.",
+				Claims: []entities.Claim{
+					{Number: 1, Text: "1. A bearing assembly comprising an outer ring and an inner ring."},
+					{
+						Number:    2,
+						Text:      "2. The bearing assembly of claim 1, further comprising a seal.",
+						DependsOn: []int{1},
+					},
+					{
+						Number:    3,
+						Text:      "3) The bearing assembly according to claims 1 or 2, wherein the seal is a lip seal.",
+						DependsOn: []int{1, 2},
+					},
+				},
+			},
+		},
+		{
+			name: "structuring headings are kept in the description",
+			text: "\nMethod for signature verification\n" +
+				"BACKGROUND OF THE INVENTION\n" +
+				"Signatures are verified.\n" +
+				"What is claimed is:\n" +
+				"1. A method comprising verifying a signature.",
+			want: entities.Patent{
+				Title:       "Method for signature verification",
+				Description: "BACKGROUND OF THE INVENTION\nSignatures are verified.",
+				Claims: []entities.Claim{
+					{Number: 1, Text: "1. A method comprising verifying a signature."},
+				},
+			},
+		},
+		{
+			name: "no headings",
+			text: "Title\nSome text\nmore text",
+			want: entities.Patent{
+				Title:       "Title",
+				Description: "Some text\nmore text",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, patenttext.Segment(tc.text))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package patentxml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// element is a node of the document tree that remembers where it starts in the document.
+type element struct {
+	name  string
+	attrs map[string]string
+	// content holds character data (string) and child elements (*element) in document order
+	content  []any
+	children []*element
+	position Position
+}
+
+// readTree reads the whole document into a tree and returns its root element.
+func readTree(r io.Reader) (*element, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = true
+	decoder.Entity = xml.HTMLEntity
+
+	var (
+		root  *element
+		stack []*element
+	)
+
+	for {
+		line, column := decoder.InputPos()
+		position := Position{Line: line, Column: column}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, syntaxError(position, err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			e := &element{name: token.Name.Local, attrs: map[string]string{}, position: position}
+			for _, attr := range token.Attr {
+				e.attrs[attr.Name.Local] = attr.Value
+			}
+
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, newError(position, "document has more than one root element")
+				}
+
+				root = e
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+				parent.content = append(parent.content, e)
+			}
+
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.content = append(parent.content, string(token))
+			} else if strings.TrimSpace(string(token)) != "" {
+				return nil, newError(position, "text outside of the root element")
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, newError(Position{Line: 1, Column: 1}, "document has no root element")
+	}
+
+	return root, nil
+}
+
+// syntaxError locates the error at the start of the offending token.
+func syntaxError(position Position, err error) error {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return newError(position, "%s", syntaxErr.Msg)
+	}
+
+	return newError(position, "%s", err.Error())
+}
+
+// child returns the first child with the given name, following the path of names, or nil.
+func (e *element) child(path ...string) *element {
+	current := e
+
+	for _, name := range path {
+		if current == nil {
+			return nil
+		}
+
+		var next *element
+
+		for _, child := range current.children {
+			if child.name == name {
+				next = child
+
+				break
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// all returns the children with the given name.
+func (e *element) all(name string) []*element {
+	if e == nil {
+		return nil
+	}
+
+	var result []*element
+
+	for _, child := range e.children {
+		if child.name == name {
+			result = append(result, child)
+		}
+	}
+
+	return result
+}
+
+// descendants returns all elements below e with the given name in document order.
+func (e *element) descendants(name string) []*element {
+	if e == nil {
+		return nil
+	}
+
+	var result []*element
+
+	for _, child := range e.children {
+		if child.name == name {
+			result = append(result, child)
+		}
+
+		result = append(result, child.descendants(name)...)
+	}
+
+	return result
+}
+
+// text returns the character data of e and its descendants with whitespace collapsed, "" for a nil element.
+func (e *element) text() string {
+	if e == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	e.writeText(&builder)
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+func (e *element) writeText(builder *strings.Builder) {
+	for _, content := range e.content {
+		switch content := content.(type) {
+		case string:
+			builder.WriteString(content)
+		case *element:
+			content.writeText(builder)
+		}
+	}
+}
+
+// paragraphs returns the text of the block elements (headings, paragraphs) of e, one per line.
+func (e *element) paragraphs() string {
+	if e == nil {
+		return ""
+	}
+
+	var lines []string
+
+	for _, child := range e.children {
+		if text := child.text(); text != "" {
+			lines = append(lines, text)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
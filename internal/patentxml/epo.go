@@ -0,0 +1,140 @@
+package patentxml
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+// epoCitation matches the cited patent documents of the search report like "EP-A- 0 123 456" or "WO-A-98/12345".
+var epoCitation = regexp.MustCompile(`^([A-Z]{2})-([A-Z][0-9]?)-\s*([0-9 /]+)`)
+
+// parseEPO reads an ep-patent-document, texts are taken in the language of the proceedings.
+func parseEPO(root *element) (entities.Patent, error) {
+	bibliographicData := root.child("SDOBI")
+	if bibliographicData == nil {
+		return entities.Patent{}, newError(root.position, "missing <SDOBI> bibliographic data")
+	}
+
+	lang := root.attrs["lang"]
+
+	title := epoTitle(bibliographicData.child("B500", "B540"), lang)
+	if title == "" {
+		return entities.Patent{}, newError(bibliographicData.position, "missing <B540> title")
+	}
+
+	number := root.attrs["doc-number"]
+	if number == "" {
+		number = bibliographicData.child("B100", "B110").text()
+	}
+
+	kind := root.attrs["kind"]
+	if kind == "" {
+		kind = bibliographicData.child("B100", "B130").text()
+	}
+
+	patent := entities.Patent{
+		Title:             title,
+		Abstract:          byLanguage(root.all("abstract"), lang).paragraphs(),
+		Description:       byLanguage(root.all("description"), lang).paragraphs(),
+		PublicationNumber: "EP" + number + kind,
+		ApplicationNumber: bibliographicData.child("B200", "B210").text(),
+		Jurisdiction:      "EP",
+	}
+
+	var err error
+
+	if patent.PublicationDate, err = parseDate(bibliographicData.child("B100", "B140", "date")); err != nil {
+		return entities.Patent{}, err
+	}
+
+	if patent.FilingDate, err = parseDate(bibliographicData.child("B200", "B220", "date")); err != nil {
+		return entities.Patent{}, err
+	}
+
+	for _, priority := range bibliographicData.child("B300").all("B320") {
+		date, err := parseDate(priority.child("date"))
+		if err != nil {
+			return entities.Patent{}, err
+		}
+
+		if patent.PriorityDate.IsZero() || date.Before(patent.PriorityDate) {
+			patent.PriorityDate = date
+		}
+	}
+
+	// without a claimed priority the filing date is the priority date
+	if patent.PriorityDate.IsZero() {
+		patent.PriorityDate = patent.FilingDate
+	}
+
+	for _, classification := range bibliographicData.descendants("classification-cpc") {
+		patent.CPCClasses = append(patent.CPCClasses, classificationText(classification.child("text").text()))
+	}
+
+	for _, classification := range bibliographicData.descendants("classification-ipcr") {
+		patent.IPCClasses = append(patent.IPCClasses, classificationText(classification.child("text").text()))
+	}
+
+	for _, citation := range bibliographicData.child("B500", "B560").all("B561") {
+		patent.CitedReferences = append(patent.CitedReferences, epoCitationNumber(citation.text()))
+	}
+
+	if patent.Claims, err = parseClaims(byLanguage(root.all("claims"), lang)); err != nil {
+		return entities.Patent{}, err
+	}
+
+	if len(patent.Claims) == 0 {
+		return entities.Patent{}, newError(root.position, "missing <claims>")
+	}
+
+	return patent, nil
+}
+
+// epoTitle picks the <B542> title following the <B541> language code, falling back to the first title.
+func epoTitle(titles *element, lang string) string {
+	var current, first string
+
+	for _, e := range titles.children {
+		switch e.name {
+		case "B541":
+			current = e.text()
+		case "B542":
+			if current == lang {
+				return e.text()
+			}
+
+			if first == "" {
+				first = e.text()
+			}
+		}
+	}
+
+	return first
+}
+
+// byLanguage picks the element in the given language, falling back to the first one.
+func byLanguage(elements []*element, lang string) *element {
+	for _, e := range elements {
+		if e.attrs["lang"] == lang {
+			return e
+		}
+	}
+
+	if len(elements) > 0 {
+		return elements[0]
+	}
+
+	return nil
+}
+
+// epoCitationNumber turns "EP-A- 0 123 456" into "EP0123456A", other citations are returned as they are.
+func epoCitationNumber(text string) string {
+	match := epoCitation.FindStringSubmatch(text)
+	if match == nil {
+		return text
+	}
+
+	return match[1] + strings.ReplaceAll(match[3], " ", "") + match[2]
+}
@@ -0,0 +1,147 @@
+// Package patentxml parses the XML patent documents of the USPTO (ST.36 based us-patent-grant and
+// us-patent-application) and of the EPO (ep-patent-document) into patents.
+package patentxml
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var (
+	ErrMalformedDocument   = errors.New("malformed patent document")
+	ErrUnsupportedDocument = errors.New("unsupported patent document")
+)
+
+// Position locates an error in the document, line and column start at 1.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Error is returned for documents that cannot be parsed, it wraps ErrMalformedDocument or ErrUnsupportedDocument.
+type Error struct {
+	Position Position
+	Message  string
+	err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Position.Line, e.Position.Column, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func newError(position Position, format string, args ...any) *Error {
+	return &Error{Position: position, Message: fmt.Sprintf(format, args...), err: ErrMalformedDocument}
+}
+
+// Parse reads a USPTO or EPO patent document, the format is detected by the root element.
+func Parse(r io.Reader) (entities.Patent, error) {
+	root, err := readTree(r)
+	if err != nil {
+		return entities.Patent{}, err
+	}
+
+	switch root.name {
+	case "us-patent-grant", "us-patent-application":
+		return parseUSPTO(root)
+	case "ep-patent-document":
+		return parseEPO(root)
+	default:
+		return entities.Patent{}, &Error{
+			Position: root.position,
+			Message:  fmt.Sprintf("unknown root element <%s>", root.name),
+			err:      ErrUnsupportedDocument,
+		}
+	}
+}
+
+// parseDate parses the YYYYMMDD dates of both formats, a nil element is the zero time.
+func parseDate(e *element) (time.Time, error) {
+	if e == nil {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse("20060102", e.text())
+	if err != nil {
+		return time.Time{}, newError(e.position, "malformed date %q, expected YYYYMMDD", e.text())
+	}
+
+	return date, nil
+}
+
+// parseClaims reads the claims of a <claims> element, resolving <claim-ref> elements to claim numbers.
+func parseClaims(claims *element) ([]entities.Claim, error) {
+	if claims == nil {
+		return nil, nil
+	}
+
+	numbersByID := map[string]int{}
+	result := make([]entities.Claim, 0, len(claims.all("claim")))
+
+	for i, claim := range claims.all("claim") {
+		number := i + 1
+
+		if num, ok := claim.attrs["num"]; ok {
+			parsed, err := strconv.Atoi(strings.TrimSpace(num))
+			if err != nil {
+				return nil, newError(claim.position, "malformed claim number %q", num)
+			}
+
+			number = parsed
+		}
+
+		numbersByID[claim.attrs["id"]] = number
+		result = append(result, entities.Claim{Number: number, Text: claim.text()})
+	}
+
+	for i, claim := range claims.all("claim") {
+		for _, ref := range claim.descendants("claim-ref") {
+			number, ok := numbersByID[ref.attrs["idref"]]
+			if !ok {
+				return nil, newError(ref.position, "reference to unknown claim %q", ref.attrs["idref"])
+			}
+
+			if number >= result[i].Number {
+				return nil, newError(ref.position, "claim %d refers to claim %d, which does not precede it",
+					result[i].Number, number)
+			}
+
+			result[i].DependsOn = appendUnique(result[i].DependsOn, number)
+		}
+	}
+
+	return result, nil
+}
+
+func appendUnique(values []int, value int) []int {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+// classificationText shortens the <text> of an EPO classification like "H04L   9/32   20060101AFI..."
+// to its symbol "H04L 9/32".
+func classificationText(text string) string {
+	const symbolFields = 2
+
+	fields := strings.Fields(text)
+	if len(fields) > symbolFields {
+		fields = fields[:symbolFields]
+	}
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,198 @@
+//nolint:funlen // Test functions are long, due to test cases
+package patentxml_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patentxml"
+)
+
+func date(value string) time.Time {
+	result, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		fixture string
+		want    entities.Patent
+	}{
+		{
+			fixture: "uspto-grant.xml",
+			want: entities.Patent{
+				Title: "Coherent LADAR using intra-pixel quadrature detection",
+				Abstract: "A frequency modulated (coherent) laser detection and ranging system includes a read-out " +
+					"integrated circuit formed with a two-dimensional array of detector elements.",
+				Claims: []entities.Claim{
+					{
+						Number: 1,
+						Text: "1. A laser detection and ranging (LADAR) system, comprising: a laser source; and " +
+							"a two-dimensional array of detector elements.",
+					},
+					{
+						Number:    2,
+						Text:      "2. The LADAR system according to claim 1, wherein the laser source is frequency modulated.",
+						DependsOn: []int{1},
+					},
+					{
+						Number: 3,
+						Text: "3. The LADAR system according to claim 1 or claim 2, wherein the detector elements are " +
+							"photodiodes.",
+						DependsOn: []int{1, 2},
+					},
+					{
+						Number: 4,
+						Text:   "4. A method of ranging, comprising emitting frequency modulated laser light.",
+					},
+				},
+				Description: "BACKGROUND\nThe invention relates to laser detection and ranging.\n" +
+					"Coherent detection offers a high sensitivity.",
+				PublicationNumber: "US10000000B2",
+				ApplicationNumber: "US14643719",
+				PriorityDate:      date("2014-01-24"),
+				FilingDate:        date("2015-03-10"),
+				PublicationDate:   date("2018-06-19"),
+				CPCClasses:        []string{"G01S 17/89", "G01S 7/4914"},
+				IPCClasses:        []string{"G01S 17/89"},
+				CitedReferences:   []string{"US4455495A", "US2012/0069342A1"},
+				Jurisdiction:      "US",
+			},
+		},
+		{
+			fixture: "uspto-application.xml",
+			want: entities.Patent{
+				Title:    "Signature verification for & between devices",
+				Abstract: "Devices verify signatures of each other.",
+				Claims: []entities.Claim{
+					{Number: 1, Text: "1. A method comprising verifying a signature."},
+					{
+						Number:    2,
+						Text:      "2. The method of claim 1, wherein the signature is an ECDSA signature.",
+						DependsOn: []int{1},
+					},
+				},
+				Description:       "Signatures are verified.",
+				PublicationNumber: "US20180000001A1",
+				ApplicationNumber: "US15635209",
+				PriorityDate:      date("2017-06-28"),
+				FilingDate:        date("2017-06-28"),
+				PublicationDate:   date("2018-01-04"),
+				CPCClasses:        []string{"H04L 9/3247"},
+				IPCClasses:        []string{"H04L 9/32"},
+				Jurisdiction:      "US",
+			},
+		},
+		{
+			fixture: "epo-publication.xml",
+			want: entities.Patent{
+				Title:    "Method for signature verification",
+				Abstract: "A method verifies signatures.",
+				Claims: []entities.Claim{
+					{Number: 1, Text: "A method for verifying a signature, comprising receiving the signature."},
+					{
+						Number:    2,
+						Text:      "A method according to claim 1, wherein the signature is a digital signature.",
+						DependsOn: []int{1},
+					},
+				},
+				Description:       "The present invention relates to signatures.\nSignatures are verified efficiently.",
+				PublicationNumber: "EP1234567B1",
+				ApplicationNumber: "01234567.8",
+				PriorityDate:      date("2000-02-16"),
+				FilingDate:        date("2001-02-15"),
+				PublicationDate:   date("2005-03-16"),
+				CPCClasses:        []string{"H04L 9/3247"},
+				IPCClasses:        []string{"H04L 9/32", "G06F 21/00"},
+				CitedReferences:   []string{"EP0123456A", "US2001012345A1", "WO98/12345A"},
+				Jurisdiction:      "EP",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.fixture, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := os.Open(filepath.Join("testdata", tc.fixture))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer file.Close()
+
+			got, err := patentxml.Parse(file)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		fixture  string
+		wantErr  error
+		position patentxml.Position
+		message  string
+	}{
+		{
+			fixture:  "malformed-syntax.xml",
+			wantErr:  patentxml.ErrMalformedDocument,
+			position: patentxml.Position{Line: 5, Column: 1},
+			message:  "element <invention-title> closed by </us-bibliographic-data-grant>",
+		},
+		{
+			fixture:  "malformed-claim-ref.xml",
+			wantErr:  patentxml.ErrMalformedDocument,
+			position: patentxml.Position{Line: 14, Column: 30},
+			message:  `reference to unknown claim "CLM-00009"`,
+		},
+		{
+			fixture:  "malformed-date.xml",
+			wantErr:  patentxml.ErrMalformedDocument,
+			position: patentxml.Position{Line: 4, Column: 13},
+			message:  `malformed date "2005-03-16", expected YYYYMMDD`,
+		},
+		{
+			fixture:  "unsupported.xml",
+			wantErr:  patentxml.ErrUnsupportedDocument,
+			position: patentxml.Position{Line: 2, Column: 1},
+			message:  "unknown root element <wo-patent-document>",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.fixture, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := os.Open(filepath.Join("testdata", tc.fixture))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer file.Close()
+
+			_, err = patentxml.Parse(file)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			var parseErr *patentxml.Error
+			if assert.ErrorAs(t, err, &parseErr) {
+				assert.Equal(t, tc.position, parseErr.Position)
+				assert.Equal(t, tc.message, parseErr.Message)
+			}
+		})
+	}
+}
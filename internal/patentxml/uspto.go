@@ -0,0 +1,110 @@
+package patentxml
+
+import (
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+// parseUSPTO reads a us-patent-grant or us-patent-application document.
+func parseUSPTO(root *element) (entities.Patent, error) {
+	bibliographicData := root.child("us-bibliographic-data-grant")
+	if bibliographicData == nil {
+		bibliographicData = root.child("us-bibliographic-data-application")
+	}
+
+	if bibliographicData == nil {
+		return entities.Patent{}, newError(root.position, "missing bibliographic data")
+	}
+
+	title := bibliographicData.child("invention-title")
+	if title.text() == "" {
+		return entities.Patent{}, newError(bibliographicData.position, "missing <invention-title>")
+	}
+
+	publication := bibliographicData.child("publication-reference", "document-id")
+	if publication == nil {
+		return entities.Patent{}, newError(bibliographicData.position, "missing <publication-reference>")
+	}
+
+	patent := entities.Patent{
+		Title:             title.text(),
+		Abstract:          root.child("abstract").paragraphs(),
+		Description:       root.child("description").paragraphs(),
+		PublicationNumber: usptoDocumentNumber(publication),
+		ApplicationNumber: usptoDocumentNumber(bibliographicData.child("application-reference", "document-id")),
+		Jurisdiction:      publication.child("country").text(),
+	}
+
+	var err error
+
+	if patent.PublicationDate, err = parseDate(publication.child("date")); err != nil {
+		return entities.Patent{}, err
+	}
+
+	if patent.FilingDate, err = parseDate(bibliographicData.child("application-reference", "document-id", "date")); err != nil {
+		return entities.Patent{}, err
+	}
+
+	for _, priorityClaim := range bibliographicData.child("priority-claims").all("priority-claim") {
+		date, err := parseDate(priorityClaim.child("date"))
+		if err != nil {
+			return entities.Patent{}, err
+		}
+
+		if patent.PriorityDate.IsZero() || date.Before(patent.PriorityDate) {
+			patent.PriorityDate = date
+		}
+	}
+
+	// without a claimed priority the filing date is the priority date
+	if patent.PriorityDate.IsZero() {
+		patent.PriorityDate = patent.FilingDate
+	}
+
+	for _, classification := range bibliographicData.child("classifications-cpc").descendants("classification-cpc") {
+		patent.CPCClasses = append(patent.CPCClasses, usptoClassification(classification))
+	}
+
+	for _, classification := range bibliographicData.child("classifications-ipcr").all("classification-ipcr") {
+		patent.IPCClasses = append(patent.IPCClasses, usptoClassification(classification))
+	}
+
+	// grants list the citations as us-references-cited, older documents as references-cited
+	for _, citation := range append(
+		bibliographicData.child("us-references-cited").descendants("patcit"),
+		bibliographicData.child("references-cited").descendants("patcit")...,
+	) {
+		patent.CitedReferences = append(patent.CitedReferences, usptoDocumentNumber(citation.child("document-id")))
+	}
+
+	if patent.Claims, err = parseClaims(root.child("claims")); err != nil {
+		return entities.Patent{}, err
+	}
+
+	if len(patent.Claims) == 0 {
+		return entities.Patent{}, newError(root.position, "missing <claims>")
+	}
+
+	return patent, nil
+}
+
+// usptoDocumentNumber joins country, number and kind of a <document-id> like "US10000000B2".
+func usptoDocumentNumber(documentID *element) string {
+	return documentID.child("country").text() + documentID.child("doc-number").text() + documentID.child("kind").text()
+}
+
+// usptoClassification formats a structured classification like "H04L 9/32".
+func usptoClassification(classification *element) string {
+	var symbol strings.Builder
+
+	symbol.WriteString(classification.child("section").text())
+	symbol.WriteString(classification.child("class").text())
+	symbol.WriteString(classification.child("subclass").text())
+	symbol.WriteString(" ")
+	symbol.WriteString(classification.child("main-group").text())
+	symbol.WriteString("/")
+	symbol.WriteString(classification.child("subgroup").text())
+
+	return symbol.String()
+}
@@ -0,0 +1,201 @@
+package pdftext
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// maxFormDepth limits nested form XObjects
+	maxFormDepth = 8
+	// wordGap is the TJ adjustment, in thousandths of the font size, above which a space is inserted
+	wordGap = 250
+)
+
+// resources are the fonts and XObjects available to a content stream.
+type resources struct {
+	fonts    dict
+	xObjects dict
+}
+
+// textWriter collects the text of a page, inserting line breaks where the text position moves to another line.
+type textWriter struct {
+	text strings.Builder
+	// y is the vertical position of the current line, lastY the one of the last shown text
+	y, lastY float64
+	shown    bool
+	newline  bool
+	space    bool
+	leading  float64
+}
+
+func (w *textWriter) show(text string) {
+	if text == "" {
+		return
+	}
+
+	if w.shown && (w.newline || math.Abs(w.y-w.lastY) > 0.5) { //nolint:gomnd // half a unit tolerates rounding
+		w.text.WriteString("\n")
+	} else if w.shown && w.space && !strings.HasSuffix(w.text.String(), " ") && !strings.HasPrefix(text, " ") {
+		w.text.WriteString(" ")
+	}
+
+	w.text.WriteString(text)
+	w.shown, w.newline, w.space, w.lastY = true, false, false, w.y
+}
+
+func (w *textWriter) move(tx, ty float64) {
+	if ty == 0 && tx > 0 {
+		w.space = true
+	}
+
+	w.y += ty
+}
+
+func (w *textWriter) nextLine() {
+	w.y -= w.leading
+	w.newline = true
+}
+
+func number(object any) float64 {
+	switch object := object.(type) {
+	case int:
+		return float64(object)
+	case float64:
+		return object
+	default:
+		return 0
+	}
+}
+
+// interpret runs a content stream and writes the shown text.
+//
+//nolint:cyclop,funlen // one branch per text operator
+func (d *document) interpret(content []byte, res resources, w *textWriter, depth int) {
+	l := &lexer{data: content}
+	fonts := map[name]*font{}
+
+	var (
+		operands []any
+		current  = &font{}
+	)
+
+	for {
+		object, err := l.object()
+		if err != nil {
+			if l.eof() {
+				return
+			}
+
+			operands = operands[:0]
+
+			continue
+		}
+
+		op, ok := object.(keyword)
+		if !ok {
+			operands = append(operands, object)
+
+			continue
+		}
+
+		switch op {
+		case "BI":
+			// inline images carry binary data up to EI
+			if end := strings.Index(string(l.data[l.pos:]), "EI"); end >= 0 {
+				l.pos += end + 2 //nolint:gomnd // len("EI")
+			}
+		case "BT":
+			w.y = 0
+		case "Tf":
+			if len(operands) == 2 { //nolint:gomnd // font and size
+				fontName, _ := operands[0].(name)
+				if _, ok := fonts[fontName]; !ok {
+					fonts[fontName] = d.font(d.dict(res.fonts[fontName]))
+				}
+
+				current = fonts[fontName]
+			}
+		case "TL":
+			if len(operands) == 1 {
+				w.leading = number(operands[0])
+			}
+		case "Td", "TD":
+			if len(operands) == 2 { //nolint:gomnd // tx and ty
+				if op == "TD" {
+					w.leading = -number(operands[1])
+				}
+
+				w.move(number(operands[0]), number(operands[1]))
+			}
+		case "Tm":
+			if len(operands) == 6 { //nolint:gomnd // matrix
+				w.y = number(operands[5])
+			}
+		case "T*":
+			w.nextLine()
+		case "Tj":
+			if len(operands) == 1 {
+				if s, ok := operands[0].(str); ok {
+					w.show(current.decode(s))
+				}
+			}
+		case "'", "\"":
+			w.nextLine()
+
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(str); ok {
+					w.show(current.decode(s))
+				}
+			}
+		case "TJ":
+			if len(operands) == 1 {
+				elements, _ := operands[0].(array)
+				for _, element := range elements {
+					switch element := element.(type) {
+					case str:
+						w.show(current.decode(element))
+					case int, float64:
+						if number(element) < -wordGap {
+							w.space = true
+						}
+					}
+				}
+			}
+		case "Do":
+			if len(operands) == 1 && depth < maxFormDepth {
+				xObjectName, _ := operands[0].(name)
+				d.form(res.xObjects[xObjectName], res, w, depth+1)
+			}
+		}
+
+		operands = operands[:0]
+	}
+}
+
+// form runs the content of a form XObject, images and other XObjects are ignored.
+func (d *document) form(object any, parent resources, w *textWriter, depth int) {
+	s, ok := d.resolve(object).(stream)
+	if !ok || d.resolve(s.dict[name("Subtype")]) != name("Form") {
+		return
+	}
+
+	data, err := d.decode(s)
+	if err != nil {
+		return
+	}
+
+	res := parent
+	if resourceDict := d.dict(s.dict[name("Resources")]); resourceDict != nil {
+		res = d.resources(resourceDict)
+	}
+
+	d.interpret(data, res, w, depth)
+}
+
+func (d *document) resources(resourceDict dict) resources {
+	return resources{
+		fonts:    d.dict(resourceDict[name("Font")]),
+		xObjects: d.dict(resourceDict[name("XObject")]),
+	}
+}
@@ -0,0 +1,264 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"io"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errUnsupportedFilter = errors.New("unsupported stream filter")
+
+	objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+)
+
+// document holds the objects of a PDF file by object number.
+//
+// Instead of following the cross-reference table, which is often damaged, the file is scanned for objects. Later
+// definitions win, so incremental updates are applied.
+type document struct {
+	objects map[int]any
+	// trailers holds the trailer dictionaries and cross-reference streams
+	trailers []dict
+	// decodeBudget is what streams may still decompress to in total, tooLarge is set once it is exhausted
+	decodeBudget int64
+	tooLarge     bool
+}
+
+func parseDocument(data []byte, maxDecodedSize int64) *document {
+	d := &document{objects: map[int]any{}, decodeBudget: maxDecodedSize}
+
+	var objectStreams []stream
+
+	for _, match := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		num, _ := parseNumber(data[match[2]:match[3]])
+
+		l := &lexer{data: data, pos: match[1]}
+
+		object, err := l.object()
+		if err != nil {
+			continue
+		}
+
+		if objectDict, ok := object.(dict); ok {
+			if s, ok := d.readStream(l, objectDict); ok {
+				object = s
+
+				switch objectDict[name("Type")] {
+				case name("ObjStm"):
+					objectStreams = append(objectStreams, s)
+				case name("XRef"):
+					d.trailers = append(d.trailers, objectDict)
+				}
+			}
+		}
+
+		d.objects[num.(int)] = object //nolint:forcetypeassert // the pattern only matches integers
+	}
+
+	for _, s := range objectStreams {
+		d.readObjectStream(s)
+	}
+
+	for _, match := range regexp.MustCompile(`trailer\s*<<`).FindAllIndex(data, -1) {
+		l := &lexer{data: data, pos: match[1] - 2}
+		if trailer, err := l.dictionary(0); err == nil {
+			d.trailers = append(d.trailers, trailer)
+		}
+	}
+
+	return d
+}
+
+// readStream reads the stream data following a dictionary, if there is one.
+func (d *document) readStream(l *lexer, streamDict dict) (stream, bool) {
+	l.skipSpace()
+
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return stream{}, false
+	}
+
+	start := l.pos + len("stream")
+	if bytes.HasPrefix(l.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(l.data) && (l.data[start] == '\n' || l.data[start] == '\r') {
+		start++
+	}
+
+	// a direct length is trusted if the stream ends there, otherwise the end is searched
+	if length, ok := streamDict[name("Length")].(int); ok && length >= 0 && start+length <= len(l.data) {
+		rest := bytes.TrimLeft(l.data[start+length:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return stream{dict: streamDict, data: l.data[start : start+length]}, true
+		}
+	}
+
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		return stream{}, false
+	}
+
+	return stream{dict: streamDict, data: bytes.TrimRight(l.data[start:start+end], "\r\n")}, true
+}
+
+// readObjectStream adds the objects compressed in an object stream, unless they are defined directly.
+func (d *document) readObjectStream(s stream) {
+	data, err := d.decode(s)
+	if err != nil {
+		return
+	}
+
+	count, _ := d.resolve(s.dict[name("N")]).(int)
+	first, _ := d.resolve(s.dict[name("First")]).(int)
+	header := &lexer{data: data}
+
+	for i := 0; i < count; i++ {
+		numObject, err := header.object()
+		if err != nil {
+			return
+		}
+
+		offsetObject, err := header.object()
+		if err != nil {
+			return
+		}
+
+		num, numOK := numObject.(int)
+		offset, offsetOK := offsetObject.(int)
+
+		if !numOK || !offsetOK || first+offset >= len(data) {
+			return
+		}
+
+		if _, ok := d.objects[num]; ok {
+			continue
+		}
+
+		if object, err := (&lexer{data: data, pos: first + offset}).object(); err == nil {
+			d.objects[num] = object
+		}
+	}
+}
+
+// resolve follows references, missing objects resolve to nil.
+func (d *document) resolve(object any) any {
+	for i := 0; i < 32; i++ { //nolint:gomnd // guards against reference cycles
+		r, ok := object.(ref)
+		if !ok {
+			return object
+		}
+
+		object = d.objects[r.num]
+	}
+
+	return nil
+}
+
+func (d *document) dict(object any) dict {
+	switch object := d.resolve(object).(type) {
+	case dict:
+		return object
+	case stream:
+		return object.dict
+	default:
+		return nil
+	}
+}
+
+func (d *document) encrypted() bool {
+	for _, trailer := range d.trailers {
+		if _, ok := trailer[name("Encrypt")]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decode applies the filters of the stream.
+func (d *document) decode(s stream) ([]byte, error) {
+	var filters []any
+
+	switch filter := d.resolve(s.dict[name("Filter")]).(type) {
+	case name:
+		filters = []any{filter}
+	case array:
+		filters = filter
+	}
+
+	data := s.data
+
+	for _, filter := range filters {
+		var err error
+
+		switch d.resolve(filter) {
+		case name("FlateDecode"), name("Fl"):
+			data, err = d.inflate(data)
+		case name("ASCIIHexDecode"), name("AHx"):
+			data, err = hex.DecodeString(string(bytes.TrimSuffix(bytes.Join(bytes.Fields(data), nil), []byte(">"))))
+		case name("ASCII85Decode"), name("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			return nil, errors.Wrapf(errUnsupportedFilter, "%v", filter)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot decode %v", filter)
+		}
+	}
+
+	return data, nil
+}
+
+// inflate decompresses zlib data, falling back to raw deflate and keeping what could be read from truncated data.
+// The output is charged against the decode budget, so that small streams cannot expand to exhaust the memory.
+func (d *document) inflate(data []byte) ([]byte, error) {
+	if d.tooLarge {
+		return nil, ErrTooLarge
+	}
+
+	var reader io.ReadCloser
+
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		reader = flate.NewReader(bytes.NewReader(data))
+	}
+	defer reader.Close()
+
+	result, err := io.ReadAll(io.LimitReader(reader, d.decodeBudget+1))
+	if int64(len(result)) > d.decodeBudget {
+		d.tooLarge = true
+
+		return nil, ErrTooLarge
+	}
+
+	d.decodeBudget -= int64(len(result))
+
+	if err != nil && len(result) == 0 {
+		return nil, errors.Wrap(err, "inflate")
+	}
+
+	return result, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+
+	result := make([]byte, len(data))
+
+	n, _, err := ascii85.Decode(result, data, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "ascii85")
+	}
+
+	return result[:n], nil
+}
@@ -0,0 +1,253 @@
+package pdftext
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// font maps the character codes of shown strings to text.
+type font struct {
+	// codeLengths holds the byte lengths of the codes in the ToUnicode code space, longest first
+	codeLengths []int
+	toUnicode   map[string]string
+	// twoByte is set for composite fonts with the Identity encodings
+	twoByte     bool
+	differences map[byte]string
+}
+
+// winAnsi holds the WinAnsiEncoding characters that differ from Latin-1.
+var winAnsi = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š',
+	0x8b: '‹', 0x8c: 'Œ', 0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›', 0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+}
+
+// glyphNames holds the glyph names used in /Differences that are not single letters.
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$", "percent": "%",
+	"ampersand": "&", "quotesingle": "'", "quoteright": "’", "quoteleft": "‘", "parenleft": "(", "parenright": ")",
+	"asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".", "slash": "/", "colon": ":",
+	"semicolon": ";", "less": "<", "equal": "=", "greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "underscore": "_", "braceleft": "{", "bar": "|", "braceright": "}",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5", "six": "6", "seven": "7",
+	"eight": "8", "nine": "9", "endash": "–", "emdash": "—", "bullet": "•", "degree": "°", "section": "§",
+	"paragraph": "¶", "fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "quotedblleft": "“",
+	"quotedblright": "”", "ellipsis": "…", "minus": "−", "multiply": "×", "plusminus": "±", "mu": "µ",
+}
+
+func (d *document) font(fontDict dict) *font {
+	f := &font{}
+
+	if encoding, ok := d.resolve(fontDict[name("Encoding")]).(name); ok {
+		f.twoByte = encoding == "Identity-H" || encoding == "Identity-V"
+	} else if encoding := d.dict(fontDict[name("Encoding")]); encoding != nil {
+		f.differences = d.differences(encoding)
+	}
+
+	if cmap, ok := d.resolve(fontDict[name("ToUnicode")]).(stream); ok {
+		if data, err := d.decode(cmap); err == nil {
+			f.parseCMap(data)
+		}
+	}
+
+	return f
+}
+
+func (d *document) differences(encoding dict) map[byte]string {
+	differences, _ := d.resolve(encoding[name("Differences")]).(array)
+	result := map[byte]string{}
+	code := 0
+
+	for _, entry := range differences {
+		switch entry := d.resolve(entry).(type) {
+		case int:
+			code = entry
+		case name:
+			if text, ok := glyphText(string(entry)); ok && code >= 0 && code <= 255 {
+				result[byte(code)] = text
+			}
+
+			code++
+		}
+	}
+
+	return result
+}
+
+func glyphText(glyph string) (string, bool) {
+	if text, ok := glyphNames[glyph]; ok {
+		return text, true
+	}
+
+	if len(glyph) == 1 {
+		return glyph, true
+	}
+
+	if hexCode, ok := strings.CutPrefix(glyph, "uni"); ok && len(hexCode) == 4 { //nolint:gomnd // uniXXXX
+		if r, err := strconv.ParseUint(hexCode, 16, 16); err == nil {
+			return string(rune(r)), true
+		}
+	}
+
+	return "", false
+}
+
+// parseCMap reads the code space and the bfchar and bfrange mappings of a ToUnicode CMap.
+//
+//nolint:cyclop // one branch per CMap section
+func (f *font) parseCMap(data []byte) {
+	f.toUnicode = map[string]string{}
+	lengths := map[int]bool{}
+	l := &lexer{data: data}
+
+	var operands []any
+
+	for {
+		object, err := l.object()
+		if err != nil {
+			break
+		}
+
+		op, ok := object.(keyword)
+		if !ok {
+			operands = append(operands, object)
+
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			for _, operand := range operands {
+				if code, ok := operand.(str); ok {
+					lengths[len(code)] = true
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				code, codeOK := operands[i].(str)
+				target, targetOK := operands[i+1].(str)
+
+				if codeOK && targetOK {
+					f.toUnicode[string(code)] = decodeUTF16(target)
+					lengths[len(code)] = true
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, lowOK := operands[i].(str)
+				high, highOK := operands[i+1].(str)
+
+				if lowOK && highOK && len(low) == len(high) && len(low) > 0 {
+					f.addRange(low, high, operands[i+2])
+					lengths[len(low)] = true
+				}
+			}
+		}
+
+		operands = operands[:0]
+	}
+
+	for length := 4; length > 0; length-- {
+		if lengths[length] {
+			f.codeLengths = append(f.codeLengths, length)
+		}
+	}
+}
+
+// addRange maps the codes from low to high, either to consecutive characters or to the entries of an array.
+func (f *font) addRange(low, high str, target any) {
+	start, end := codeValue(low), codeValue(high)
+	if end < start || end-start > 0xffff {
+		return
+	}
+
+	for value := start; value <= end; value++ {
+		code := make([]byte, len(low))
+		for i, v := len(code)-1, value; i >= 0; i, v = i-1, v>>8 {
+			code[i] = byte(v)
+		}
+
+		switch target := target.(type) {
+		case str:
+			text := []rune(decodeUTF16(target))
+			if len(text) > 0 {
+				text[len(text)-1] += rune(value - start)
+			}
+
+			f.toUnicode[string(code)] = string(text)
+		case array:
+			if index := value - start; index < len(target) {
+				if text, ok := target[index].(str); ok {
+					f.toUnicode[string(code)] = decodeUTF16(text)
+				}
+			}
+		}
+	}
+}
+
+func codeValue(code []byte) int {
+	value := 0
+	for _, b := range code {
+		value = value<<8 | int(b)
+	}
+
+	return value
+}
+
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2) //nolint:gomnd // two bytes per unit
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// decode turns the codes of a shown string into text.
+func (f *font) decode(data []byte) string {
+	var result strings.Builder
+
+	for len(data) > 0 {
+		length := f.nextCodeLength(data)
+		code := data[:length]
+		data = data[length:]
+
+		if text, ok := f.toUnicode[string(code)]; ok {
+			result.WriteString(text)
+
+			continue
+		}
+
+		switch {
+		case length > 1:
+			if r := rune(codeValue(code)); r >= ' ' && f.toUnicode == nil {
+				result.WriteRune(r)
+			}
+		case f.differences[code[0]] != "":
+			result.WriteString(f.differences[code[0]])
+		case winAnsi[code[0]] != 0:
+			result.WriteRune(winAnsi[code[0]])
+		default:
+			result.WriteRune(rune(code[0]))
+		}
+	}
+
+	return result.String()
+}
+
+func (f *font) nextCodeLength(data []byte) int {
+	for _, length := range f.codeLengths {
+		if length <= len(data) {
+			if _, ok := f.toUnicode[string(data[:length])]; ok {
+				return length
+			}
+		}
+	}
+
+	if f.twoByte && len(data) >= 2 { //nolint:gomnd // two byte codes
+		return 2 //nolint:gomnd // two byte codes
+	}
+
+	return 1
+}
@@ -0,0 +1,354 @@
+package pdftext
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// maxNestingDepth bounds nested arrays and dictionaries, real documents stay far below it.
+const maxNestingDepth = 64
+
+var errSyntax = errors.New("pdf syntax error")
+
+type (
+	name    string
+	keyword string
+	str     []byte
+	array   []any
+	dict    map[name]any
+	ref     struct{ num, gen int }
+	stream  struct {
+		dict dict
+		data []byte
+	}
+)
+
+// lexer reads the objects of the PDF syntax, it is used for the file body as well as for content streams and CMaps.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (l *lexer) eof() bool {
+	return l.pos >= len(l.data)
+}
+
+func (l *lexer) skipSpace() {
+	for !l.eof() {
+		switch c := l.data[l.pos]; {
+		case isWhitespace(c):
+			l.pos++
+		case c == '%':
+			for !l.eof() && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// regular reads a run of regular characters, i.e. a number, keyword or the rest of a name.
+func (l *lexer) regular() []byte {
+	start := l.pos
+	for !l.eof() && !isWhitespace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+
+	return l.data[start:l.pos]
+}
+
+// object reads the next object, operators and other bare words are returned as keyword.
+func (l *lexer) object() (any, error) {
+	return l.nestedObject(0)
+}
+
+// nestedObject reads an object inside depth arrays or dictionaries.
+//
+//nolint:cyclop // one branch per object type
+func (l *lexer) nestedObject(depth int) (any, error) {
+	l.skipSpace()
+
+	if l.eof() {
+		return nil, errors.Wrap(errSyntax, "unexpected end of data")
+	}
+
+	switch c := l.data[l.pos]; c {
+	case '/':
+		l.pos++
+
+		return name(decodeName(l.regular())), nil
+	case '(':
+		return l.literalString()
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			return l.dictionary(depth)
+		}
+
+		return l.hexString()
+	case '[':
+		l.pos++
+
+		return l.array(depth)
+	case ']', '>', ')', '{', '}':
+		l.pos++
+
+		return keyword(string(c)), nil
+	}
+
+	word := l.regular()
+	if len(word) == 0 {
+		l.pos++
+
+		return nil, errors.Wrapf(errSyntax, "unexpected character at %d", l.pos-1)
+	}
+
+	if number, ok := parseNumber(word); ok {
+		return l.maybeReference(number), nil
+	}
+
+	return keyword(word), nil
+}
+
+// maybeReference turns "num gen R" into a reference, other numbers are returned as they are.
+func (l *lexer) maybeReference(number any) any {
+	num, ok := number.(int)
+	if !ok {
+		return number
+	}
+
+	start := l.pos
+
+	l.skipSpace()
+
+	if gen, ok := parseNumber(l.regular()); ok {
+		if gen, ok := gen.(int); ok {
+			l.skipSpace()
+
+			if !l.eof() && l.data[l.pos] == 'R' && (l.pos+1 == len(l.data) ||
+				isWhitespace(l.data[l.pos+1]) || isDelimiter(l.data[l.pos+1])) {
+				l.pos++
+
+				return ref{num: num, gen: gen}
+			}
+		}
+	}
+
+	l.pos = start
+
+	return num
+}
+
+func parseNumber(word []byte) (any, bool) {
+	if len(word) == 0 || (word[0] != '+' && word[0] != '-' && word[0] != '.' && (word[0] < '0' || word[0] > '9')) {
+		return nil, false
+	}
+
+	if i, err := strconv.Atoi(string(word)); err == nil {
+		return i, true
+	}
+
+	if f, err := strconv.ParseFloat(string(word), 64); err == nil {
+		return f, true
+	}
+
+	return nil, false
+}
+
+func decodeName(raw []byte) string {
+	if bytes.IndexByte(raw, '#') < 0 {
+		return string(raw)
+	}
+
+	var result []byte
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if b, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				result = append(result, byte(b))
+				i += 2
+
+				continue
+			}
+		}
+
+		result = append(result, raw[i])
+	}
+
+	return string(result)
+}
+
+func (l *lexer) array(depth int) (array, error) {
+	if depth >= maxNestingDepth {
+		return nil, errors.Wrapf(errSyntax, "nesting deeper than %d at %d", maxNestingDepth, l.pos)
+	}
+
+	var result array
+
+	for {
+		l.skipSpace()
+
+		if !l.eof() && l.data[l.pos] == ']' {
+			l.pos++
+
+			return result, nil
+		}
+
+		object, err := l.nestedObject(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, object)
+	}
+}
+
+func (l *lexer) dictionary(depth int) (dict, error) {
+	if depth >= maxNestingDepth {
+		return nil, errors.Wrapf(errSyntax, "nesting deeper than %d at %d", maxNestingDepth, l.pos)
+	}
+
+	l.pos += 2
+	result := dict{}
+
+	for {
+		l.skipSpace()
+
+		if l.pos+1 < len(l.data) && l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+			l.pos += 2
+
+			return result, nil
+		}
+
+		key, err := l.nestedObject(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		keyName, ok := key.(name)
+		if !ok {
+			return nil, errors.Wrapf(errSyntax, "dictionary key %v is not a name", key)
+		}
+
+		value, err := l.nestedObject(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		result[keyName] = value
+	}
+}
+
+//nolint:cyclop // one branch per escape sequence
+func (l *lexer) literalString() (str, error) {
+	l.pos++
+	depth := 1
+
+	var result []byte
+
+	for !l.eof() {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return result, nil
+			}
+		case '\\':
+			if l.eof() {
+				continue
+			}
+
+			escaped := l.data[l.pos]
+			l.pos++
+
+			switch escaped {
+			case 'n':
+				result = append(result, '\n')
+			case 'r':
+				result = append(result, '\r')
+			case 't':
+				result = append(result, '\t')
+			case 'b':
+				result = append(result, '\b')
+			case 'f':
+				result = append(result, '\f')
+			case '\r':
+				if !l.eof() && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if escaped >= '0' && escaped <= '7' {
+					value := int(escaped - '0')
+
+					for i := 0; i < 2 && !l.eof() && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+
+					result = append(result, byte(value))
+				} else {
+					result = append(result, escaped)
+				}
+			}
+
+			continue
+		}
+
+		result = append(result, c)
+	}
+
+	return nil, errors.Wrap(errSyntax, "unterminated string")
+}
+
+func (l *lexer) hexString() (str, error) {
+	l.pos++
+
+	var digits []byte
+
+	for !l.eof() && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+
+		l.pos++
+	}
+
+	if l.eof() {
+		return nil, errors.Wrap(errSyntax, "unterminated hex string")
+	}
+
+	l.pos++
+
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	result := make([]byte, 0, len(digits)/2) //nolint:gomnd // two digits per byte
+
+	for i := 0; i < len(digits); i += 2 {
+		b, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return nil, errors.Wrap(errSyntax, "malformed hex string")
+		}
+
+		result = append(result, byte(b))
+	}
+
+	return result, nil
+}
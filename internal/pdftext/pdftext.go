@@ -0,0 +1,163 @@
+// Package pdftext extracts the text of PDF documents.
+//
+// It supports the subset of PDF found in patent documents: uncompressed and Flate, ASCIIHex or ASCII85 encoded
+// streams, object streams, simple fonts with standard or differences encodings and composite fonts with ToUnicode
+// CMaps. Layout is not reconstructed beyond line breaks, scanned documents without a text layer yield no text.
+package pdftext
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxPageTreeDepth guards against cyclic page trees
+	maxPageTreeDepth = 32
+	pageSeparator    = "\n\n"
+
+	// DefaultMaxDecodedSize limits what the streams of a document decompress to, unless WithMaxDecodedSize is given
+	DefaultMaxDecodedSize = 64 << 20
+)
+
+var (
+	ErrNotPDF       = errors.New("not a PDF document")
+	ErrEncrypted    = errors.New("encrypted PDF documents are not supported")
+	ErrMalformedPDF = errors.New("malformed PDF document")
+	ErrTooLarge     = errors.New("PDF streams decompress beyond the size limit")
+
+	pdfHeader = []byte("%PDF-")
+)
+
+// IsPDF reports whether data starts with the PDF header.
+func IsPDF(data []byte) bool {
+	return bytes.HasPrefix(data, pdfHeader)
+}
+
+type options struct {
+	maxDecodedSize int64
+}
+
+type Option func(*options)
+
+// WithMaxDecodedSize limits the total size the streams of a document decompress to, Extract fails with ErrTooLarge
+// beyond it. Zero keeps DefaultMaxDecodedSize.
+func WithMaxDecodedSize(size int64) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.maxDecodedSize = size
+		}
+	}
+}
+
+// Extract returns the text of all pages, pages are separated by an empty line.
+func Extract(data []byte, opts ...Option) (string, error) {
+	if !IsPDF(data) {
+		return "", ErrNotPDF
+	}
+
+	o := options{maxDecodedSize: DefaultMaxDecodedSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	d := parseDocument(data, o.maxDecodedSize)
+	if d.tooLarge {
+		return "", ErrTooLarge
+	}
+
+	if d.encrypted() {
+		return "", ErrEncrypted
+	}
+
+	root := d.catalog()
+	if root == nil {
+		return "", errors.Wrap(ErrMalformedPDF, "no page tree found")
+	}
+
+	var pages []string
+
+	d.walkPages(root[name("Pages")], resources{}, 0, func(page dict, res resources) {
+		w := &textWriter{}
+
+		for _, content := range d.contents(page) {
+			d.interpret(content, res, w, 0)
+			// content streams of a page may split operators, but text never continues across them
+			w.newline = true
+		}
+
+		if text := strings.TrimSpace(w.text.String()); text != "" {
+			pages = append(pages, text)
+		}
+	})
+
+	if d.tooLarge {
+		return "", ErrTooLarge
+	}
+
+	return strings.Join(pages, pageSeparator), nil
+}
+
+// catalog returns the document catalog referenced by the trailer or, failing that, found among the objects.
+func (d *document) catalog() dict {
+	for i := len(d.trailers) - 1; i >= 0; i-- {
+		if root := d.dict(d.trailers[i][name("Root")]); root != nil && root[name("Pages")] != nil {
+			return root
+		}
+	}
+
+	for _, object := range d.objects {
+		if root, ok := object.(dict); ok && root[name("Type")] == name("Catalog") && root[name("Pages")] != nil {
+			return root
+		}
+	}
+
+	return nil
+}
+
+func (d *document) walkPages(node any, inherited resources, depth int, visit func(page dict, res resources)) {
+	nodeDict := d.dict(node)
+	if nodeDict == nil || depth > maxPageTreeDepth {
+		return
+	}
+
+	res := inherited
+	if resourceDict := d.dict(nodeDict[name("Resources")]); resourceDict != nil {
+		res = d.resources(resourceDict)
+	}
+
+	if kids, ok := d.resolve(nodeDict[name("Kids")]).(array); ok {
+		for _, kid := range kids {
+			d.walkPages(kid, res, depth+1, visit)
+		}
+
+		return
+	}
+
+	visit(nodeDict, res)
+}
+
+// contents returns the decoded content streams of a page, streams that cannot be decoded are skipped.
+func (d *document) contents(page dict) [][]byte {
+	var streams []any
+
+	switch contents := d.resolve(page[name("Contents")]).(type) {
+	case stream:
+		streams = []any{contents}
+	case array:
+		streams = contents
+	}
+
+	result := make([][]byte, 0, len(streams))
+
+	for _, object := range streams {
+		if s, ok := d.resolve(object).(stream); ok {
+			if data, err := d.decode(s); err == nil {
+				result = append(result, data)
+			}
+		}
+	}
+
+	return result
+}
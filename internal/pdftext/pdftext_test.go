@@ -0,0 +1,179 @@
+//nolint:funlen // Test functions are long, due to test cases
+package pdftext_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/pdftext"
+)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		fixture string
+		want    string
+		wantErr error
+	}{
+		{
+			fixture: "simple.pdf",
+			want: "Sealed bearing assembly for wind turbines\n" +
+				"Abstract\n" +
+				"A bearing assembly comprising an outer ring, an inner ring and a seal\n" +
+				"preventing the ingress of moisture in offshore environments.\n" +
+				"Description\n" +
+				"The invention relates to bearings (rolling) for réduced friction.\n" +
+				"\n" +
+				"Claims\n" +
+				"1. A bearing assembly comprising an outer ring and an inner ring.\n" +
+				"2. The bearing assembly of claim 1, further comprising a seal.",
+		},
+		{
+			fixture: "compressed.pdf",
+			want:    "Verfahren zur\nHerstellung von Schaltkreisen\nfi",
+		},
+		{
+			fixture: "scanned.pdf",
+			want:    "",
+		},
+		{
+			fixture: "encrypted.pdf",
+			wantErr: pdftext.ErrEncrypted,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.fixture, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := pdftext.Extract(data)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExtract_NotPDF(t *testing.T) {
+	t.Parallel()
+
+	_, err := pdftext.Extract([]byte("<?xml version=\"1.0\"?>"))
+	assert.ErrorIs(t, err, pdftext.ErrNotPDF)
+}
+
+// document builds a PDF with a single page showing content, followed by the extra objects.
+func document(t *testing.T, content []byte, compressed bool, extra ...string) []byte {
+	t.Helper()
+
+	filter := ""
+
+	if compressed {
+		var buffer bytes.Buffer
+
+		w := zlib.NewWriter(&buffer)
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		content, filter = buffer.Bytes(), " /Filter /FlateDecode"
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d%s >>\nstream\n%s\nendstream", len(content), filter, content),
+	}
+
+	var pdf bytes.Buffer
+
+	pdf.WriteString("%PDF-1.4\n")
+
+	for i, object := range append(objects, extra...) {
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	pdf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	return pdf.Bytes()
+}
+
+func TestExtract_Limits(t *testing.T) {
+	t.Parallel()
+
+	text := []byte("BT /F1 10 Tf 72 760 Td (A bearing assembly) Tj ET")
+	// whitespace compresses well and is ignored by the content stream interpreter
+	padded := append(bytes.Repeat([]byte(" "), 1<<20), text...)
+
+	testCases := []struct {
+		name    string
+		data    []byte
+		options []pdftext.Option
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "deeply nested arrays",
+			data:    []byte("%PDF-1.4\n1 0 obj\n" + strings.Repeat("[", 10_000_000)),
+			wantErr: pdftext.ErrMalformedPDF,
+		},
+		{
+			name:    "deeply nested dictionaries",
+			data:    []byte("%PDF-1.4\n1 0 obj\n" + strings.Repeat("<< /Kids ", 1_000_000)),
+			wantErr: pdftext.ErrMalformedPDF,
+		},
+		{
+			name: "deeply nested objects are skipped",
+			data: document(t, text, false,
+				strings.Repeat("[", 100_000)+strings.Repeat("]", 100_000),
+				strings.Repeat("<< /A ", 100_000)+"1"+strings.Repeat(" >>", 100_000)),
+			want: "A bearing assembly",
+		},
+		{
+			name: "nesting within the limit",
+			data: document(t, text, false, strings.Repeat("[", 60)+strings.Repeat("]", 60)),
+			want: "A bearing assembly",
+		},
+		{
+			name: "decompressed within the limit",
+			data: document(t, padded, true),
+			want: "A bearing assembly",
+		},
+		{
+			name:    "decompressed beyond the limit",
+			data:    document(t, padded, true),
+			options: []pdftext.Option{pdftext.WithMaxDecodedSize(1 << 16)},
+			wantErr: pdftext.ErrTooLarge,
+		},
+		{
+			name:    "decompressed beyond the default limit",
+			data:    document(t, bytes.Repeat([]byte(" "), pdftext.DefaultMaxDecodedSize+1), true),
+			wantErr: pdftext.ErrTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pdftext.Extract(tc.data, tc.options...)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
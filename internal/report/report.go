@@ -0,0 +1,207 @@
+// Package report renders printable valuation reports as self-contained HTML documents: styles, the logo and the
+// charts (inline SVG) are part of the document, so that it can be saved, mailed or printed as is.
+package report
+
+import (
+	"encoding/base64"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var ErrInvalidBranding = errors.New("invalid report branding")
+
+// Renderer is safe for concurrent use.
+type Renderer interface {
+	// Render writes the report as a self-contained HTML document in the branding of the tenant
+	Render(w io.Writer, tenantID string, report Report) error
+}
+
+// Report is the content of a printable valuation report.
+type Report struct {
+	// Job is in the currency the report is requested in
+	Job Job
+	// Patent is the structured document, nil for plain text submissions
+	Patent      *entities.Patent
+	Claims      claims.Analysis
+	GeneratedAt time.Time
+}
+
+// Job is what a report shows of the valued job.
+type Job struct {
+	ID string
+	// Status is pending, running, finished or failed
+	Status         string
+	CreatedAt      time.Time
+	PatentKey      string
+	TechnicalField string
+	// Title and PublicationNumber are only known for structured submissions
+	Title             string
+	PublicationNumber string
+	// FileName is the name of the uploaded document, empty for other submissions
+	FileName string
+	Labels   map[string]string
+	Tags     []string
+	Note     string
+	// Valuation and Explanation are only present for finished jobs
+	Valuation   *Valuation
+	Explanation *Explanation
+	Cached      bool
+	Error       string
+}
+
+// Valuation is the value range of a finished job.
+type Valuation struct {
+	Currency string
+	Low      float64
+	Expected float64
+	High     float64
+	// Date is the day the patent was valued, YYYY-MM-DD
+	Date string
+}
+
+type Explanation struct {
+	Engine  entities.EngineInfo
+	Factors []ValuationFactor
+	// ConfidenceLevel is zero if the engine gave no confidence interval
+	ConfidenceLevel float64
+}
+
+type ValuationFactor struct {
+	Name        string
+	Description string
+	Weight      float64
+	Score       float64
+}
+
+// hexColor matches CSS hex colors like #fff or #1f3a5f.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// branding is the validated BrandingConfig with the logo loaded.
+type branding struct {
+	Name         string
+	Logo         template.URL
+	PrimaryColor template.CSS
+	AccentColor  template.CSS
+	Footer       string
+}
+
+type renderer struct {
+	template *template.Template
+	branding branding
+	// tenants are keyed by lowercase identity ID, as configuration keys are case-insensitive
+	tenants map[string]branding
+}
+
+// NewRenderer parses the report template and loads the brandings, tenant brandings inherit all fields they do not
+// set from the default branding.
+func NewRenderer(cfg *config.ReportConfig) (Renderer, error) {
+	reportTemplate, err := template.New(filepath.Base(cfg.TemplateFile)).Funcs(funcs).ParseFiles(cfg.TemplateFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse report template")
+	}
+
+	defaultBranding, err := loadBranding(cfg.Branding)
+	if err != nil {
+		return nil, errors.Wrap(err, "default")
+	}
+
+	tenants := make(map[string]branding, len(cfg.Tenants))
+
+	for tenantID, tenantConfig := range cfg.Tenants {
+		tenantBranding, err := loadBranding(inherit(tenantConfig, cfg.Branding))
+		if err != nil {
+			return nil, errors.Wrap(err, tenantID)
+		}
+
+		tenants[strings.ToLower(tenantID)] = tenantBranding
+	}
+
+	return &renderer{template: reportTemplate, branding: defaultBranding, tenants: tenants}, nil
+}
+
+func inherit(tenant config.BrandingConfig, defaults config.BrandingConfig) config.BrandingConfig {
+	fallback := func(value *string, defaultValue string) {
+		if *value == "" {
+			*value = defaultValue
+		}
+	}
+
+	fallback(&tenant.Name, defaults.Name)
+	fallback(&tenant.LogoFile, defaults.LogoFile)
+	fallback(&tenant.PrimaryColor, defaults.PrimaryColor)
+	fallback(&tenant.AccentColor, defaults.AccentColor)
+	fallback(&tenant.Footer, defaults.Footer)
+
+	return tenant
+}
+
+func loadBranding(cfg config.BrandingConfig) (branding, error) {
+	result := branding{Name: cfg.Name, Footer: cfg.Footer}
+
+	for _, color := range []struct {
+		value  string
+		target *template.CSS
+	}{
+		{value: cfg.PrimaryColor, target: &result.PrimaryColor},
+		{value: cfg.AccentColor, target: &result.AccentColor},
+	} {
+		if color.value == "" {
+			continue
+		}
+
+		if !hexColor.MatchString(color.value) {
+			return branding{}, errors.Wrapf(ErrInvalidBranding, "color %q is no CSS hex color", color.value)
+		}
+
+		// the pattern leaves no room for anything but a color
+		*color.target = template.CSS(color.value) //nolint:gosec // validated above
+	}
+
+	if cfg.LogoFile != "" {
+		logo, err := os.ReadFile(cfg.LogoFile)
+		if err != nil {
+			return branding{}, errors.Wrap(ErrInvalidBranding, err.Error())
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(cfg.LogoFile))
+		if contentType == "" {
+			contentType = http.DetectContentType(logo)
+		}
+
+		if !strings.HasPrefix(contentType, "image/") {
+			return branding{}, errors.Wrapf(ErrInvalidBranding, "logo %s is no image but %s", cfg.LogoFile, contentType)
+		}
+
+		// a data URI built from a configured file, not from user input
+		result.Logo = template.URL( //nolint:gosec // see above
+			"data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(logo))
+	}
+
+	return result, nil
+}
+
+func (r *renderer) Render(w io.Writer, tenantID string, report Report) error {
+	tenantBranding, ok := r.tenants[strings.ToLower(tenantID)]
+	if !ok {
+		tenantBranding = r.branding
+	}
+
+	if err := r.template.Execute(w, newView(tenantBranding, report)); err != nil {
+		return errors.Wrap(err, "cannot render report")
+	}
+
+	return nil
+}
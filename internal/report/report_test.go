@@ -0,0 +1,202 @@
+//nolint:funlen // Test functions are long, due to test cases
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/report"
+)
+
+const templateFile = "../../templates/report.html.tmpl"
+
+func TestNewRenderer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		cfg     config.ReportConfig
+		wantErr error
+	}{
+		{
+			name: "default branding only",
+			cfg:  config.ReportConfig{TemplateFile: templateFile, Branding: config.BrandingConfig{Name: "patAi"}},
+		},
+		{
+			name: "short hex colors",
+			cfg: config.ReportConfig{
+				TemplateFile: templateFile,
+				Branding:     config.BrandingConfig{PrimaryColor: "#123", AccentColor: "#abcdef"},
+			},
+		},
+		{
+			name: "color that is no hex color",
+			cfg: config.ReportConfig{
+				TemplateFile: templateFile,
+				Branding:     config.BrandingConfig{PrimaryColor: "red;background:url(x)"},
+			},
+			wantErr: report.ErrInvalidBranding,
+		},
+		{
+			name: "invalid tenant color",
+			cfg: config.ReportConfig{
+				TemplateFile: templateFile,
+				Tenants:      map[string]config.BrandingConfig{"tenant": {AccentColor: "#12345"}},
+			},
+			wantErr: report.ErrInvalidBranding,
+		},
+		{
+			name: "missing logo",
+			cfg: config.ReportConfig{
+				TemplateFile: templateFile,
+				Branding:     config.BrandingConfig{LogoFile: "does-not-exist.png"},
+			},
+			wantErr: report.ErrInvalidBranding,
+		},
+		{
+			name: "logo that is no image",
+			cfg: config.ReportConfig{
+				TemplateFile: templateFile,
+				Branding:     config.BrandingConfig{LogoFile: templateFile},
+			},
+			wantErr: report.ErrInvalidBranding,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			renderer, err := report.NewRenderer(&tc.cfg)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, renderer)
+		})
+	}
+
+	t.Run("missing template", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := report.NewRenderer(&config.ReportConfig{TemplateFile: "does-not-exist.tmpl"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	renderer, err := report.NewRenderer(&config.ReportConfig{
+		TemplateFile: templateFile,
+		Branding:     config.BrandingConfig{Name: "patAi", Footer: "Indicative valuation only"},
+		Tenants: map[string]config.BrandingConfig{
+			"Tenant-ID": {Name: "ACME IP Counsel", PrimaryColor: "#0b6e4f"},
+		},
+	})
+	assert.NoError(t, err)
+
+	finished := report.Report{
+		Job: report.Job{
+			ID:       "0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+			Status:   "finished",
+			Title:    "Widget <script>alert(1)</script>",
+			Tags:     []string{"priority"},
+			FileName: "widget.pdf",
+			Valuation: &report.Valuation{
+				Currency: "EUR", Low: 900000, Expected: 1000000, High: 1100000, Date: "2026-10-19",
+			},
+			Explanation: &report.Explanation{
+				Engine: entities.EngineInfo{Name: "simulation", Version: "1"},
+				Factors: []report.ValuationFactor{
+					{Name: "claims", Weight: 0.6, Score: 80},
+					{Name: "citations", Weight: 0.4, Score: 40},
+				},
+				ConfidenceLevel: 0.9,
+			},
+		},
+		Patent: &entities.Patent{
+			Jurisdiction: "EP",
+			FilingDate:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+			CPCClasses:   []string{"G06F", "H04L"},
+		},
+		Claims:      claims.Analysis{Metrics: claims.Metrics{Claims: 3, IndependentClaims: 1, DependentClaims: 2}},
+		GeneratedAt: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name        string
+		tenantID    string
+		report      report.Report
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "finished job",
+			tenantID: "unknown-tenant",
+			report:   finished,
+			contains: []string{
+				"Widget &lt;script&gt;alert(1)&lt;/script&gt;",
+				"1,000,000 EUR",
+				"900,000 – 1,100,000 EUR",
+				"<svg",
+				"90 %",
+				"60 %",
+				"G06F, H04L",
+				"2020-03-01",
+				"widget.pdf",
+				"3 (1 independent, 2 dependent)",
+				"Indicative valuation only",
+				"Job 0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+				"--primary: #1f3a5f",
+			},
+			notContains: []string{"<script>"},
+		},
+		{
+			name:     "tenant branding inherits the default footer",
+			tenantID: "tenant-id",
+			report:   finished,
+			contains: []string{"ACME IP Counsel", "--primary: #0b6e4f", "--accent: #e07a1f", "Indicative valuation only"},
+		},
+		{
+			name:        "pending job",
+			report:      report.Report{Job: report.Job{ID: "pending-job", Status: "pending"}},
+			contains:    []string{"Untitled submission", "The valuation is pending"},
+			notContains: []string{"<svg", "Claims</h2>"},
+		},
+		{
+			name:     "failed job",
+			report:   report.Report{Job: report.Job{ID: "failed-job", Status: "failed", Error: "engine unavailable"}},
+			contains: []string{"The valuation failed: engine unavailable"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var rendered bytes.Buffer
+
+			assert.NoError(t, renderer.Render(&rendered, tc.tenantID, tc.report))
+
+			for _, want := range tc.contains {
+				assert.Contains(t, rendered.String(), want)
+			}
+
+			for _, unwanted := range tc.notContains {
+				assert.NotContains(t, rendered.String(), unwanted)
+			}
+		})
+	}
+}
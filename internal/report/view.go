@@ -0,0 +1,194 @@
+package report
+
+import (
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+const (
+	defaultPrimaryColor = "#1f3a5f"
+	defaultAccentColor  = "#e07a1f"
+
+	// chart geometry in SVG user units
+	chartWidth      = 640
+	factorLabelSize = 200
+	factorBarHeight = 22
+	factorBarGap    = 10
+	rangeMargin     = 40
+)
+
+// view is what the template renders, with the chart geometry worked out beforehand.
+type view struct {
+	Branding    branding
+	Job         Job
+	Patent      *entities.Patent
+	Claims      claims.Metrics
+	GeneratedAt time.Time
+	Factors     []factor
+	// FactorChartHeight fits all factor bars
+	FactorChartHeight float64
+	// Range is nil unless the job has a valuation
+	Range *valueRange
+}
+
+type factor struct {
+	Name        string
+	Description string
+	// Weight is the share of the factor in the overall score, in percent
+	Weight float64
+	// Score is the factor's score from 0 to 100
+	Score float64
+	// Y and Width place the factor's bar in the factor chart, TextY its label
+	Y     float64
+	TextY float64
+	Width float64
+}
+
+// valueRange places the low, expected and high value on an axis from 0 to Max.
+type valueRange struct {
+	Currency  string
+	Low       float64
+	Expected  float64
+	High      float64
+	Max       float64
+	LowX      float64
+	ExpectedX float64
+	SpanWidth float64
+	// Ticks label the axis
+	Ticks []tick
+}
+
+type tick struct {
+	X     float64
+	Value float64
+}
+
+func newView(b branding, report Report) view {
+	if b.PrimaryColor == "" {
+		b.PrimaryColor = defaultPrimaryColor
+	}
+
+	if b.AccentColor == "" {
+		b.AccentColor = defaultAccentColor
+	}
+
+	result := view{
+		Branding:    b,
+		Job:         report.Job,
+		Patent:      report.Patent,
+		Claims:      report.Claims.Metrics,
+		GeneratedAt: report.GeneratedAt,
+	}
+
+	if explanation := report.Job.Explanation; explanation != nil {
+		for i, f := range explanation.Factors {
+			y := float64(i * (factorBarHeight + factorBarGap))
+			score := math.Max(0, math.Min(f.Score, 100))                      //nolint:gomnd // scores are percentages
+			width := coordinate(score / 100 * (chartWidth - factorLabelSize)) //nolint:gomnd // percent
+
+			result.Factors = append(result.Factors, factor{
+				Name:        f.Name,
+				Description: f.Description,
+				Weight:      f.Weight * 100, //nolint:gomnd // percent
+				Score:       f.Score,
+				Y:           y,
+				TextY:       y + factorBarHeight*0.7, //nolint:gomnd // baseline of the label within the bar
+				Width:       width,
+			})
+		}
+
+		result.FactorChartHeight = float64(len(result.Factors)*(factorBarHeight+factorBarGap) - factorBarGap)
+	}
+
+	if valuation := report.Job.Valuation; valuation != nil && valuation.High > 0 {
+		result.Range = newValueRange(valuation)
+	}
+
+	return result
+}
+
+func newValueRange(valuation *Valuation) *valueRange {
+	maxValue := niceCeiling(valuation.High * 1.1) //nolint:gomnd // leave room to the right of the high value
+	x := func(value float64) float64 {
+		return coordinate(rangeMargin + value/maxValue*(chartWidth-2*rangeMargin))
+	}
+
+	result := &valueRange{
+		Currency:  valuation.Currency,
+		Low:       valuation.Low,
+		Expected:  valuation.Expected,
+		High:      valuation.High,
+		Max:       maxValue,
+		LowX:      x(valuation.Low),
+		ExpectedX: x(valuation.Expected),
+		SpanWidth: coordinate(x(valuation.High) - x(valuation.Low)),
+	}
+
+	const ticks = 4
+	for i := 0; i <= ticks; i++ {
+		value := maxValue * float64(i) / ticks
+		result.Ticks = append(result.Ticks, tick{X: x(value), Value: value})
+	}
+
+	return result
+}
+
+// coordinate rounds to hundredths of a user unit, which is plenty for the charts and keeps the SVG readable.
+func coordinate(value float64) float64 {
+	return math.Round(value*100) / 100 //nolint:gomnd // hundredths
+}
+
+// niceCeiling rounds up to 1, 2 or 5 times a power of ten, so that the axis ticks are round numbers.
+func niceCeiling(value float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(value))) //nolint:gomnd // decimal magnitude
+
+	for _, step := range []float64{1, 2, 5, 10} {
+		if value <= step*magnitude {
+			return step * magnitude
+		}
+	}
+
+	return 10 * magnitude //nolint:gomnd // next magnitude
+}
+
+//nolint:gochecknoglobals // template functions
+var funcs = template.FuncMap{
+	"amount":  amount,
+	"number":  func(value float64) string { return strconv.FormatFloat(value, 'f', 0, 64) },
+	"percent": func(value float64) string { return strconv.FormatFloat(value*100, 'f', -1, 64) }, //nolint:gomnd // percent
+	"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+	"join":    strings.Join,
+}
+
+// amount formats money with thousands separators, cents are only shown if there are any: 1,234,567 or 1,234.50.
+func amount(value float64) string {
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', 2, 64)
+	whole, cents, _ := strings.Cut(formatted, ".")
+
+	var grouped strings.Builder
+
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String()
+	if cents != "00" {
+		result += "." + cents
+	}
+
+	if value < 0 {
+		result = "-" + result
+	}
+
+	return result
+}
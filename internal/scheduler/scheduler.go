@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+// Item is a job waiting to be scheduled.
+type Item struct {
+	ID       uuid.UUID
+	OwnerID  string
+	Priority entities.JobPriority
+	// Weight is the owner's share of the workers, values below 1 count as 1
+	Weight int
+}
+
+// Scheduler decides which queued job is evaluated next. Implementations are not safe for concurrent use.
+type Scheduler interface {
+	Push(item Item)
+	// Pop returns the next item to evaluate, false if nothing is queued
+	Pop() (Item, bool)
+	// Remove drops a queued item, false if it was not queued
+	Remove(id uuid.UUID) bool
+	Len() int
+}
+
+//nolint:gochecknoglobals // lookup table
+var priorityFactors = map[entities.JobPriority]int{
+	entities.JobPriorityLow:    1,
+	entities.JobPriorityNormal: 2,
+	entities.JobPriorityHigh:   4,
+}
+
+type ownerQueue struct {
+	ownerID string
+	weight  int
+	current int
+	// items by priority, each in arrival order
+	items map[entities.JobPriority][]Item
+	size  int
+}
+
+// head returns the owner's next item: the oldest one of the highest priority.
+func (o *ownerQueue) head() (entities.JobPriority, bool) {
+	for priority := entities.JobPriorityHigh; priority >= entities.JobPriorityLow; priority-- {
+		if len(o.items[priority]) > 0 {
+			return priority, true
+		}
+	}
+
+	return 0, false
+}
+
+func (o *ownerQueue) effectiveWeight() int {
+	priority, _ := o.head()
+
+	return o.weight * priorityFactors[priority]
+}
+
+type weightedFairScheduler struct {
+	// owners with queued items in order of arrival, keeps tie breaking deterministic
+	owners  []*ownerQueue
+	byOwner map[string]*ownerQueue
+	size    int
+}
+
+// NewWeightedFairScheduler returns a scheduler that picks owners by smooth weighted round-robin, so every owner
+// with queued items gets a share of the pops proportional to its weight, boosted by the priority of its next item.
+// Within an owner higher priorities go first, equal priorities in arrival order.
+func NewWeightedFairScheduler() Scheduler {
+	return &weightedFairScheduler{
+		owners:  []*ownerQueue{},
+		byOwner: map[string]*ownerQueue{},
+	}
+}
+
+func (s *weightedFairScheduler) Push(item Item) {
+	owner := s.byOwner[item.OwnerID]
+	if owner == nil {
+		owner = &ownerQueue{
+			ownerID: item.OwnerID,
+			items:   map[entities.JobPriority][]Item{},
+		}
+		s.byOwner[item.OwnerID] = owner
+		s.owners = append(s.owners, owner)
+	}
+
+	owner.weight = max(item.Weight, 1)
+	owner.items[item.Priority] = append(owner.items[item.Priority], item)
+	owner.size++
+	s.size++
+}
+
+func (s *weightedFairScheduler) Pop() (Item, bool) {
+	if s.size == 0 {
+		return Item{}, false
+	}
+
+	var (
+		total int
+		next  *ownerQueue
+	)
+
+	for _, owner := range s.owners {
+		weight := owner.effectiveWeight()
+		owner.current += weight
+		total += weight
+
+		if next == nil || owner.current > next.current {
+			next = owner
+		}
+	}
+
+	next.current -= total
+
+	priority, _ := next.head()
+	item := next.items[priority][0]
+	next.items[priority] = next.items[priority][1:]
+	next.size--
+	s.size--
+
+	s.dropIfEmpty(next)
+
+	return item, true
+}
+
+func (s *weightedFairScheduler) Remove(id uuid.UUID) bool {
+	for _, owner := range s.owners {
+		for priority, items := range owner.items {
+			for i, item := range items {
+				if item.ID != id {
+					continue
+				}
+
+				owner.items[priority] = append(items[:i:i], items[i+1:]...)
+				owner.size--
+				s.size--
+
+				s.dropIfEmpty(owner)
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *weightedFairScheduler) dropIfEmpty(owner *ownerQueue) {
+	if owner.size > 0 {
+		return
+	}
+
+	delete(s.byOwner, owner.ownerID)
+
+	for i, o := range s.owners {
+		if o == owner {
+			s.owners = append(s.owners[:i:i], s.owners[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (s *weightedFairScheduler) Len() int {
+	return s.size
+}
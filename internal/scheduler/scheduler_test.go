@@ -0,0 +1,207 @@
+//nolint:funlen // Test functions are long, due to test cases
+package scheduler_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/scheduler"
+)
+
+func push(s scheduler.Scheduler, owner string, weight int, priority entities.JobPriority, n int) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, n)
+
+	for i := 0; i < n; i++ {
+		id := uuid.New()
+		ids = append(ids, id)
+		s.Push(scheduler.Item{ID: id, OwnerID: owner, Priority: priority, Weight: weight})
+	}
+
+	return ids
+}
+
+func popOwners(t *testing.T, s scheduler.Scheduler, n int) []string {
+	t.Helper()
+
+	owners := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		item, ok := s.Pop()
+		if !assert.True(t, ok, "pop %d", i) {
+			break
+		}
+
+		owners = append(owners, item.OwnerID)
+	}
+
+	return owners
+}
+
+func count(owners []string) map[string]int {
+	result := map[string]int{}
+	for _, o := range owners {
+		result[o]++
+	}
+
+	return result
+}
+
+func TestWeightedFairScheduler_OrderWithinOwner(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.NewWeightedFairScheduler()
+
+	normal := push(s, "Alice", 1, entities.JobPriorityNormal, 2)
+	low := push(s, "Alice", 1, entities.JobPriorityLow, 1)
+	high := push(s, "Alice", 1, entities.JobPriorityHigh, 2)
+
+	var got []uuid.UUID
+
+	for {
+		item, ok := s.Pop()
+		if !ok {
+			break
+		}
+
+		got = append(got, item.ID)
+	}
+
+	want := append(append(append([]uuid.UUID{}, high...), normal...), low...)
+	assert.Equal(t, want, got, "higher priorities first, arrival order within a priority")
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestWeightedFairScheduler_BurstDoesNotStarveOthers(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.NewWeightedFairScheduler()
+
+	push(s, "Alice", 1, entities.JobPriorityNormal, 100)
+	push(s, "Bob", 1, entities.JobPriorityNormal, 1)
+	push(s, "Carol", 1, entities.JobPriorityNormal, 1)
+
+	owners := popOwners(t, s, 3)
+
+	assert.ElementsMatch(t, []string{"Alice", "Bob", "Carol"}, owners,
+		"every owner is served once per round regardless of Alice's backlog")
+}
+
+func TestWeightedFairScheduler_LateArrivalIsServedWithinOneRound(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.NewWeightedFairScheduler()
+
+	push(s, "Alice", 1, entities.JobPriorityNormal, 100)
+	popOwners(t, s, 10)
+	push(s, "Bob", 1, entities.JobPriorityNormal, 1)
+
+	assert.Contains(t, popOwners(t, s, 2), "Bob")
+}
+
+func TestWeightedFairScheduler_ShareIsProportionalToWeight(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		weights map[string]int
+		pops    int
+		want    map[string]int
+	}{
+		{
+			name:    "equal weights share equally",
+			weights: map[string]int{"Alice": 1, "Bob": 1, "Carol": 1},
+			pops:    30,
+			want:    map[string]int{"Alice": 10, "Bob": 10, "Carol": 10},
+		},
+		{
+			name:    "three to one",
+			weights: map[string]int{"Alice": 3, "Bob": 1},
+			pops:    40,
+			want:    map[string]int{"Alice": 30, "Bob": 10},
+		},
+		{
+			name:    "missing weight counts as one",
+			weights: map[string]int{"Alice": 2, "Bob": 0},
+			pops:    30,
+			want:    map[string]int{"Alice": 20, "Bob": 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := scheduler.NewWeightedFairScheduler()
+			for owner, weight := range tc.weights {
+				push(s, owner, weight, entities.JobPriorityNormal, 1000)
+			}
+
+			assert.Equal(t, tc.want, count(popOwners(t, s, tc.pops)))
+		})
+	}
+}
+
+func TestWeightedFairScheduler_EveryPrefixIsFair(t *testing.T) {
+	t.Parallel()
+
+	weights := map[string]int{"Alice": 5, "Bob": 2, "Carol": 1}
+	total := 8
+
+	s := scheduler.NewWeightedFairScheduler()
+	for owner, weight := range weights {
+		push(s, owner, weight, entities.JobPriorityNormal, 1000)
+	}
+
+	served := map[string]int{}
+
+	for i := 1; i <= 200; i++ {
+		item, ok := s.Pop()
+		assert.True(t, ok)
+
+		served[item.OwnerID]++
+
+		for owner, weight := range weights {
+			ideal := float64(i*weight) / float64(total)
+			assert.InDelta(t, ideal, served[owner], 1.0, "owner %s after %d pops", owner, i)
+		}
+	}
+}
+
+func TestWeightedFairScheduler_PriorityBoostsShareWithoutStarvation(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.NewWeightedFairScheduler()
+
+	push(s, "Alice", 1, entities.JobPriorityHigh, 1000)
+	push(s, "Bob", 1, entities.JobPriorityLow, 1000)
+
+	served := count(popOwners(t, s, 50))
+
+	assert.Equal(t, 40, served["Alice"], "high priority weighs four times low priority")
+	assert.Equal(t, 10, served["Bob"], "low priority is still served")
+}
+
+func TestWeightedFairScheduler_Remove(t *testing.T) {
+	t.Parallel()
+
+	s := scheduler.NewWeightedFairScheduler()
+
+	alice := push(s, "Alice", 1, entities.JobPriorityNormal, 2)
+	bob := push(s, "Bob", 1, entities.JobPriorityNormal, 1)
+
+	assert.True(t, s.Remove(bob[0]))
+	assert.False(t, s.Remove(bob[0]))
+	assert.True(t, s.Remove(alice[0]))
+	assert.Equal(t, 1, s.Len())
+
+	item, ok := s.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, alice[1], item.ID)
+
+	_, ok = s.Pop()
+	assert.False(t, ok)
+}
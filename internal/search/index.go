@@ -0,0 +1,467 @@
+// Package search is an embedded full-text index over the content and metadata of patent valuation jobs, with boolean
+// and phrase queries and highlighted snippets.
+//
+// Every owner has an index of their own, so that neither results nor term statistics cross tenant boundaries.
+package search
+
+import (
+	"html"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+const (
+	FieldTitle   = "title"
+	FieldNumber  = "number"
+	FieldClass   = "class"
+	FieldLabel   = "label"
+	FieldTag     = "tag"
+	FieldNote    = "note"
+	FieldFile    = "file"
+	FieldContent = "content"
+
+	// snippetContext is the number of bytes of content shown around a match
+	snippetContext = 60
+	// maxContentSnippets limits the snippets of the content, other fields are short and shown whole
+	maxContentSnippets = 3
+)
+
+// Fields lists the searchable fields in the order their snippets are returned.
+//
+//nolint:gochecknoglobals // lookup table
+var Fields = []string{FieldTitle, FieldNumber, FieldClass, FieldLabel, FieldTag, FieldNote, FieldFile, FieldContent}
+
+// Index is safe for concurrent use.
+type Index interface {
+	// Add indexes the job, adding a job again replaces it, e.g. after its metadata changed
+	Add(job entities.EvaluationJob)
+	// Remove drops the job from the index, unknown jobs are ignored
+	Remove(ownerID string, id uuid.UUID)
+	// Search returns the owner's jobs matching the query, best first
+	Search(ownerID string, query Query) []entities.SearchHit
+}
+
+type index struct {
+	mutex  sync.RWMutex
+	owners map[string]*ownerIndex
+}
+
+type ownerIndex struct {
+	documents map[uuid.UUID]*document
+	// postings lists the documents containing a term in any field
+	postings map[string]map[uuid.UUID]struct{}
+}
+
+type document struct {
+	createdAt time.Time
+	texts     map[string]string
+	positions map[string][]position
+}
+
+func NewIndex() Index {
+	return &index{
+		owners: map[string]*ownerIndex{},
+	}
+}
+
+func (i *index) Add(job entities.EvaluationJob) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	owner, ok := i.owners[job.OwnerID]
+	if !ok {
+		owner = &ownerIndex{
+			documents: map[uuid.UUID]*document{},
+			postings:  map[string]map[uuid.UUID]struct{}{},
+		}
+		i.owners[job.OwnerID] = owner
+	}
+
+	owner.remove(job.ID)
+
+	doc := &document{createdAt: job.CreatedAt, texts: texts(job), positions: map[string][]position{}}
+	owner.documents[job.ID] = doc
+
+	for field, text := range doc.texts {
+		doc.positions[field] = tokenize(text)
+
+		for _, p := range doc.positions[field] {
+			if owner.postings[p.term] == nil {
+				owner.postings[p.term] = map[uuid.UUID]struct{}{}
+			}
+
+			owner.postings[p.term][job.ID] = struct{}{}
+		}
+	}
+}
+
+func (i *index) Remove(ownerID string, id uuid.UUID) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	owner, ok := i.owners[ownerID]
+	if !ok {
+		return
+	}
+
+	owner.remove(id)
+
+	if len(owner.documents) == 0 {
+		delete(i.owners, ownerID)
+	}
+}
+
+// remove drops the document and its postings, terms no longer found anywhere are dropped as well.
+func (o *ownerIndex) remove(id uuid.UUID) {
+	previous, ok := o.documents[id]
+	if !ok {
+		return
+	}
+
+	for _, positions := range previous.positions {
+		for _, p := range positions {
+			delete(o.postings[p.term], id)
+
+			if len(o.postings[p.term]) == 0 {
+				delete(o.postings, p.term)
+			}
+		}
+	}
+
+	delete(o.documents, id)
+}
+
+// texts returns the searchable texts of the job by field.
+func texts(job entities.EvaluationJob) map[string]string {
+	var title, numbers, classes []string
+
+	if job.Patent != nil {
+		title = append(title, job.Patent.Title)
+		numbers = append(numbers, job.Patent.PublicationNumber)
+		classes = append(append(classes, job.Patent.CPCClasses...), job.Patent.IPCClasses...)
+	}
+
+	numbers = append(numbers, job.PatentKey)
+	classes = append(classes, job.TechnicalField)
+
+	labels := make([]string, 0, len(job.Metadata.Labels))
+	for key, value := range job.Metadata.Labels {
+		labels = append(labels, strings.TrimSuffix(key+":"+value, ":"))
+	}
+
+	slices.Sort(labels)
+
+	result := map[string]string{
+		FieldTitle:   joinNonEmpty(title, ""),
+		FieldNumber:  joinNonEmpty(numbers, ", "),
+		FieldClass:   joinNonEmpty(classes, ", "),
+		FieldLabel:   joinNonEmpty(labels, ", "),
+		FieldTag:     joinNonEmpty(job.Metadata.Tags, ", "),
+		FieldNote:    job.Metadata.Note,
+		FieldContent: job.PatentContent,
+	}
+
+	if job.Document != nil {
+		result[FieldFile] = job.Document.FileName
+	}
+
+	return result
+}
+
+func joinNonEmpty(values []string, separator string) string {
+	result := []string{}
+
+	for _, value := range values {
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+
+	return strings.Join(result, separator)
+}
+
+func (i *index) Search(ownerID string, query Query) []entities.SearchHit {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	owner, ok := i.owners[ownerID]
+	if !ok {
+		return []entities.SearchHit{}
+	}
+
+	scores := owner.evaluate(query)
+	result := make([]entities.SearchHit, 0, len(scores))
+
+	for id, score := range scores {
+		result = append(result, entities.SearchHit{
+			JobID:    id,
+			Score:    math.Round(score*100) / 100, //nolint:gomnd // hundredths
+			Snippets: owner.documents[id].snippets(query.positive()),
+		})
+	}
+
+	// equally relevant jobs are listed newest first
+	slices.SortFunc(result, func(a, b entities.SearchHit) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		return owner.documents[b.JobID].createdAt.Compare(owner.documents[a.JobID].createdAt)
+	})
+
+	return result
+}
+
+// evaluate returns the matching documents with their relevance.
+func (o *ownerIndex) evaluate(query Query) map[uuid.UUID]float64 {
+	switch q := query.(type) {
+	case *match:
+		return o.evaluateMatch(q)
+	case *and:
+		left, right := o.evaluate(q.left), o.evaluate(q.right)
+		result := map[uuid.UUID]float64{}
+
+		for id, score := range left {
+			if other, ok := right[id]; ok {
+				result[id] = score + other
+			}
+		}
+
+		return result
+	case *or:
+		result := o.evaluate(q.left)
+		for id, score := range o.evaluate(q.right) {
+			result[id] += score
+		}
+
+		return result
+	case *not:
+		excluded := o.evaluate(q.query)
+		result := map[uuid.UUID]float64{}
+
+		for id := range o.documents {
+			if _, ok := excluded[id]; !ok {
+				result[id] = 0
+			}
+		}
+
+		return result
+	}
+
+	return map[uuid.UUID]float64{}
+}
+
+// evaluateMatch weighs the occurrences of the phrase with the inverse document frequency of its rarest term.
+func (o *ownerIndex) evaluateMatch(m *match) map[uuid.UUID]float64 {
+	result := map[uuid.UUID]float64{}
+	inverseDocumentFrequency := math.Inf(1)
+
+	for _, term := range m.terms {
+		frequency := float64(len(o.postings[term]))
+		if frequency == 0 {
+			return result
+		}
+
+		inverseDocumentFrequency = math.Min(inverseDocumentFrequency, math.Log(1+float64(len(o.documents))/frequency))
+	}
+
+	for id := range o.postings[m.terms[0]] {
+		occurrences := len(o.documents[id].find(m))
+		if occurrences > 0 {
+			result[id] = (1 + math.Log(float64(occurrences))) * inverseDocumentFrequency
+		}
+	}
+
+	return result
+}
+
+// span is a match in a field, as byte offsets of its text.
+type span struct {
+	field      string
+	start, end int
+}
+
+// find returns the occurrences of the phrase in the fields it is restricted to.
+func (d *document) find(m *match) []span {
+	var result []span
+
+	for _, field := range Fields {
+		if m.field != "" && m.field != field {
+			continue
+		}
+
+		positions := d.positions[field]
+
+		for i := 0; i+len(m.terms) <= len(positions); i++ {
+			found := true
+
+			for j, term := range m.terms {
+				if positions[i+j].term != term {
+					found = false
+
+					break
+				}
+			}
+
+			if found {
+				result = append(result, span{
+					field: field, start: positions[i].start, end: positions[i+len(m.terms)-1].end,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// snippets shows the matches of the given phrases in their fields, HTML escaped and wrapped in <mark> elements.
+func (d *document) snippets(matches []*match) []entities.Snippet {
+	spans := map[string][]span{}
+
+	for _, m := range matches {
+		for _, s := range d.find(m) {
+			spans[s.field] = append(spans[s.field], s)
+		}
+	}
+
+	result := []entities.Snippet{}
+
+	for _, field := range Fields {
+		fieldSpans := merge(spans[field])
+		if len(fieldSpans) == 0 {
+			continue
+		}
+
+		text := d.texts[field]
+		if field != FieldContent {
+			result = append(result, entities.Snippet{Field: field, Text: highlight(text, 0, len(text), fieldSpans)})
+
+			continue
+		}
+
+		for _, window := range windows(text, fieldSpans) {
+			result = append(result, entities.Snippet{
+				Field: field, Text: highlight(text, window.start, window.end, fieldSpans),
+			})
+		}
+	}
+
+	return result
+}
+
+// merge orders the spans and joins overlapping ones.
+func merge(spans []span) []span {
+	slices.SortFunc(spans, func(a, b span) int { return a.start - b.start })
+
+	var result []span
+
+	for _, s := range spans {
+		if last := len(result) - 1; last >= 0 && s.start <= result[last].end {
+			result[last].end = max(result[last].end, s.end)
+
+			continue
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// windows returns up to maxContentSnippets excerpts of text around the spans, cut at spaces.
+func windows(text string, spans []span) []span {
+	var result []span
+
+	for _, s := range spans {
+		if last := len(result) - 1; last >= 0 && s.start < result[last].end {
+			result[last].end = max(result[last].end, cutAfter(text, s.end+snippetContext))
+
+			continue
+		}
+
+		if len(result) == maxContentSnippets {
+			break
+		}
+
+		result = append(result, span{start: cutBefore(text, s.start-snippetContext), end: cutAfter(text, s.end+snippetContext)})
+	}
+
+	return result
+}
+
+func cutBefore(text string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+
+	if space := strings.IndexAny(text[offset:], " \n\t"); space >= 0 {
+		return offset + space + 1
+	}
+
+	return runeStart(text, offset)
+}
+
+func cutAfter(text string, offset int) int {
+	if offset >= len(text) {
+		return len(text)
+	}
+
+	if space := strings.LastIndexAny(text[:offset], " \n\t"); space >= 0 {
+		return space
+	}
+
+	return runeStart(text, offset)
+}
+
+// runeStart moves offset back to the start of the rune it points into.
+func runeStart(text string, offset int) int {
+	for offset > 0 && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+
+	return offset
+}
+
+// highlight returns text[start:end] with the spans marked, with an ellipsis where the text was cut.
+func highlight(text string, start int, end int, spans []span) string {
+	var builder strings.Builder
+
+	if start > 0 {
+		builder.WriteString("…")
+	}
+
+	current := start
+
+	for _, s := range spans {
+		if s.end <= start || s.start >= end {
+			continue
+		}
+
+		builder.WriteString(html.EscapeString(text[current:max(current, s.start)]))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(text[max(current, s.start):min(end, s.end)]))
+		builder.WriteString("</mark>")
+
+		current = min(end, s.end)
+	}
+
+	builder.WriteString(html.EscapeString(text[current:end]))
+
+	if end < len(text) {
+		builder.WriteString("…")
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
@@ -0,0 +1,298 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Query is a parsed search query.
+type Query interface {
+	// positive returns the terms and phrases the query looks for, i.e. all that are not negated
+	positive() []*match
+}
+
+// match finds a phrase of one or more consecutive terms, in any field if field is empty.
+type match struct {
+	field string
+	terms []string
+}
+
+type and struct {
+	left, right Query
+}
+
+type or struct {
+	left, right Query
+}
+
+type not struct {
+	query Query
+}
+
+func (m *match) positive() []*match { return []*match{m} }
+func (a *and) positive() []*match   { return append(a.left.positive(), a.right.positive()...) }
+func (o *or) positive() []*match    { return append(o.left.positive(), o.right.positive()...) }
+func (n *not) positive() []*match   { return nil }
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	field string
+	text  string
+}
+
+// Parse parses queries of terms and "quoted phrases", which all have to match unless combined with OR; NOT or a
+// leading minus excludes, parentheses group and a field prefix like title: restricts a term or phrase to a field.
+// Returns an ErrInvalidQuery error for malformed queries.
+func Parse(query string) (Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.position < len(p.tokens) {
+		return nil, errors.Wrap(ErrInvalidQuery, "unbalanced parenthesis")
+	}
+
+	return result, nil
+}
+
+func lex(query string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, token{kind: tokenNot})
+			i++
+		case r == '"':
+			phrase, next, err := lexPhrase(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenPhrase, text: phrase})
+			i = next
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+				i++
+			}
+
+			word := string(runes[start:i])
+
+			field, rest, qualified := strings.Cut(word, ":")
+			if qualified && !slices.Contains(Fields, field) {
+				return nil, errors.Wrapf(ErrInvalidQuery, "unknown field %q, use one of %s",
+					field, strings.Join(Fields, ", "))
+			}
+
+			switch {
+			case qualified && rest == "" && i < len(runes) && runes[i] == '"':
+				phrase, next, err := lexPhrase(runes, i)
+				if err != nil {
+					return nil, err
+				}
+
+				tokens = append(tokens, token{kind: tokenPhrase, field: field, text: phrase})
+				i = next
+			case qualified:
+				tokens = append(tokens, token{kind: tokenWord, field: field, text: rest})
+			case word == "AND":
+				tokens = append(tokens, token{kind: tokenAnd})
+			case word == "OR":
+				tokens = append(tokens, token{kind: tokenOr})
+			case word == "NOT":
+				tokens = append(tokens, token{kind: tokenNot})
+			default:
+				tokens = append(tokens, token{kind: tokenWord, text: word})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// lexPhrase reads the phrase quoted at runes[start] and returns it with the position after its closing quote.
+func lexPhrase(runes []rune, start int) (string, int, error) {
+	end := slices.Index(runes[start+1:], '"')
+	if end < 0 {
+		return "", 0, errors.Wrap(ErrInvalidQuery, "unterminated phrase")
+	}
+
+	return string(runes[start+1 : start+1+end]), start + end + 2, nil //nolint:gomnd // both quotes
+}
+
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.position >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.position], true
+}
+
+func (p *parser) parseOr() (Query, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for next, ok := p.peek(); ok && next.kind == tokenOr; next, ok = p.peek() {
+		p.position++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &or{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Query, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for next, ok := p.peek(); ok && next.kind != tokenOr && next.kind != tokenClose; next, ok = p.peek() {
+		if next.kind == tokenAnd {
+			p.position++
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &and{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Query, error) {
+	next, ok := p.peek()
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidQuery, "term expected")
+	}
+
+	p.position++
+
+	switch next.kind {
+	case tokenNot:
+		query, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &not{query: query}, nil
+	case tokenOpen:
+		query, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing, ok := p.peek(); !ok || closing.kind != tokenClose {
+			return nil, errors.Wrap(ErrInvalidQuery, "unbalanced parenthesis")
+		}
+
+		p.position++
+
+		return query, nil
+	case tokenWord, tokenPhrase:
+		terms := Terms(next.text)
+		if len(terms) == 0 {
+			return nil, errors.Wrapf(ErrInvalidQuery, "%q contains no searchable term", next.text)
+		}
+
+		return &match{field: next.field, terms: terms}, nil
+	case tokenAnd, tokenOr, tokenClose:
+	}
+
+	return nil, errors.Wrap(ErrInvalidQuery, "term expected")
+}
+
+// Terms splits text into lower case words of letters and digits.
+func Terms(text string) []string {
+	terms := []string{}
+
+	for _, t := range tokenize(text) {
+		terms = append(terms, t.term)
+	}
+
+	return terms
+}
+
+// position is a term of a field with its byte offsets.
+type position struct {
+	term       string
+	start, end int
+}
+
+func tokenize(text string) []position {
+	var (
+		result []position
+		start  = -1
+	)
+
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			result = append(result, position{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		result = append(result, position{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+
+	return result
+}
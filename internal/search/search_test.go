@@ -0,0 +1,291 @@
+//nolint:funlen // Test functions are long, due to test cases
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/search"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "terms", query: "digital signature"},
+		{name: "boolean operators and grouping", query: `(rsa OR "elliptic curve") AND NOT battery -lithium`},
+		{name: "field qualifiers", query: `title:signature number:EP1234567 class:"H04L 9"`},
+		{name: "empty query", query: "  ", wantErr: true},
+		{name: "unterminated phrase", query: `"digital signature`, wantErr: true},
+		{name: "unbalanced parenthesis", query: "(rsa OR ecdsa", wantErr: true},
+		{name: "closing parenthesis without opening", query: "rsa)", wantErr: true},
+		{name: "dangling operator", query: "rsa OR", wantErr: true},
+		{name: "unknown field", query: "inventor:smith", wantErr: true},
+		{name: "no searchable term", query: `"--"`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := search.Parse(tc.query)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, search.ErrInvalidQuery)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	rsa := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Alice", CreatedAt: now,
+		PatentContent: "A method for verifying a digital signature using RSA with a public key.",
+		Patent:        &entities.Patent{Title: "Signature verification", PublicationNumber: "EP1234567A1"},
+		PatentKey:     "EP1234567",
+	}
+	ecdsa := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Alice", CreatedAt: now.Add(time.Minute),
+		PatentContent:  "A method for verifying a digital signature using an elliptic curve.",
+		TechnicalField: "H04L",
+	}
+	battery := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Alice", CreatedAt: now.Add(2 * time.Minute),
+		PatentContent: "A lithium battery with a signature <cell> layout.",
+		Document:      &entities.Document{FileName: "battery.pdf"},
+	}
+	bobs := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Bob", CreatedAt: now,
+		PatentContent: "A method for verifying a digital signature using RSA with a public key.",
+	}
+
+	index := search.NewIndex()
+	for _, job := range []entities.EvaluationJob{rsa, ecdsa, battery, bobs} {
+		index.Add(job)
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		want         []uuid.UUID
+		wantSnippets []entities.Snippet
+	}{
+		{
+			name:  "terms are combined with AND, other owners' jobs are not found",
+			query: "verifying signature",
+			want:  []uuid.UUID{rsa.ID, ecdsa.ID},
+		},
+		{
+			name:  "phrase",
+			query: `"elliptic curve"`,
+			want:  []uuid.UUID{ecdsa.ID},
+			wantSnippets: []entities.Snippet{{
+				Field: search.FieldContent,
+				Text:  "A method for verifying a digital signature using an <mark>elliptic curve</mark>.",
+			}},
+		},
+		{
+			name:  "OR and NOT",
+			query: "(rsa OR lithium) -battery",
+			want:  []uuid.UUID{rsa.ID},
+		},
+		{
+			name:  "leading negation matches all other jobs",
+			query: "NOT rsa",
+			want:  []uuid.UUID{battery.ID, ecdsa.ID},
+		},
+		{
+			name:  "fields",
+			query: "title:signature OR number:EP1234567 OR class:h04l OR file:battery",
+			want:  []uuid.UUID{rsa.ID, battery.ID, ecdsa.ID},
+		},
+		{
+			name:  "snippets of short fields are whole, content is escaped",
+			query: "signature battery",
+			want:  []uuid.UUID{battery.ID},
+			wantSnippets: []entities.Snippet{
+				{Field: search.FieldFile, Text: "<mark>battery</mark>.pdf"},
+				{Field: search.FieldContent, Text: "A lithium <mark>battery</mark> with a <mark>signature</mark> &lt;cell&gt; layout."},
+			},
+		},
+		{
+			name:  "no match",
+			query: "quantum",
+			want:  []uuid.UUID{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := search.Parse(tc.query)
+			assert.NoError(t, err)
+
+			hits := index.Search("Alice", query)
+
+			ids := make([]uuid.UUID, 0, len(hits))
+			for _, hit := range hits {
+				ids = append(ids, hit.JobID)
+			}
+
+			assert.Equal(t, tc.want, ids)
+
+			if tc.wantSnippets != nil {
+				assert.Equal(t, tc.wantSnippets, hits[0].Snippets)
+			}
+		})
+	}
+}
+
+func TestIndex_ContentSnippetsAreCut(t *testing.T) {
+	t.Parallel()
+
+	job := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Alice",
+		PatentContent: "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut " +
+			"labore et dolore magna aliqua. A digital signature is verified. Ut enim ad minim veniam, quis nostrud " +
+			"exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat.",
+	}
+
+	index := search.NewIndex()
+	index.Add(job)
+
+	query, err := search.Parse("signature")
+	assert.NoError(t, err)
+
+	hits := index.Search("Alice", query)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, []entities.Snippet{{
+		Field: search.FieldContent,
+		Text: "…incididunt ut labore et dolore magna aliqua. A digital <mark>signature</mark> is verified. Ut " +
+			"enim ad minim veniam, quis nostrud…",
+	}}, hits[0].Snippets)
+}
+
+func TestIndex_Metadata(t *testing.T) {
+	t.Parallel()
+
+	job := entities.EvaluationJob{
+		ID: uuid.New(), OwnerID: "Alice",
+		PatentContent: "A method for verifying a digital signature.",
+		Metadata: entities.JobMetadata{
+			Labels: map[string]string{"team": "crypto", "archived": ""},
+			Tags:   []string{"licensing"},
+			Note:   "Ask counsel about the renewal fees",
+		},
+	}
+	other := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", PatentContent: "A lithium battery."}
+
+	updated := job
+	updated.Metadata = entities.JobMetadata{Labels: map[string]string{"team": "energy"}}
+
+	testCases := []struct {
+		name  string
+		jobs  []entities.EvaluationJob
+		query string
+		want  []uuid.UUID
+		// wantSnippets are those of the first hit
+		wantSnippets []entities.Snippet
+	}{
+		{
+			name:  "labels, tags and notes",
+			jobs:  []entities.EvaluationJob{job, other},
+			query: "label:crypto tag:licensing note:renewal",
+			want:  []uuid.UUID{job.ID},
+			wantSnippets: []entities.Snippet{
+				{Field: search.FieldLabel, Text: "archived, team:<mark>crypto</mark>"},
+				{Field: search.FieldTag, Text: "<mark>licensing</mark>"},
+				{Field: search.FieldNote, Text: "Ask counsel about the <mark>renewal</mark> fees"},
+			},
+		},
+		{
+			name:  "metadata is found without field",
+			jobs:  []entities.EvaluationJob{job, other},
+			query: "counsel",
+			want:  []uuid.UUID{job.ID},
+		},
+		{
+			name:  "adding a job again replaces its metadata",
+			jobs:  []entities.EvaluationJob{job, other, updated},
+			query: "label:crypto OR note:counsel",
+			want:  []uuid.UUID{},
+		},
+		{
+			name:  "updated metadata is found",
+			jobs:  []entities.EvaluationJob{job, other, updated},
+			query: `label:"team:energy"`,
+			want:  []uuid.UUID{job.ID},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			index := search.NewIndex()
+			for _, job := range tc.jobs {
+				index.Add(job)
+			}
+
+			query, err := search.Parse(tc.query)
+			assert.NoError(t, err)
+
+			hits := index.Search("Alice", query)
+
+			ids := make([]uuid.UUID, 0, len(hits))
+			for _, hit := range hits {
+				ids = append(ids, hit.JobID)
+			}
+
+			assert.Equal(t, tc.want, ids)
+
+			if tc.wantSnippets != nil {
+				assert.Equal(t, tc.wantSnippets, hits[0].Snippets)
+			}
+		})
+	}
+}
+
+func TestIndex_Remove(t *testing.T) {
+	t.Parallel()
+
+	signature := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", PatentContent: "A digital signature."}
+	battery := entities.EvaluationJob{ID: uuid.New(), OwnerID: "Alice", PatentContent: "A lithium battery."}
+
+	index := search.NewIndex()
+	index.Add(signature)
+	index.Add(battery)
+
+	index.Remove("Alice", signature.ID)
+	// unknown owners and jobs are ignored
+	index.Remove("Bob", battery.ID)
+	index.Remove("Alice", uuid.New())
+
+	for query, want := range map[string]int{"signature": 0, "NOT signature": 1, "battery": 1} {
+		parsed, err := search.Parse(query)
+		assert.NoError(t, err)
+		assert.Len(t, index.Search("Alice", parsed), want, query)
+	}
+
+	index.Remove("Alice", battery.ID)
+
+	parsed, err := search.Parse("NOT signature")
+	assert.NoError(t, err)
+	assert.Empty(t, index.Search("Alice", parsed))
+}
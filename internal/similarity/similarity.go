@@ -0,0 +1,194 @@
+// Package similarity finds patents similar to each other by the cosine similarity of their TF-IDF weighted terms.
+//
+// Every owner has an index of their own, so that neither matches nor term statistics cross tenant boundaries.
+package similarity
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+const minTermLength = 3
+
+//nolint:gochecknoglobals // lookup table
+var stopWords = map[string]bool{
+	"about": true, "according": true, "all": true, "and": true, "any": true, "are": true, "based": true,
+	"being": true, "between": true, "both": true, "but": true, "can": true, "claim": true, "claims": true,
+	"comprising": true, "each": true, "for": true, "from": true, "further": true, "has": true, "have": true,
+	"into": true, "its": true, "least": true, "may": true, "not": true, "one": true, "other": true, "said": true,
+	"such": true, "than": true, "that": true, "the": true, "their": true, "then": true, "there": true, "thereof": true,
+	"these": true, "this": true, "was": true, "were": true, "when": true, "where": true, "whereby": true,
+	"wherein": true, "which": true, "will": true, "with": true, "within": true, "would": true,
+}
+
+// Index is safe for concurrent use.
+type Index interface {
+	// Add indexes the content of the job, adding a job again replaces its content
+	Add(job entities.EvaluationJob)
+	// Similar returns up to limit other jobs of the owner sharing terms with the given job, most similar first;
+	// returns an entities.ErrJobNotIndexed error if the job is not indexed for the owner
+	Similar(ownerID string, id uuid.UUID, limit int) ([]entities.SimilarityMatch, error)
+}
+
+type index struct {
+	mutex  sync.RWMutex
+	owners map[string]*ownerIndex
+}
+
+type ownerIndex struct {
+	// terms holds the term frequencies per job
+	terms map[uuid.UUID]map[string]int
+	// postings lists the jobs containing a term
+	postings map[string]map[uuid.UUID]struct{}
+}
+
+func NewIndex() Index {
+	return &index{
+		owners: map[string]*ownerIndex{},
+	}
+}
+
+func (i *index) Add(job entities.EvaluationJob) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	owner, ok := i.owners[job.OwnerID]
+	if !ok {
+		owner = &ownerIndex{
+			terms:    map[uuid.UUID]map[string]int{},
+			postings: map[string]map[uuid.UUID]struct{}{},
+		}
+		i.owners[job.OwnerID] = owner
+	}
+
+	for term := range owner.terms[job.ID] {
+		delete(owner.postings[term], job.ID)
+	}
+
+	frequencies := Terms(job.PatentContent)
+	owner.terms[job.ID] = frequencies
+
+	for term := range frequencies {
+		if owner.postings[term] == nil {
+			owner.postings[term] = map[uuid.UUID]struct{}{}
+		}
+
+		owner.postings[term][job.ID] = struct{}{}
+	}
+}
+
+func (i *index) Similar(ownerID string, id uuid.UUID, limit int) ([]entities.SimilarityMatch, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	owner, ok := i.owners[ownerID]
+	if !ok || owner.terms[id] == nil {
+		return nil, errors.Wrapf(entities.ErrJobNotIndexed, "job %s", id.String())
+	}
+
+	query := owner.vector(id)
+	dotProducts := map[uuid.UUID]float64{}
+
+	for term, weight := range query {
+		for candidate := range owner.postings[term] {
+			if candidate != id {
+				dotProducts[candidate] += weight * owner.weight(candidate, term)
+			}
+		}
+	}
+
+	queryNorm := norm(query)
+	result := make([]entities.SimilarityMatch, 0, len(dotProducts))
+
+	for candidate, dotProduct := range dotProducts {
+		if dotProduct <= 0 {
+			continue
+		}
+
+		result = append(result, entities.SimilarityMatch{
+			JobID: candidate,
+			Score: math.Min(1, dotProduct/(queryNorm*norm(owner.vector(candidate)))),
+		})
+	}
+
+	slices.SortFunc(result, func(a, b entities.SimilarityMatch) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.JobID.String(), b.JobID.String())
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// vector returns the TF-IDF weights of the job's terms.
+func (o *ownerIndex) vector(id uuid.UUID) map[string]float64 {
+	result := make(map[string]float64, len(o.terms[id]))
+
+	for term := range o.terms[id] {
+		result[term] = o.weight(id, term)
+	}
+
+	return result
+}
+
+// weight dampens the term frequency logarithmically and weighs it with the smoothed inverse document frequency, so
+// that terms found in every patent of the owner still count a little.
+func (o *ownerIndex) weight(id uuid.UUID, term string) float64 {
+	frequency := o.terms[id][term]
+	if frequency == 0 {
+		return 0
+	}
+
+	inverseDocumentFrequency := math.Log(1 + float64(len(o.terms))/float64(len(o.postings[term])))
+
+	return (1 + math.Log(float64(frequency))) * inverseDocumentFrequency
+}
+
+func norm(vector map[string]float64) float64 {
+	sum := 0.0
+	for _, weight := range vector {
+		sum += weight * weight
+	}
+
+	return math.Sqrt(sum)
+}
+
+// Terms counts the words of text, normalized to lower case, without numbers, short words and stop words common
+// to patent language.
+func Terms(text string) map[string]int {
+	result := map[string]int{}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < minTermLength || stopWords[word] || isNumber(word) {
+			continue
+		}
+
+		result[word]++
+	}
+
+	return result
+}
+
+func isNumber(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
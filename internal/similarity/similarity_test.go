@@ -0,0 +1,127 @@
+//nolint:funlen // Test functions are long, due to test cases
+package similarity_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/similarity"
+)
+
+func TestTerms(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, map[string]int{"method": 2, "verifying": 1, "signature": 1, "rsa": 1, "used": 1},
+		similarity.Terms("1. A method comprising verifying a Signature.\n2. The method of claim 1, wherein RSA is used."))
+}
+
+func TestIndex_Similar(t *testing.T) {
+	t.Parallel()
+
+	signature := uuid.New()
+	signatureAmended := uuid.New()
+	battery := uuid.New()
+	bobsSignature := uuid.New()
+
+	index := similarity.NewIndex()
+	index.Add(entities.EvaluationJob{ID: signature, OwnerID: "Alice",
+		PatentContent: "A method for verifying a digital signature with a public key certificate."})
+	index.Add(entities.EvaluationJob{ID: signatureAmended, OwnerID: "Alice",
+		PatentContent: "A method for verifying a digital signature with an elliptic curve public key."})
+	index.Add(entities.EvaluationJob{ID: battery, OwnerID: "Alice",
+		PatentContent: "A battery cell with a lithium anode and a method for charging it."})
+	index.Add(entities.EvaluationJob{ID: bobsSignature, OwnerID: "Bob",
+		PatentContent: "A method for verifying a digital signature with a public key certificate."})
+
+	testCases := []struct {
+		name    string
+		ownerID string
+		id      uuid.UUID
+		limit   int
+		want    []uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "most similar job first, other owners' jobs are not found",
+			ownerID: "Alice",
+			id:      signature,
+			limit:   10,
+			want:    []uuid.UUID{signatureAmended, battery},
+		},
+		{
+			name:    "limit",
+			ownerID: "Alice",
+			id:      signature,
+			limit:   1,
+			want:    []uuid.UUID{signatureAmended},
+		},
+		{
+			name:    "only job of the owner has no similar jobs",
+			ownerID: "Bob",
+			id:      bobsSignature,
+			limit:   10,
+			want:    []uuid.UUID{},
+		},
+		{
+			name:    "job of another owner",
+			ownerID: "Bob",
+			id:      signature,
+			limit:   10,
+			wantErr: entities.ErrJobNotIndexed,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			matches, err := index.Similar(tc.ownerID, tc.id, tc.limit)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			ids := make([]uuid.UUID, 0, len(matches))
+			for i, match := range matches {
+				ids = append(ids, match.JobID)
+
+				assert.Greater(t, match.Score, 0.0)
+				assert.LessOrEqual(t, match.Score, 1.0)
+
+				if i > 0 {
+					assert.LessOrEqual(t, match.Score, matches[i-1].Score)
+				}
+			}
+
+			assert.Equal(t, tc.want, ids)
+		})
+	}
+}
+
+func TestIndex_AddReplacesContent(t *testing.T) {
+	t.Parallel()
+
+	first := uuid.New()
+	second := uuid.New()
+
+	index := similarity.NewIndex()
+	index.Add(entities.EvaluationJob{ID: first, OwnerID: "Alice", PatentContent: "digital signature verification"})
+	index.Add(entities.EvaluationJob{ID: second, OwnerID: "Alice", PatentContent: "lithium battery anode"})
+
+	matches, err := index.Similar("Alice", first, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+
+	index.Add(entities.EvaluationJob{ID: first, OwnerID: "Alice", PatentContent: "lithium battery cathode"})
+
+	matches, err = index.Similar("Alice", first, 10)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+}
@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) CreateBatch(batch entities.Batch) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	batch.Items = slices.Clone(batch.Items)
+	s.batches[batch.ID] = batch
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetBatchByID(id uuid.UUID) (entities.Batch, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	batch, ok := s.batches[id]
+	if !ok {
+		return entities.Batch{}, patents.ErrBatchNotFound
+	}
+
+	batch.Items = slices.Clone(batch.Items)
+
+	return batch, nil
+}
@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/worker"
+)
+
+var ErrSimulatedEngineFailure = errors.New("simulated transient engine failure")
+
+const (
+	engineName            = "simulation"
+	engineVersion         = "1.0.0"
+	weightedEngineVersion = "2.0.0"
+	simulatedValue        = 42000
+	simulatedCurrency     = "EUR"
+)
+
+type engine struct {
+	cfg     *config.SimulationConfig
+	version string
+	value   func([]entities.ValuationFactor) int
+}
+
+// NewEngine returns an engine that values every patent at EUR 42,000 after the configured duration, explained by factors
+// derived from the patent, failing with the configured rate to exercise the retry handling.
+func NewEngine(cfg *config.SimulationConfig) worker.Engine {
+	return &engine{
+		cfg:     cfg,
+		version: engineVersion,
+		value:   fixedValue,
+	}
+}
+
+// NewWeightedEngine returns the next version of the engine from NewEngine, which derives the value from the weighted
+// factor scores instead, so that re-valuations yield different values.
+func NewWeightedEngine(cfg *config.SimulationConfig) worker.Engine {
+	return &engine{
+		cfg:     cfg,
+		version: weightedEngineVersion,
+		value:   weightedValue,
+	}
+}
+
+func (e *engine) Name() string {
+	return engineName
+}
+
+func (e *engine) Version() string {
+	return e.version
+}
+
+func (e *engine) Evaluate(ctx context.Context, job entities.EvaluationJob) (entities.Valuation, error) {
+	select {
+	case <-ctx.Done():
+		return entities.Valuation{}, errors.Wrap(ctx.Err(), "evaluation aborted")
+	case <-time.After(e.cfg.EvaluationDuration):
+	}
+
+	//nolint:gosec // no cryptographic use
+	if rand.Float64() < e.cfg.FailureRate {
+		return entities.Valuation{}, ErrSimulatedEngineFailure
+	}
+
+	jobFactors := factors(job)
+
+	return valuation(e.value(jobFactors), jobFactors), nil
+}
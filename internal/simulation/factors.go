@@ -0,0 +1,111 @@
+package simulation
+
+import (
+	"math"
+	"strings"
+
+	"github.com/MyChaOS87/patAi/internal/claims"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/patenttext"
+)
+
+const (
+	maxScore = 100
+	// confidenceLevel and confidenceMargin describe the simulated interval of ±10 % around the value
+	confidenceLevel  = 0.9
+	confidenceMargin = 0.1
+
+	// independentClaimScore is the score per independent claim for the claim breadth
+	independentClaimScore = 25
+	// broadClaimWords is the length of an independent claim up to which its scope scores fully
+	broadClaimWords = 40
+	// citationScore is the score per cited reference
+	citationScore = 10
+	// wordsPerDisclosurePoint is the number of words of content per disclosure score point
+	wordsPerDisclosurePoint = 50
+	// valuePerScorePoint converts the weighted factor score into the value of the weighted engine
+	valuePerScorePoint = 1000
+)
+
+// valuation explains value with the factors and puts the confidence interval around it.
+func valuation(value int, factors []entities.ValuationFactor) entities.Valuation {
+	return entities.Valuation{
+		Value:    value,
+		Currency: simulatedCurrency,
+		Factors:  factors,
+		Confidence: entities.ConfidenceInterval{
+			Level: confidenceLevel,
+			Lower: int(math.Round(float64(value) * (1 - confidenceMargin))),
+			Upper: int(math.Round(float64(value) * (1 + confidenceMargin))),
+		},
+	}
+}
+
+// fixedValue is the value of the original simulation, which is the same for every patent.
+func fixedValue([]entities.ValuationFactor) int {
+	return simulatedValue
+}
+
+// weightedValue derives the value from the weighted factor scores.
+func weightedValue(factors []entities.ValuationFactor) int {
+	total := 0.0
+
+	for _, factor := range factors {
+		total += factor.Weight * factor.Score
+	}
+
+	return int(math.Round(total * valuePerScorePoint))
+}
+
+// factors are derived from the claims and the content of the patent, so that the breakdown differs between patents
+// even if the value does not.
+func factors(job entities.EvaluationJob) []entities.ValuationFactor {
+	jobClaims := patenttext.Claims(job.PatentContent)
+	citations := 0
+
+	if job.Patent != nil {
+		if len(job.Patent.Claims) > 0 {
+			jobClaims = job.Patent.Claims
+		}
+
+		citations = len(job.Patent.CitedReferences)
+	}
+
+	metrics := claims.Analyze(jobClaims).Metrics
+	scope := 0.0
+
+	if metrics.ShortestIndependentClaimWords > 0 {
+		scope = maxScore * math.Min(1, float64(broadClaimWords)/float64(metrics.ShortestIndependentClaimWords))
+	}
+
+	return []entities.ValuationFactor{
+		{
+			Name:        "claimBreadth",
+			Description: "Number of independent claims, i.e. separate lines of protection",
+			Weight:      0.35, //nolint:gomnd // simulated weights
+			Score:       score(metrics.Breadth * independentClaimScore),
+		},
+		{
+			Name:        "claimScope",
+			Description: "Length of the shortest independent claim, shorter claims tend to protect more broadly",
+			Weight:      0.35, //nolint:gomnd // simulated weights
+			Score:       math.Round(scope),
+		},
+		{
+			Name:        "citations",
+			Description: "Number of cited patent documents",
+			Weight:      0.15, //nolint:gomnd // simulated weights
+			Score:       score(citations * citationScore),
+		},
+		{
+			Name:        "disclosure",
+			Description: "Extent of the disclosure",
+			Weight:      0.15, //nolint:gomnd // simulated weights
+			Score:       score(len(strings.Fields(job.PatentContent)) / wordsPerDisclosurePoint),
+		},
+	}
+}
+
+func score(value int) float64 {
+	return float64(min(value, maxScore))
+}
@@ -0,0 +1,137 @@
+//nolint:funlen // Test functions are long, due to test cases
+package simulation_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/simulation"
+)
+
+func TestEngine_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	words := func(count int) string {
+		return strings.TrimSpace(strings.Repeat("word ", count))
+	}
+	independent := func(number int, text string) entities.Claim {
+		return entities.Claim{Number: number, Text: text}
+	}
+	citations := func(count int) []string {
+		return make([]string, count)
+	}
+
+	testCases := []struct {
+		name string
+		job  entities.EvaluationJob
+		// wantScores are the scores of claimBreadth, claimScope, citations and disclosure
+		wantScores []float64
+		// wantValue is the value of the weighted engine
+		wantValue int
+		wantLower int
+		wantUpper int
+	}{
+		{
+			name:       "nothing to rate",
+			wantScores: []float64{0, 0, 0, 0},
+		},
+		{
+			name: "claims parsed from the content",
+			job: entities.EvaluationJob{
+				PatentContent: "Signature\nClaims\n1. A method comprising verifying a signature.\n2. The method of claim 1.",
+			},
+			wantScores: []float64{25, 100, 0, 0},
+			wantValue:  43750,
+			wantLower:  39375,
+			wantUpper:  48125,
+		},
+		{
+			name: "structured patent",
+			job: entities.EvaluationJob{
+				PatentContent: words(500),
+				Patent: &entities.Patent{
+					Claims: []entities.Claim{
+						independent(1, words(80)),
+						{Number: 2, Text: words(10), DependsOn: []int{1}},
+						independent(3, words(20)),
+					},
+					CitedReferences: citations(3),
+				},
+			},
+			wantScores: []float64{50, 100, 30, 10},
+			wantValue:  58500,
+			wantLower:  52650,
+			wantUpper:  64350,
+		},
+		{
+			name: "scores are capped",
+			job: entities.EvaluationJob{
+				PatentContent: words(6000),
+				Patent: &entities.Patent{
+					Claims: []entities.Claim{
+						independent(1, words(80)), independent(2, words(90)), independent(3, words(100)),
+						independent(4, words(110)), independent(5, words(120)),
+					},
+					CitedReferences: citations(12),
+				},
+			},
+			wantScores: []float64{100, 50, 100, 100},
+			wantValue:  82500,
+			wantLower:  74250,
+			wantUpper:  90750,
+		},
+	}
+
+	cfg := &config.SimulationConfig{}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			weighted, err := simulation.NewWeightedEngine(cfg).Evaluate(context.Background(), tc.job)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			names := make([]string, 0, len(weighted.Factors))
+			scores := make([]float64, 0, len(weighted.Factors))
+			weights := 0.0
+			contributions := 0.0
+
+			for _, factor := range weighted.Factors {
+				names = append(names, factor.Name)
+				scores = append(scores, factor.Score)
+				weights += factor.Weight
+				contributions += factor.Weight * factor.Score * 1000
+
+				assert.NotEmpty(t, factor.Description)
+			}
+
+			assert.Equal(t, []string{"claimBreadth", "claimScope", "citations", "disclosure"}, names)
+			assert.Equal(t, tc.wantScores, scores)
+			assert.InDelta(t, 1, weights, 1e-9)
+
+			assert.Equal(t, tc.wantValue, weighted.Value)
+			assert.Equal(t, int(math.Round(contributions)), weighted.Value, "contributions add up to the value")
+			assert.Equal(t, "EUR", weighted.Currency)
+			assert.Equal(t, entities.ConfidenceInterval{Level: 0.9, Lower: tc.wantLower, Upper: tc.wantUpper},
+				weighted.Confidence)
+
+			fixed, err := simulation.NewEngine(cfg).Evaluate(context.Background(), tc.job)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, weighted.Factors, fixed.Factors, "both versions explain by the same factors")
+			assert.Equal(t, 42000, fixed.Value)
+			assert.Equal(t, entities.ConfidenceInterval{Level: 0.9, Lower: 37800, Upper: 46200}, fixed.Confidence)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/api/admin"
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/scheduler"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/pkg/log"
+)
+
+// markReady queues the job for the workers; the caller has to hold the mutex.
+func (s *inMemoryQueueAndQuotaServiceSimulation) markReady(job *entities.EvaluationJob) {
+	if !job.QueuedAt.IsZero() {
+		return
+	}
+
+	job.QueuedAt = time.Now()
+	s.readyJobs.Push(scheduler.Item{
+		ID:       job.ID,
+		OwnerID:  job.OwnerID,
+		Priority: job.Priority,
+		Weight:   job.SchedulingWeight,
+	})
+	s.notifyWorkers()
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) notifyWorkers() {
+	select {
+	case s.jobReady <- struct{}{}:
+	default:
+	}
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) NextJob(
+	ctx context.Context, workerID string,
+) (entities.EvaluationJob, error) {
+	for {
+		if job, ok := s.popReadyJob(workerID); ok {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return entities.EvaluationJob{}, errors.Wrap(ctx.Err(), "waiting for next job")
+		case <-s.jobReady:
+		}
+	}
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) popReadyJob(workerID string) (entities.EvaluationJob, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for {
+		item, ok := s.readyJobs.Pop()
+		if !ok {
+			return entities.EvaluationJob{}, false
+		}
+
+		job := s.jobsByID[item.ID]
+		if job == nil || job.EvaluationJobStatus != entities.EvaluationJobStatusPending || job.QueuedAt.IsZero() {
+			continue
+		}
+
+		job.Attempts++
+		job.EvaluationJobStatus = entities.EvaluationJobStatusRunning
+		job.WorkerID = workerID
+		job.HeartbeatAt = time.Now()
+		job.QueuedAt = time.Time{}
+
+		// wake up another worker in case there is more work
+		if s.readyJobs.Len() > 0 {
+			s.notifyWorkers()
+		}
+
+		return copyJob(job), true
+	}
+}
+
+// leasedJob returns the job if it is running on the given worker; the caller has to hold the mutex.
+func (s *inMemoryQueueAndQuotaServiceSimulation) leasedJob(
+	id uuid.UUID, workerID string,
+) (*entities.EvaluationJob, error) {
+	job := s.jobsByID[id]
+	if job == nil {
+		return nil, patents.ErrJobNotFound
+	}
+
+	if job.EvaluationJobStatus != entities.EvaluationJobStatusRunning || job.WorkerID != workerID {
+		return nil, worker.ErrLeaseLost
+	}
+
+	return job, nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) Heartbeat(id uuid.UUID, workerID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.leasedJob(id, workerID)
+	if err != nil {
+		return err
+	}
+
+	job.HeartbeatAt = time.Now()
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) FinishJob(
+	id uuid.UUID, workerID string, valuation entities.Valuation, engine entities.EngineInfo,
+) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.leasedJob(id, workerID)
+	if err != nil {
+		return err
+	}
+
+	job.EvaluationJobStatus = entities.EvaluationJobStatusFinished
+	job.WorkerID = ""
+	job.Value = valuation.Value
+	job.Currency = valuation.Currency
+	job.Factors = valuation.Factors
+	job.Confidence = valuation.Confidence
+	job.Engine = engine
+	job.FinishedAt = time.Now()
+
+	if job.ContentHash != "" {
+		s.results[resultKey(job.OwnerID, job.ContentHash, engine)] = entities.CachedResult{
+			JobID:      job.ID,
+			Value:      job.Value,
+			Currency:   job.Currency,
+			Factors:    job.Factors,
+			Confidence: job.Confidence,
+			Engine:     job.Engine,
+			FinishedAt: job.FinishedAt,
+		}
+	}
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) RetryJob(
+	id uuid.UUID, workerID string, jobError entities.JobError, notBefore time.Time,
+) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.leasedJob(id, workerID)
+	if err != nil {
+		return err
+	}
+
+	job.Errors = append(job.Errors, jobError)
+	job.EvaluationJobStatus = entities.EvaluationJobStatusPending
+	job.WorkerID = ""
+	job.NextAttemptAt = notBefore
+
+	time.AfterFunc(time.Until(notBefore), func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if job.EvaluationJobStatus == entities.EvaluationJobStatusPending {
+			s.markReady(job)
+		}
+	})
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) DeadLetterJob(
+	id uuid.UUID, workerID string, jobError entities.JobError,
+) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.leasedJob(id, workerID)
+	if err != nil {
+		return err
+	}
+
+	s.deadLetter(job, jobError)
+
+	return nil
+}
+
+// deadLetter fails the job and moves it to the dead-letter set; the caller has to hold the mutex.
+func (s *inMemoryQueueAndQuotaServiceSimulation) deadLetter(job *entities.EvaluationJob, jobError entities.JobError) {
+	job.Errors = append(job.Errors, jobError)
+	job.EvaluationJobStatus = entities.EvaluationJobStatusFailed
+	job.FailureReason = jobError.Message
+	job.WorkerID = ""
+	job.NextAttemptAt = time.Time{}
+	job.QueuedAt = time.Time{}
+	s.deadLetters[job.ID] = job
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetStaleJobs(heartbeatBefore time.Time) ([]entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []entities.EvaluationJob
+
+	for _, job := range s.jobs {
+		if job.EvaluationJobStatus == entities.EvaluationJobStatusRunning && job.HeartbeatAt.Before(heartbeatBefore) {
+			result = append(result, copyJob(job))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetExpiredJobs(queuedBefore time.Time) ([]entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []entities.EvaluationJob
+
+	for _, job := range s.jobs {
+		if job.EvaluationJobStatus == entities.EvaluationJobStatusPending &&
+			!job.QueuedAt.IsZero() && job.QueuedAt.Before(queuedBefore) {
+			result = append(result, copyJob(job))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) ExpireJob(id uuid.UUID, jobError entities.JobError) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.jobsByID[id]
+	if job == nil {
+		return patents.ErrJobNotFound
+	}
+
+	if job.EvaluationJobStatus != entities.EvaluationJobStatusPending || job.QueuedAt.IsZero() {
+		return worker.ErrJobNotQueued
+	}
+
+	s.readyJobs.Remove(id)
+	s.deadLetter(job, jobError)
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetDeadLetterJobs() ([]entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]entities.EvaluationJob, 0, len(s.deadLetters))
+
+	// iterate over all jobs to keep the dead letters in submission order
+	for _, job := range s.jobs {
+		if _, ok := s.deadLetters[job.ID]; ok {
+			result = append(result, copyJob(job))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetDeadLetterJobByID(id uuid.UUID) (entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.deadLetters[id]
+	if job == nil {
+		return entities.EvaluationJob{}, admin.ErrDeadLetterJobNotFound
+	}
+
+	return copyJob(job), nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) RequeueDeadLetterJob(id uuid.UUID) (entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.deadLetters[id]
+	if job == nil {
+		return entities.EvaluationJob{}, admin.ErrDeadLetterJobNotFound
+	}
+
+	delete(s.deadLetters, id)
+
+	job.EvaluationJobStatus = entities.EvaluationJobStatusPending
+	job.FailureReason = ""
+	job.Attempts = 0
+	s.markReady(job)
+
+	log.Infof("Job %s requeued from the dead-letter queue", id.String())
+
+	return copyJob(job), nil
+}
@@ -0,0 +1,57 @@
+package simulation
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) CreatePortfolio(portfolio entities.Portfolio) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	portfolio = copyPortfolio(portfolio)
+	s.portfolios[portfolio.ID] = portfolio
+	s.portfoliosByOwner[portfolio.OwnerID] = append(s.portfoliosByOwner[portfolio.OwnerID], portfolio.ID)
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetPortfoliosByOwnerID(ownerID string) ([]entities.Portfolio, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := s.portfoliosByOwner[ownerID]
+	if ids == nil {
+		return nil, nil
+	}
+
+	result := make([]entities.Portfolio, len(ids))
+	for i, id := range ids {
+		result[i] = copyPortfolio(s.portfolios[id])
+	}
+
+	return result, nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetPortfolioByID(id uuid.UUID) (entities.Portfolio, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	portfolio, ok := s.portfolios[id]
+	if !ok {
+		return entities.Portfolio{}, portfolios.ErrPortfolioNotFound
+	}
+
+	return copyPortfolio(portfolio), nil
+}
+
+func copyPortfolio(portfolio entities.Portfolio) entities.Portfolio {
+	portfolio.JobIDs = slices.Clone(portfolio.JobIDs)
+	portfolio.BatchIDs = slices.Clone(portfolio.BatchIDs)
+
+	return portfolio
+}
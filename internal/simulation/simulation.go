@@ -1,25 +1,64 @@
 package simulation
 
 import (
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
+	"github.com/MyChaOS87/patAi/internal/api/admin"
 	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
 	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/scheduler"
+	"github.com/MyChaOS87/patAi/internal/search"
+	"github.com/MyChaOS87/patAi/internal/worker"
 	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const (
+	quotaTokensPerOwner = 5
+	quotaTokenLifetime  = 5 * time.Minute
 )
 
 type Simulation interface {
 	patents.QueueService
 	patents.QuotaService
+	patents.ResultCache
+	worker.JobStore
+	admin.DeadLetterService
+	patents.BatchService
+	patents.SearchService
+	portfolios.PortfolioService
+}
+
+type quotaToken struct {
+	id        uuid.UUID
+	expiresAt time.Time
 }
 
 type inMemoryQueueAndQuotaServiceSimulation struct {
-	jobs               []*entities.EvaluationJob
-	jobsByID           map[uuid.UUID]*entities.EvaluationJob
-	jobsByOwner        map[string][]*entities.EvaluationJob
-	quotaTokensByOwner map[string][]uuid.UUID
+	mutex       sync.Mutex
+	jobs        []*entities.EvaluationJob
+	jobsByID    map[uuid.UUID]*entities.EvaluationJob
+	jobsByOwner map[string][]*entities.EvaluationJob
+	// quota tokens by owner in the order they were granted, which is the order they expire in
+	quotaTokensByOwner map[string][]quotaToken
+	readyJobs          scheduler.Scheduler
+	jobReady           chan struct{}
+	deadLetters        map[uuid.UUID]*entities.EvaluationJob
+	// latest results by owner, content hash and engine, see resultKey
+	results map[string]entities.CachedResult
+	batches map[uuid.UUID]entities.Batch
+	// portfolios by ID, and their IDs by owner in creation order
+	portfolios        map[uuid.UUID]entities.Portfolio
+	portfoliosByOwner map[string][]uuid.UUID
+	// searchIndex holds every job, it is updated as jobs are created, their metadata changes and they are deleted
+	searchIndex search.Index
 }
 
 func NewInMemoryQueueAndQuotaServiceSimulation() Simulation {
@@ -27,38 +66,74 @@ func NewInMemoryQueueAndQuotaServiceSimulation() Simulation {
 		jobs:               []*entities.EvaluationJob{},
 		jobsByID:           map[uuid.UUID]*entities.EvaluationJob{},
 		jobsByOwner:        map[string][]*entities.EvaluationJob{},
-		quotaTokensByOwner: map[string][]uuid.UUID{},
+		quotaTokensByOwner: map[string][]quotaToken{},
+		readyJobs:          scheduler.NewWeightedFairScheduler(),
+		jobReady:           make(chan struct{}, 1),
+		deadLetters:        map[uuid.UUID]*entities.EvaluationJob{},
+		results:            map[string]entities.CachedResult{},
+		batches:            map[uuid.UUID]entities.Batch{},
+		portfolios:         map[uuid.UUID]entities.Portfolio{},
+		portfoliosByOwner:  map[string][]uuid.UUID{},
+		searchIndex:        search.NewIndex(),
 	}
 }
 
-func (s *inMemoryQueueAndQuotaServiceSimulation) EnqueueJob(ownerID string, content string) (entities.EvaluationJob, error) {
+func (s *inMemoryQueueAndQuotaServiceSimulation) EnqueueJob(
+	ownerID string, content string, options entities.JobOptions,
+) (entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	job := entities.EvaluationJob{
 		ID:                  uuid.New(),
 		OwnerID:             ownerID,
 		EvaluationJobStatus: entities.EvaluationJobStatusPending,
 		PatentContent:       content,
+		CreatedAt:           time.Now(),
+		Priority:            options.Priority,
+		SchedulingWeight:    options.SchedulingWeight,
+		ContentHash:         options.ContentHash,
+		BatchID:             options.BatchID,
+		TechnicalField:      options.TechnicalField,
+		Patent:              options.Patent,
+		Document:            options.Document,
+		RevaluationOf:       options.RevaluationOf,
+		PatentKey:           options.PatentKey,
+		Metadata:            copyMetadata(options.Metadata),
+		Engine:              options.Engine,
 	}
 
 	s.jobs = append(s.jobs, &job)
 	s.jobsByID[job.ID] = &job
 	s.jobsByOwner[job.OwnerID] = append(s.jobsByOwner[job.OwnerID], &job)
+	s.searchIndex.Add(job)
 
-	log.Infof("Job %s scheduled for execution", job.ID.String())
+	if cached := options.CachedResult; cached != nil {
+		job.EvaluationJobStatus = entities.EvaluationJobStatusFinished
+		job.Value = cached.Value
+		job.Currency = cached.Currency
+		job.Factors = cached.Factors
+		job.Confidence = cached.Confidence
+		job.Engine = cached.Engine
+		job.FinishedAt = job.CreatedAt
+		job.Cached = true
 
-	// Simulate trigger of evaluation
-	go func() {
-		//nolint:gomnd // Simulation preset
-		time.Sleep(2 * time.Minute)
+		log.Infof("Job %s finished with the cached result of job %s", job.ID.String(), cached.JobID.String())
 
-		log.Infof("Job %s finished evaluation", job.ID.String())
-		job.EvaluationJobStatus = entities.EvaluationJobStatusFinished
-		job.Value = 42
-	}()
+		return copyJob(&job), nil
+	}
+
+	s.markReady(&job)
+
+	log.Infof("Job %s scheduled for execution", job.ID.String())
 
-	return job, nil
+	return copyJob(&job), nil
 }
 
 func (s *inMemoryQueueAndQuotaServiceSimulation) GetJobsByOwnerID(ownerID string) ([]entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	jobs := s.jobsByOwner[ownerID]
 	if jobs == nil {
 		return nil, nil
@@ -66,46 +141,147 @@ func (s *inMemoryQueueAndQuotaServiceSimulation) GetJobsByOwnerID(ownerID string
 
 	result := make([]entities.EvaluationJob, len(jobs))
 	for i, j := range jobs {
-		result[i] = *j
+		result[i] = copyJob(j)
 	}
 
 	return result, nil
 }
 
+// EachJobByOwnerID copies one job at a time, fn runs without holding the lock so a slow consumer does not block the
+// workers.
+func (s *inMemoryQueueAndQuotaServiceSimulation) EachJobByOwnerID(
+	ownerID string, fn func(entities.EvaluationJob) error,
+) error {
+	s.mutex.Lock()
+	jobs := slices.Clone(s.jobsByOwner[ownerID])
+	s.mutex.Unlock()
+
+	for _, j := range jobs {
+		s.mutex.Lock()
+		job := copyJob(j)
+		s.mutex.Unlock()
+
+		if err := fn(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *inMemoryQueueAndQuotaServiceSimulation) GetJobByID(id uuid.UUID) (entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	job := s.jobsByID[id]
 	if job == nil {
 		return entities.EvaluationJob{}, patents.ErrJobNotFound
 	}
 
-	return *job, nil
+	return copyJob(job), nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) UpdateJobMetadata(
+	id uuid.UUID, metadata entities.JobMetadata,
+) (entities.EvaluationJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.jobsByID[id]
+	if job == nil {
+		return entities.EvaluationJob{}, patents.ErrJobNotFound
+	}
+
+	job.Metadata = copyMetadata(metadata)
+	s.searchIndex.Add(*job)
+
+	return copyJob(job), nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) DeleteJob(id uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.jobsByID[id]
+	if job == nil {
+		return patents.ErrJobNotFound
+	}
+
+	isJob := func(j *entities.EvaluationJob) bool { return j.ID == id }
+
+	s.jobs = slices.DeleteFunc(s.jobs, isJob)
+	s.jobsByOwner[job.OwnerID] = slices.DeleteFunc(s.jobsByOwner[job.OwnerID], isJob)
+	delete(s.jobsByID, id)
+	delete(s.deadLetters, id)
+	s.readyJobs.Remove(id)
+	s.searchIndex.Remove(job.OwnerID, id)
+
+	return nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) SearchJobs(ownerID string, query string) ([]entities.SearchHit, error) {
+	parsed, err := search.Parse(query)
+	if errors.Is(err, search.ErrInvalidQuery) {
+		return nil, problem.Detailed(patents.ErrInvalidQuery, "%v", err)
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return s.searchIndex.Search(ownerID, parsed), nil
 }
 
 func (s *inMemoryQueueAndQuotaServiceSimulation) GetQuotaToken(ownerID string) (uuid.UUID, error) {
+	tokens, err := s.GetQuotaTokens(ownerID, 1)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return tokens[0], nil
+}
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetQuotaTokens(ownerID string, count int) ([]uuid.UUID, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	tokens := s.quotaTokensByOwner[ownerID]
-	//nolint:gomnd // Simulation preset
-	if len(tokens) >= 5 {
-		return uuid.UUID{}, patents.ErrQuotaExceeded
+	if excess := len(tokens) + count - quotaTokensPerOwner; excess > 0 {
+		if count > quotaTokensPerOwner {
+			return nil, patents.ErrQuotaExceeded
+		}
+
+		// enough of the quota is free once the excess-th token expired
+		return nil, problem.RetryAfter(patents.ErrQuotaExceeded, max(time.Until(tokens[excess-1].expiresAt), 0))
 	}
 
-	token := uuid.New()
-	s.quotaTokensByOwner[ownerID] = append(tokens, token)
+	granted := make([]uuid.UUID, count)
+	expiresAt := time.Now().Add(quotaTokenLifetime)
 
-	// Simulate token expiration after 5 min
+	for i := range granted {
+		granted[i] = uuid.New()
+		tokens = append(tokens, quotaToken{id: granted[i], expiresAt: expiresAt})
+	}
+
+	s.quotaTokensByOwner[ownerID] = tokens
+
+	// Simulate token expiration
 	go func() {
-		//nolint:gomnd // Simulation preset
-		time.Sleep(5 * time.Minute)
+		time.Sleep(quotaTokenLifetime)
 
-		s.ReturnQuotaToken(token)
+		for _, token := range granted {
+			s.ReturnQuotaToken(token)
+		}
 	}()
 
-	return token, nil
+	return granted, nil
 }
 
 func (s *inMemoryQueueAndQuotaServiceSimulation) ReturnQuotaToken(token uuid.UUID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	for ownerID, tokens := range s.quotaTokensByOwner {
 		for i, t := range tokens {
-			if t == token {
+			if t.id == token {
 				s.quotaTokensByOwner[ownerID] = append(tokens[:i], tokens[i+1:]...)
 
 				return
@@ -113,3 +289,38 @@ func (s *inMemoryQueueAndQuotaServiceSimulation) ReturnQuotaToken(token uuid.UUI
 		}
 	}
 }
+
+func (s *inMemoryQueueAndQuotaServiceSimulation) GetCachedResult(
+	ownerID string, contentHash string, engine entities.EngineInfo, notBefore time.Time,
+) (entities.CachedResult, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result, ok := s.results[resultKey(ownerID, contentHash, engine)]
+	if !ok || result.FinishedAt.Before(notBefore) {
+		return entities.CachedResult{}, false, nil
+	}
+
+	return result, true, nil
+}
+
+func resultKey(ownerID string, contentHash string, engine entities.EngineInfo) string {
+	return ownerID + "\x00" + contentHash + "\x00" + engine.Name + "\x00" + engine.Version
+}
+
+// copyJob returns a snapshot of the job that does not share mutable state with the stored one.
+func copyJob(job *entities.EvaluationJob) entities.EvaluationJob {
+	result := *job
+	result.Errors = append([]entities.JobError(nil), job.Errors...)
+	result.Metadata = copyMetadata(job.Metadata)
+
+	return result
+}
+
+func copyMetadata(metadata entities.JobMetadata) entities.JobMetadata {
+	return entities.JobMetadata{
+		Labels: maps.Clone(metadata.Labels),
+		Tags:   slices.Clone(metadata.Tags),
+		Note:   metadata.Note,
+	}
+}
@@ -0,0 +1,57 @@
+package simulation_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/simulation"
+)
+
+// deleted jobs are gone from every view of the store, including the full-text search.
+func TestSimulation_DeleteJob(t *testing.T) {
+	t.Parallel()
+
+	store := simulation.NewInMemoryQueueAndQuotaServiceSimulation()
+
+	deleted, err := store.EnqueueJob("owner", "A method for valuing patents", entities.JobOptions{})
+	assert.NoError(t, err)
+
+	kept, err := store.EnqueueJob("owner", "A system for valuing patents", entities.JobOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.DeleteJob(deleted.ID))
+
+	_, err = store.GetJobByID(deleted.ID)
+	assert.ErrorIs(t, err, patents.ErrJobNotFound)
+
+	jobs, err := store.GetJobsByOwnerID("owner")
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{kept.ID}, jobIDs(jobs))
+
+	hits, err := store.SearchJobs("owner", "valuing")
+	assert.NoError(t, err)
+
+	if assert.Len(t, hits, 1) {
+		assert.Equal(t, kept.ID, hits[0].JobID)
+	}
+
+	hits, err = store.SearchJobs("owner", "method")
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	assert.ErrorIs(t, store.DeleteJob(deleted.ID), patents.ErrJobNotFound)
+}
+
+func jobIDs(jobs []entities.EvaluationJob) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(jobs))
+
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+
+	return ids
+}
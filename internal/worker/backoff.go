@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"math"
+	"time"
+
+	"github.com/MyChaOS87/patAi/config"
+)
+
+// Backoff returns the delay before the next attempt after the given (1-based) attempt failed.
+// It grows exponentially from InitialBackoff by BackoffMultiplier and is capped at MaxBackoff.
+func Backoff(cfg *config.WorkerConfig, attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	multiplier := cfg.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(cfg.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if cfg.MaxBackoff > 0 && delay > float64(cfg.MaxBackoff) {
+		return cfg.MaxBackoff
+	}
+
+	return time.Duration(delay)
+}
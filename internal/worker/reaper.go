@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/log"
+)
+
+type Reaper interface {
+	// Run reaps periodically until the context is done
+	Run(ctx context.Context)
+	// Reap requeues or fails running jobs whose worker vanished and fails jobs waiting too long for a worker
+	Reap()
+}
+
+type reaper struct {
+	cfg   *config.WorkerConfig
+	store JobStore
+}
+
+func NewReaper(cfg *config.WorkerConfig, store JobStore) Reaper {
+	return &reaper{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+func (r *reaper) Run(ctx context.Context) {
+	if r.cfg.ReaperInterval <= 0 {
+		log.Warn("reaper disabled, abandoned jobs will not be recovered")
+
+		return
+	}
+
+	ticker := time.NewTicker(r.cfg.ReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reap()
+		}
+	}
+}
+
+func (r *reaper) Reap() {
+	now := time.Now()
+
+	if r.cfg.LeaseTimeout > 0 {
+		r.reapStaleJobs(now)
+	}
+
+	if r.cfg.MaxQueueTime > 0 {
+		r.reapExpiredJobs(now)
+	}
+}
+
+func (r *reaper) reapStaleJobs(now time.Time) {
+	jobs, err := r.store.GetStaleJobs(now.Add(-r.cfg.LeaseTimeout))
+	if err != nil {
+		log.Errorf("cannot get stale jobs: %v", err)
+
+		return
+	}
+
+	for _, job := range jobs {
+		jobError := entities.JobError{
+			Attempt: job.Attempts,
+			Message: fmt.Sprintf("worker %s vanished, no heartbeat since %s",
+				job.WorkerID, job.HeartbeatAt.Format(time.RFC3339)),
+			OccurredAt: now,
+		}
+
+		if job.Attempts >= r.cfg.MaxAttempts {
+			log.Errorf("Job %s abandoned on its last attempt, moving it to the dead-letter queue: %s",
+				job.ID.String(), jobError.Message)
+
+			err = r.store.DeadLetterJob(job.ID, job.WorkerID, jobError)
+		} else {
+			log.Warnf("Job %s abandoned, requeueing it: %s", job.ID.String(), jobError.Message)
+
+			err = r.store.RetryJob(job.ID, job.WorkerID, jobError, now)
+		}
+
+		if err != nil {
+			log.Warnf("cannot reap job %s: %v", job.ID.String(), err)
+		}
+	}
+}
+
+func (r *reaper) reapExpiredJobs(now time.Time) {
+	jobs, err := r.store.GetExpiredJobs(now.Add(-r.cfg.MaxQueueTime))
+	if err != nil {
+		log.Errorf("cannot get expired jobs: %v", err)
+
+		return
+	}
+
+	for _, job := range jobs {
+		jobError := entities.JobError{
+			Attempt:    job.Attempts,
+			Message:    fmt.Sprintf("not picked up by a worker within %s", r.cfg.MaxQueueTime),
+			OccurredAt: now,
+		}
+
+		log.Errorf("Job %s expired in the queue, moving it to the dead-letter queue", job.ID.String())
+
+		if err := r.store.ExpireJob(job.ID, jobError); err != nil {
+			log.Warnf("cannot expire job %s: %v", job.ID.String(), err)
+		}
+	}
+}
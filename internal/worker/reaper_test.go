@@ -0,0 +1,84 @@
+//nolint:funlen // Test functions are long, due to test cases
+package worker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/internal/worker/mocks"
+)
+
+func TestReaper_Reap(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+
+	testCases := []struct {
+		name        string
+		preparation func(*mocks.JobStore)
+	}{
+		{
+			name: "nothing to reap",
+			preparation: func(store *mocks.JobStore) {
+				store.On("GetStaleJobs", mock.Anything).Return(nil, nil).Once()
+				store.On("GetExpiredJobs", mock.Anything).Return(nil, nil).Once()
+			},
+		},
+		{
+			name: "abandoned job with attempts left is requeued",
+			preparation: func(store *mocks.JobStore) {
+				store.On("GetStaleJobs", mock.Anything).Return([]entities.EvaluationJob{
+					{ID: id, Attempts: 1, WorkerID: "gone", EvaluationJobStatus: entities.EvaluationJobStatusRunning},
+				}, nil).Once()
+				store.On("RetryJob", id, "gone", mock.MatchedBy(func(e entities.JobError) bool {
+					return e.Attempt == 1
+				}), mock.Anything).Return(nil).Once()
+				store.On("GetExpiredJobs", mock.Anything).Return(nil, nil).Once()
+			},
+		},
+		{
+			name: "abandoned job on its last attempt is dead-lettered",
+			preparation: func(store *mocks.JobStore) {
+				store.On("GetStaleJobs", mock.Anything).Return([]entities.EvaluationJob{
+					{ID: id, Attempts: 3, WorkerID: "gone", EvaluationJobStatus: entities.EvaluationJobStatusRunning},
+				}, nil).Once()
+				store.On("DeadLetterJob", id, "gone", mock.Anything).Return(nil).Once()
+				store.On("GetExpiredJobs", mock.Anything).Return(nil, nil).Once()
+			},
+		},
+		{
+			name: "job waiting too long for a worker is expired",
+			preparation: func(store *mocks.JobStore) {
+				store.On("GetStaleJobs", mock.Anything).Return(nil, nil).Once()
+				store.On("GetExpiredJobs", mock.MatchedBy(func(queuedBefore time.Time) bool {
+					return time.Since(queuedBefore) >= time.Hour
+				})).Return([]entities.EvaluationJob{
+					{ID: id, EvaluationJobStatus: entities.EvaluationJobStatusPending},
+				}, nil).Once()
+				store.On("ExpireJob", id, mock.Anything).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := mocks.NewJobStore(t)
+			tc.preparation(store)
+
+			worker.NewReaper(&config.WorkerConfig{
+				MaxAttempts:  3,
+				LeaseTimeout: time.Minute,
+				MaxQueueTime: time.Hour,
+			}, store).Reap()
+		})
+	}
+}
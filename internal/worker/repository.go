@@ -0,0 +1,51 @@
+//go:generate mockery --name JobStore|Engine|EngineRegistry
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/internal/entities"
+)
+
+var (
+	// ErrLeaseLost is returned by the JobStore if the job is no longer running on the given worker,
+	// e.g. because the reaper already gave it to someone else.
+	ErrLeaseLost = errors.New("job lease lost")
+	// ErrJobNotQueued is returned by the JobStore if a job to expire is no longer waiting in the queue.
+	ErrJobNotQueued = errors.New("job not queued")
+)
+
+type JobStore interface {
+	// NextJob blocks until a job is ready for evaluation or the context is done and marks it running on the worker,
+	// the returned job already accounts for the attempt about to be started
+	NextJob(ctx context.Context, workerID string) (entities.EvaluationJob, error)
+	Heartbeat(id uuid.UUID, workerID string) error
+	FinishJob(id uuid.UUID, workerID string, valuation entities.Valuation, engine entities.EngineInfo) error
+	// RetryJob records the failed attempt and makes the job available again once notBefore has passed
+	RetryJob(id uuid.UUID, workerID string, jobError entities.JobError, notBefore time.Time) error
+	// DeadLetterJob records the final failed attempt, marks the job failed and moves it to the dead-letter set
+	DeadLetterJob(id uuid.UUID, workerID string, jobError entities.JobError) error
+
+	// GetStaleJobs returns running jobs without heartbeat since the given point in time
+	GetStaleJobs(heartbeatBefore time.Time) ([]entities.EvaluationJob, error)
+	// GetExpiredJobs returns jobs waiting in the queue since before the given point in time
+	GetExpiredJobs(queuedBefore time.Time) ([]entities.EvaluationJob, error)
+	// ExpireJob fails a job that is still waiting in the queue and moves it to the dead-letter set
+	ExpireJob(id uuid.UUID, jobError entities.JobError) error
+}
+
+type Engine interface {
+	Name() string
+	Version() string
+	Evaluate(ctx context.Context, job entities.EvaluationJob) (entities.Valuation, error)
+}
+
+type EngineRegistry interface {
+	// Engine returns the engine a job was created for, the zero EngineInfo selects the default engine
+	Engine(info entities.EngineInfo) (Engine, error)
+}
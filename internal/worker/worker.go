@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/pkg/log"
+)
+
+var ErrExecutionTimeout = errors.New("execution timed out")
+
+// minFetchBackoff is the least time a worker waits after failing to fetch a job, even without configured backoff.
+const minFetchBackoff = 100 * time.Millisecond
+
+type Worker interface {
+	// Run processes jobs until the context is done
+	Run(ctx context.Context)
+}
+
+type worker struct {
+	cfg     *config.WorkerConfig
+	store   JobStore
+	engines EngineRegistry
+}
+
+// NewWorker values each job with the engine it was created for.
+func NewWorker(cfg *config.WorkerConfig, store JobStore, engines EngineRegistry) Worker {
+	return &worker{
+		cfg:     cfg,
+		store:   store,
+		engines: engines,
+	}
+}
+
+func (w *worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < max(w.cfg.Count, 1); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			w.loop(ctx, uuid.NewString())
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (w *worker) loop(ctx context.Context, workerID string) {
+	failures := 0
+
+	for {
+		job, err := w.store.NextJob(ctx, workerID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			failures++
+			delay := max(Backoff(w.cfg, failures), minFetchBackoff)
+
+			log.Errorf("cannot fetch next job, retrying in %s: %v", delay, err)
+
+			// a failing store must neither keep the workers busy nor flood the log
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			continue
+		}
+
+		failures = 0
+
+		w.process(ctx, workerID, job)
+	}
+}
+
+func (w *worker) executionTimeout(engine Engine) time.Duration {
+	if timeout, ok := w.cfg.ExecutionTimeouts[engine.Name()]; ok && timeout > 0 {
+		return timeout
+	}
+
+	return w.cfg.DefaultExecutionTimeout
+}
+
+func (w *worker) evaluate(
+	ctx context.Context, workerID string, engine Engine, job entities.EvaluationJob,
+) (entities.Valuation, error) {
+	leaseCtx, loseLease := context.WithCancelCause(ctx)
+	defer loseLease(nil)
+
+	jobCtx := leaseCtx
+
+	if timeout := w.executionTimeout(engine); timeout > 0 {
+		var cancel context.CancelFunc
+
+		jobCtx, cancel = context.WithTimeoutCause(leaseCtx, timeout, fmt.Errorf("%w after %s", ErrExecutionTimeout, timeout))
+		defer cancel()
+	}
+
+	if w.cfg.HeartbeatInterval > 0 {
+		go w.heartbeat(jobCtx, loseLease, workerID, job)
+	}
+
+	valuation, err := engine.Evaluate(jobCtx, job)
+	if cause := context.Cause(jobCtx); cause != nil && ctx.Err() == nil {
+		return entities.Valuation{}, cause
+	}
+
+	return valuation, err
+}
+
+func (w *worker) heartbeat(
+	ctx context.Context, loseLease context.CancelCauseFunc, workerID string, job entities.EvaluationJob,
+) {
+	ticker := time.NewTicker(w.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.store.Heartbeat(job.ID, workerID); errors.Is(err, ErrLeaseLost) {
+				loseLease(ErrLeaseLost)
+
+				return
+			} else if err != nil {
+				log.Errorf("cannot send heartbeat for job %s: %v", job.ID.String(), err)
+			}
+		}
+	}
+}
+
+func (w *worker) process(ctx context.Context, workerID string, job entities.EvaluationJob) {
+	engine, err := w.engines.Engine(job.Engine)
+	if err != nil {
+		w.fail(workerID, job, err)
+
+		return
+	}
+
+	valuation, err := w.evaluate(ctx, workerID, engine, job)
+	if ctx.Err() != nil {
+		log.Warnf("Job %s interrupted by shutdown", job.ID.String())
+
+		return
+	}
+
+	if errors.Is(err, ErrLeaseLost) {
+		log.Warnf("Job %s was taken away from worker %s, dropping the evaluation", job.ID.String(), workerID)
+
+		return
+	}
+
+	if err == nil {
+		if err := w.store.FinishJob(job.ID, workerID, valuation, entities.EngineInfo{
+			Name:    engine.Name(),
+			Version: engine.Version(),
+		}); err != nil {
+			log.Errorf("cannot finish job %s: %v", job.ID.String(), err)
+
+			return
+		}
+
+		log.Infof("Job %s finished evaluation", job.ID.String())
+
+		return
+	}
+
+	w.fail(workerID, job, err)
+}
+
+// fail retries the job with backoff or dead-letters it once its attempts are exhausted.
+func (w *worker) fail(workerID string, job entities.EvaluationJob, err error) {
+	jobError := entities.JobError{
+		Attempt:    job.Attempts,
+		Message:    err.Error(),
+		OccurredAt: time.Now(),
+	}
+
+	if job.Attempts >= w.cfg.MaxAttempts {
+		log.Errorf("Job %s failed attempt %d of %d, moving it to the dead-letter queue: %v",
+			job.ID.String(), job.Attempts, w.cfg.MaxAttempts, err)
+
+		if err := w.store.DeadLetterJob(job.ID, workerID, jobError); err != nil {
+			log.Errorf("cannot dead-letter job %s: %v", job.ID.String(), err)
+		}
+
+		return
+	}
+
+	delay := Backoff(w.cfg, job.Attempts)
+
+	log.Warnf("Job %s failed attempt %d of %d, retrying in %s: %v",
+		job.ID.String(), job.Attempts, w.cfg.MaxAttempts, delay, err)
+
+	if err := w.store.RetryJob(job.ID, workerID, jobError, time.Now().Add(delay)); err != nil {
+		log.Errorf("cannot retry job %s: %v", job.ID.String(), err)
+	}
+}
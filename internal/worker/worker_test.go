@@ -0,0 +1,250 @@
+//nolint:funlen // Test functions are long, due to test cases
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/entities"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/internal/worker/mocks"
+)
+
+var (
+	errEngine = errors.New("engine error")
+	errStore  = errors.New("store error")
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.WorkerConfig{
+		InitialBackoff:    time.Second,
+		MaxBackoff:        10 * time.Second,
+		BackoffMultiplier: 2,
+	}
+
+	testCases := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first retry waits the initial backoff", attempt: 1, want: time.Second},
+		{name: "second retry doubles", attempt: 2, want: 2 * time.Second},
+		{name: "third retry doubles again", attempt: 3, want: 4 * time.Second},
+		{name: "backoff is capped", attempt: 10, want: 10 * time.Second},
+		{name: "invalid attempt is treated as first", attempt: 0, want: time.Second},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, worker.Backoff(cfg, tc.attempt))
+		})
+	}
+}
+
+func TestWorker_Run(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("0441f94b-9a04-4015-9190-f213d55bf9fb")
+
+	testCases := []struct {
+		name        string
+		attempts    int
+		preparation func(*mocks.JobStore, *mocks.Engine, *mocks.EngineRegistry)
+	}{
+		{
+			name:     "successful evaluation finishes the job",
+			attempts: 1,
+			preparation: func(store *mocks.JobStore, engine *mocks.Engine, _ *mocks.EngineRegistry) {
+				engine.On("Evaluate", mock.Anything, mock.Anything).Return(entities.Valuation{Value: 42}, nil).Once()
+				store.On("FinishJob", id, mock.Anything, entities.Valuation{Value: 42},
+					entities.EngineInfo{Name: "test", Version: "1"}).Return(nil).Once()
+			},
+		},
+		{
+			name:     "failed evaluation is retried with backoff",
+			attempts: 2,
+			preparation: func(store *mocks.JobStore, engine *mocks.Engine, _ *mocks.EngineRegistry) {
+				engine.On("Evaluate", mock.Anything, mock.Anything).Return(entities.Valuation{}, errEngine).Once()
+				store.On("RetryJob", id, mock.Anything,
+					mock.MatchedBy(func(e entities.JobError) bool {
+						return e.Attempt == 2 && e.Message == errEngine.Error()
+					}),
+					mock.MatchedBy(func(notBefore time.Time) bool {
+						return time.Until(notBefore) > time.Second && time.Until(notBefore) <= 2*time.Second
+					}),
+				).Return(nil).Once()
+			},
+		},
+		{
+			name:     "evaluation exceeding the engine's execution timeout is retried",
+			attempts: 1,
+			preparation: func(store *mocks.JobStore, engine *mocks.Engine, _ *mocks.EngineRegistry) {
+				engine.On("Evaluate", mock.Anything, mock.Anything).Return(
+					func(ctx context.Context, _ entities.EvaluationJob) (entities.Valuation, error) {
+						<-ctx.Done()
+
+						return entities.Valuation{}, ctx.Err()
+					},
+				).Once()
+				store.On("RetryJob", id, mock.Anything, mock.MatchedBy(func(e entities.JobError) bool {
+					return strings.HasPrefix(e.Message, worker.ErrExecutionTimeout.Error())
+				}), mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name:     "exhausted job is dead-lettered",
+			attempts: 3,
+			preparation: func(store *mocks.JobStore, engine *mocks.Engine, _ *mocks.EngineRegistry) {
+				engine.On("Evaluate", mock.Anything, mock.Anything).Return(entities.Valuation{}, errEngine).Once()
+				store.On("DeadLetterJob", id, mock.Anything, mock.MatchedBy(func(e entities.JobError) bool {
+					return e.Attempt == 3 && e.Message == errEngine.Error()
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:     "job for an unavailable engine is retried without evaluation",
+			attempts: 1,
+			preparation: func(store *mocks.JobStore, _ *mocks.Engine, engines *mocks.EngineRegistry) {
+				engines.On("Engine", entities.EngineInfo{}).Return(nil, errEngine).Once()
+				store.On("RetryJob", id, mock.Anything, mock.MatchedBy(func(e entities.JobError) bool {
+					return e.Message == errEngine.Error()
+				}), mock.Anything).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			store := mocks.NewJobStore(t)
+			engine := mocks.NewEngine(t)
+			engines := mocks.NewEngineRegistry(t)
+
+			store.On("NextJob", mock.Anything, mock.Anything).Return(entities.EvaluationJob{ID: id, Attempts: tc.attempts}, nil).Once()
+			store.On("NextJob", mock.Anything, mock.Anything).Return(entities.EvaluationJob{}, context.Canceled).Run(func(mock.Arguments) {
+				cancel()
+			}).Once()
+			engine.On("Name").Return("test").Maybe()
+			engine.On("Version").Return("1").Maybe()
+			tc.preparation(store, engine, engines)
+			engines.On("Engine", entities.EngineInfo{}).Return(engine, nil).Maybe()
+
+			w := worker.NewWorker(&config.WorkerConfig{
+				Count:             1,
+				MaxAttempts:       3,
+				InitialBackoff:    time.Second,
+				MaxBackoff:        time.Minute,
+				BackoffMultiplier: 2,
+				ExecutionTimeouts: map[string]time.Duration{
+					"test": 10 * time.Millisecond,
+				},
+			}, store, engines)
+
+			w.Run(ctx)
+		})
+	}
+}
+
+func TestWorker_Run_StoreFailure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		failures int
+		backoff  time.Duration
+		// wantGaps are the least times between fetching attempts
+		wantGaps []time.Duration
+	}{
+		{
+			name:     "backs off exponentially",
+			failures: 3,
+			backoff:  20 * time.Millisecond,
+			wantGaps: []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond},
+		},
+		{
+			name:     "waits without configured backoff",
+			failures: 2,
+			wantGaps: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			store := mocks.NewJobStore(t)
+
+			var fetches []time.Time
+
+			store.On("NextJob", mock.Anything, mock.Anything).Return(entities.EvaluationJob{}, errStore).
+				Run(func(mock.Arguments) { fetches = append(fetches, time.Now()) }).Times(tc.failures)
+			store.On("NextJob", mock.Anything, mock.Anything).Return(entities.EvaluationJob{}, context.Canceled).
+				Run(func(mock.Arguments) {
+					fetches = append(fetches, time.Now())
+
+					cancel()
+				}).Once()
+
+			worker.NewWorker(&config.WorkerConfig{
+				Count:             1,
+				InitialBackoff:    tc.backoff,
+				MaxBackoff:        time.Second,
+				BackoffMultiplier: 2,
+			}, store, mocks.NewEngineRegistry(t)).Run(ctx)
+
+			if assert.Len(t, fetches, len(tc.wantGaps)+1) {
+				for i, want := range tc.wantGaps {
+					assert.GreaterOrEqual(t, fetches[i+1].Sub(fetches[i]), want, "gap %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestWorker_Run_StopsWhileBackingOff(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := mocks.NewJobStore(t)
+	store.On("NextJob", mock.Anything, mock.Anything).Return(entities.EvaluationJob{}, errStore).
+		Run(func(mock.Arguments) { time.AfterFunc(10*time.Millisecond, cancel) }).Once()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		worker.NewWorker(&config.WorkerConfig{Count: 1, InitialBackoff: time.Hour}, store, mocks.NewEngineRegistry(t)).
+			Run(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not stop while backing off")
+	}
+}
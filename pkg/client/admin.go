@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const deadLettersPath = "/admin/dead-letters"
+
+// ListDeadLetterJobs lists the jobs that failed all attempts, it requires an operator identity.
+func (c *Client) ListDeadLetterJobs(ctx context.Context) ([]DeadLetterJob, error) {
+	return decode[[]DeadLetterJob](ctx, c, newRequest(http.MethodGet, deadLettersPath, nil))
+}
+
+func (c *Client) GetDeadLetterJob(ctx context.Context, id string) (*DeadLetterJob, error) {
+	return decode[*DeadLetterJob](ctx, c, newRequest(http.MethodGet, deadLettersPath+pathEscape(id), nil))
+}
+
+// RequeueDeadLetterJob queues a dead-letter job again with fresh attempts.
+func (c *Client) RequeueDeadLetterJob(ctx context.Context, id string) (*DeadLetterJob, error) {
+	return decode[*DeadLetterJob](ctx, c, newRequest(http.MethodPost, deadLettersPath+pathEscape(id, "requeue"), nil))
+}
+
+// Health checks that the server is up, it needs no credentials.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, newRequest(http.MethodGet, "/health", nil), nil)
+}
@@ -0,0 +1,327 @@
+// Package client is a Go client for the patAi API.
+//
+// Errors reported by the API are returned as *Error, which matches the sentinel errors of this package by errors.Is,
+// e.g. errors.Is(err, client.ErrJobNotFound). Requests rejected for exceeding the quota can be retried with
+// WithRetries, waiting as long as the Retry-After header of the rejection says.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRetryWait    = time.Second
+	defaultPollInterval = time.Second
+
+	apiKeyHeader         = "X-API-Key"
+	idempotencyKeyHeader = "Idempotency-Key"
+	acceptCurrencyHeader = "Accept-Currency"
+	retryAfterHeader     = "Retry-After"
+
+	mimeApplicationJSON = "application/json"
+)
+
+var ErrMissingBaseURL = errors.New("base URL is required")
+
+// Client calls the API below a base URL, e.g. https://patai.example.com/api/v0; it is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+
+	maxRetries   int
+	retryWait    time.Duration
+	pollInterval time.Duration
+}
+
+type Option func(*Client)
+
+// WithAPIKey authenticates all requests with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.header.Set(apiKeyHeader, key)
+	}
+}
+
+// WithBearerToken sends a JWT as bearer token, for deployments with a gateway authenticating by token in front of the
+// API.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to set timeouts or a transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithCurrency converts all valuations to a currency of the server's conversion table.
+func WithCurrency(code string) Option {
+	return func(c *Client) {
+		c.header.Set(acceptCurrencyHeader, code)
+	}
+}
+
+// WithRetries retries requests exceeding the quota up to maxRetries times, by default they are not retried. The client
+// waits as long as the Retry-After header says, without one it waits wait before the first retry and doubles it for
+// each further one. Zero maxRetries disables retries.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithPollInterval sets how often WaitForJob and WaitForBatch ask for the status.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+func New(baseURL string, options ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, ErrMissingBaseURL
+	}
+
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, errors.Wrap(err, "malformed base URL")
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		httpClient:   http.DefaultClient,
+		header:       http.Header{},
+		retryWait:    defaultRetryWait,
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// request is an API call, its path is relative to the base URL and escaped already; the body is kept in memory, so that
+// it can be sent again on retries.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	contentType string
+	body        []byte
+}
+
+func newRequest(method string, path string, query url.Values) *request {
+	return &request{method: method, path: path, query: query, header: http.Header{}}
+}
+
+func (r *request) withJSON(body any) (*request, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode request body")
+	}
+
+	return r.withBody(mimeApplicationJSON, encoded), nil
+}
+
+func (r *request) withBody(contentType string, body []byte) *request {
+	r.contentType = contentType
+	r.body = body
+
+	return r
+}
+
+func (r *request) withIdempotencyKey(key string) *request {
+	if key != "" {
+		r.header.Set(idempotencyKeyHeader, key)
+	}
+
+	return r
+}
+
+// pathEscape escapes a path parameter, so that e.g. patent keys may contain slashes.
+func pathEscape(segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+
+	return "/" + strings.Join(escaped, "/")
+}
+
+// do sends the request, retrying it while the quota is exceeded, and decodes a JSON response into result unless it
+// is nil.
+func (c *Client) do(ctx context.Context, r *request, result any) error {
+	response, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return errors.Wrapf(err, "cannot decode response of %s %s", r.method, r.path)
+	}
+
+	return nil
+}
+
+// decode sends the request and decodes the JSON response; it is no method, as methods cannot have type parameters.
+func decode[T any](ctx context.Context, c *Client, r *request) (T, error) {
+	var result T
+
+	if err := c.do(ctx, r, &result); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return result, nil
+}
+
+// Download is a file served by the API.
+type Download struct {
+	ContentType string
+	// FileName is the name suggested by the server, empty if it did not suggest one
+	FileName string
+	Content  []byte
+}
+
+func (c *Client) download(ctx context.Context, r *request) (*Download, error) {
+	response, err := c.send(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read response of %s %s", r.method, r.path)
+	}
+
+	download := &Download{ContentType: response.Header.Get("Content-Type"), Content: content}
+
+	if _, params, err := mime.ParseMediaType(response.Header.Get("Content-Disposition")); err == nil {
+		download.FileName = params["filename"]
+	}
+
+	return download, nil
+}
+
+// send returns successful responses, error responses are closed and returned as *Error.
+func (c *Client) send(ctx context.Context, r *request) (*http.Response, error) {
+	wait := c.retryWait
+
+	for attempt := 0; ; attempt++ {
+		response, err := c.sendOnce(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode < http.StatusBadRequest {
+			return response, nil
+		}
+
+		apiErr := readError(response)
+
+		if response.StatusCode != http.StatusTooManyRequests || attempt >= c.maxRetries {
+			return nil, apiErr
+		}
+
+		delay, ok := retryAfter(response.Header.Get(retryAfterHeader), time.Now())
+		if !ok {
+			delay = wait
+			wait *= 2
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, errors.Wrap(err, apiErr.Error())
+		}
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, r *request) (*http.Response, error) {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create request %s %s", r.method, r.path)
+	}
+
+	for name, values := range c.header {
+		httpRequest.Header[name] = values
+	}
+
+	for name, values := range r.header {
+		httpRequest.Header[name] = values
+	}
+
+	if r.contentType != "" {
+		httpRequest.Header.Set("Content-Type", r.contentType)
+	}
+
+	response, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", r.method, r.path)
+	}
+
+	return response, nil
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
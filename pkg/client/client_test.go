@@ -0,0 +1,491 @@
+//nolint:funlen // Test functions are long, due to test cases
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/config"
+	"github.com/MyChaOS87/patAi/internal/api/admin"
+	"github.com/MyChaOS87/patAi/internal/api/patents"
+	"github.com/MyChaOS87/patAi/internal/api/portfolios"
+	"github.com/MyChaOS87/patAi/internal/api/server"
+	"github.com/MyChaOS87/patAi/internal/authorization"
+	"github.com/MyChaOS87/patAi/internal/engines"
+	"github.com/MyChaOS87/patAi/internal/report"
+	"github.com/MyChaOS87/patAi/internal/similarity"
+	"github.com/MyChaOS87/patAi/internal/simulation"
+	"github.com/MyChaOS87/patAi/internal/worker"
+	"github.com/MyChaOS87/patAi/pkg/client"
+	"github.com/MyChaOS87/patAi/pkg/currency"
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+)
+
+const (
+	specFile       = "../../patAi.openapi3.yaml"
+	reportTemplate = "../../templates/report.html.tmpl"
+	currencyTable  = "../../config/currencies.yml"
+	pdfDocument    = "../../internal/pdftext/testdata/simple.pdf"
+	unknownID      = "0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11"
+	waitTimeout    = 5 * time.Second
+
+	// keys of the mock authorization provider
+	proKey      = "user2"
+	operatorKey = "operator"
+	freeKey     = "free"
+)
+
+// newTestServer serves the API like cmd/patAi does and returns its base URL.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := &config.Config{
+		API: config.APIConfig{
+			OpenAPIFile:       specFile,
+			IdempotencyKeyTTL: time.Minute,
+			MaxBatchSize:      10,
+			BodyLimit:         "256K",
+		},
+		Worker:      config.WorkerConfig{Count: 2, MaxAttempts: 1, DefaultExecutionTimeout: time.Second},
+		ResultCache: config.ResultCacheConfig{TTL: time.Hour},
+		Report:      config.ReportConfig{TemplateFile: reportTemplate},
+		Simulation:  config.SimulationConfig{EvaluationDuration: 10 * time.Millisecond},
+	}
+
+	document, err := openapi.LoadDocument(cfg.API.OpenAPIFile, struct{ ServerBaseURL string }{})
+	assert.NoError(t, err)
+
+	patentSchema, err := document.Schema("PatentSubmission")
+	assert.NoError(t, err)
+
+	currencies, err := currency.Load(currencyTable)
+	assert.NoError(t, err)
+
+	reports, err := report.NewRenderer(&cfg.Report)
+	assert.NoError(t, err)
+
+	backend := simulation.NewInMemoryQueueAndQuotaServiceSimulation()
+	registry := engines.NewRegistry(simulation.NewEngine(&cfg.Simulation), simulation.NewWeightedEngine(&cfg.Simulation))
+	identities := authorization.NewMockProvider()
+
+	useCase := patents.NewValuationJobUseCase(backend, backend,
+		patents.WithResultCache(backend, &cfg.ResultCache),
+		patents.WithBatches(backend, cfg.API.MaxBatchSize),
+		patents.WithEngines(registry),
+		patents.WithSimilarityIndex(similarity.NewIndex()),
+		patents.WithSearch(backend),
+	)
+
+	srv := server.NewServer(
+		server.API(&cfg.API),
+		server.ChildRouters(
+			patents.NewPatentsRouter(&cfg.API, identities,
+				patents.NewHandler(useCase, patentSchema, currencies, reports)),
+			portfolios.NewPortfoliosRouter(identities,
+				portfolios.NewHandler(portfolios.NewPortfolioUseCase(backend, backend, backend, currencies))),
+			admin.NewAdminRouter(identities, admin.NewHandler(admin.NewDeadLetterUseCase(backend))),
+		),
+		server.OpenAPIDocument(document),
+		server.ProblemTypes(patents.ProblemTypes()...),
+		server.ProblemTypes(portfolios.ProblemTypes()...),
+		server.ProblemTypes(admin.ProblemTypes()...),
+	)
+
+	handler, err := srv.Handler()
+	assert.NoError(t, err)
+
+	go worker.NewWorker(&cfg.Worker, backend, registry).Run(ctx)
+
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	return httpServer.URL + "/api/v0"
+}
+
+func newClient(t *testing.T, baseURL string, options ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(baseURL, append([]client.Option{client.WithPollInterval(10 * time.Millisecond)}, options...)...)
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestClient_Patents(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	c := newClient(t, newTestServer(t), client.WithAPIKey(proKey))
+
+	assert.NoError(t, c.Health(ctx))
+
+	engines, err := c.GetEngines(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, engines, 2)
+
+	created, err := c.SubmitPatent(ctx, client.PatentSubmission{
+		Title:             "Valuing patents",
+		Claims:            []string{"1. A method for valuing patents.", "2. The method of claim 1, done quickly."},
+		PublicationNumber: "EP1234567A1",
+	}, client.SubmitOptions{PatentKey: "EP/1234567", Labels: map[string]string{"team": "ip"}, IdempotencyKey: "first"})
+	assert.NoError(t, err)
+	assert.Equal(t, client.StatusPending, created.Status)
+
+	replayed, err := c.SubmitPatent(ctx, client.PatentSubmission{
+		Title:             "Valuing patents",
+		Claims:            []string{"1. A method for valuing patents.", "2. The method of claim 1, done quickly."},
+		PublicationNumber: "EP1234567A1",
+	}, client.SubmitOptions{PatentKey: "EP/1234567", Labels: map[string]string{"team": "ip"}, IdempotencyKey: "first"})
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, replayed.ID)
+
+	job, err := c.WaitForJob(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, client.StatusFinished, job.Status)
+	assert.NotNil(t, job.Valuation)
+
+	note := "reviewed"
+	job, err = c.UpdateJobMetadata(ctx, job.ID, client.JobMetadataPatch{Note: &note})
+	assert.NoError(t, err)
+	assert.Equal(t, "reviewed", job.Note)
+
+	jobs, err := c.ListJobs(ctx, client.JobFilter{Labels: map[string]string{"team": "ip"}})
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	analysis, err := c.GetClaims(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, analysis.Metrics.Claims)
+
+	report, err := c.GetJobReport(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Contains(t, report.ContentType, "text/html")
+
+	export, err := c.ExportJobs(ctx, client.ExportFormatCSV, []string{"id"}, client.JobFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "patents.csv", export.FileName)
+	assert.Equal(t, "id\n"+job.ID+"\n", string(export.Content))
+
+	amended, err := c.SubmitText(ctx, "1. A method for valuing granted patents.",
+		client.SubmitOptions{PatentKey: "EP/1234567"})
+	assert.NoError(t, err)
+
+	_, err = c.WaitForJob(ctx, amended.ID)
+	assert.NoError(t, err)
+
+	history, err := c.GetHistory(ctx, "EP/1234567")
+	assert.NoError(t, err)
+	assert.Len(t, history.Jobs, 2)
+
+	diff, err := c.GetDiff(ctx, "EP/1234567", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, diff.From.ID)
+	assert.Equal(t, amended.ID, diff.To.ID)
+
+	revaluation, err := c.RevalueJob(ctx, job.ID, client.RevalueOptions{EngineVersion: "2.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, revaluation.RevaluationOf)
+
+	revaluations, err := c.GetRevaluations(ctx, job.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, revaluations, 2) {
+		assert.Equal(t, revaluation.ID, revaluations[1].ID)
+	}
+
+	similar, err := c.GetSimilarPatents(ctx, job.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, similar, 1)
+
+	pdf, err := os.ReadFile(pdfDocument)
+	assert.NoError(t, err)
+
+	uploaded, err := c.SubmitDocument(ctx, client.DocumentUpload{FileName: "simple.pdf", Content: pdf, Title: "Upload"},
+		client.SubmitOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Upload", uploaded.Title)
+
+	document, err := c.GetJobDocument(ctx, uploaded.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "simple.pdf", document.FileName)
+	assert.Equal(t, pdf, document.Content)
+}
+
+func TestClient_BatchesAndPortfolios(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	c := newClient(t, newTestServer(t), client.WithAPIKey(proKey), client.WithCurrency("USD"))
+
+	batch, err := c.CreateBatch(ctx, []client.BatchItemRequest{
+		{Content: "A first patent", TechnicalField: "software"},
+		{Content: "A second patent", TechnicalField: "chemistry"},
+	}, client.BatchOptions{Mode: client.BatchModeBestEffort})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batch.Progress.Accepted)
+
+	batch, err = c.WaitForBatch(ctx, batch.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batch.Progress.Finished)
+	assert.Equal(t, "USD", batch.Items[0].Job.Valuation.Currency)
+
+	created, err := c.CreatePortfolio(ctx, client.CreatePortfolio{Name: "All", BatchIDs: []string{batch.ID}})
+	assert.NoError(t, err)
+
+	portfolio, err := c.GetPortfolio(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "All", portfolio.Name)
+
+	portfolios, err := c.ListPortfolios(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, portfolios, 1)
+
+	statistics, err := c.GetPortfolioStatistics(ctx, portfolio.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, statistics.Finished)
+	assert.Len(t, statistics.Top, 1)
+	assert.Len(t, statistics.ByTechnicalField, 2)
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	baseURL := newTestServer(t)
+
+	testCases := []struct {
+		name       string
+		key        string
+		call       func(ctx context.Context, c *client.Client) error
+		wantErrs   []error
+		wantFields []string
+	}{
+		{
+			name: "job not found", key: proKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.GetJob(ctx, unknownID)
+
+				return err
+			},
+			wantErrs: []error{client.ErrJobNotFound, client.ErrNotFound},
+		},
+		{
+			name: "malformed job ID", key: proKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.GetJob(ctx, "not-a-uuid")
+
+				return err
+			},
+			wantErrs: []error{client.ErrInvalidRequest}, wantFields: []string{"id"},
+		},
+		{
+			name: "invalid patent", key: proKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.SubmitPatent(ctx, client.PatentSubmission{Title: "No claims"}, client.SubmitOptions{})
+
+				return err
+			},
+			wantErrs: []error{client.ErrInvalidRequest}, wantFields: []string{"/claims"},
+		},
+		{
+			name: "missing credentials",
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.ListJobs(ctx, client.JobFilter{})
+
+				return err
+			},
+			wantErrs: []error{client.ErrUnauthorized},
+		},
+		{
+			name: "priority not allowed", key: freeKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.SubmitText(ctx, "A patent", client.SubmitOptions{Priority: client.PriorityHigh})
+
+				return err
+			},
+			wantErrs: []error{client.ErrPriorityNotAllowed},
+		},
+		{
+			name: "operator required", key: proKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.ListDeadLetterJobs(ctx)
+
+				return err
+			},
+			wantErrs: []error{client.ErrOperatorRequired},
+		},
+		{
+			name: "dead-letter job not found", key: operatorKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.RequeueDeadLetterJob(ctx, unknownID)
+
+				return err
+			},
+			wantErrs: []error{client.ErrDeadLetterJobNotFound, client.ErrNotFound},
+		},
+		{
+			name: "empty batch", key: proKey,
+			call: func(ctx context.Context, c *client.Client) error {
+				_, err := c.CreateBatch(ctx, []client.BatchItemRequest{}, client.BatchOptions{})
+
+				return err
+			},
+			wantErrs: []error{client.ErrInvalidRequest},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var options []client.Option
+			if tc.key != "" {
+				options = append(options, client.WithAPIKey(tc.key))
+			}
+
+			err := tc.call(context.Background(), newClient(t, baseURL, options...))
+
+			for _, want := range tc.wantErrs {
+				assert.ErrorIs(t, err, want)
+			}
+
+			var apiErr *client.Error
+			if !assert.ErrorAs(t, err, &apiErr) {
+				return
+			}
+
+			var fields []string
+			for _, field := range apiErr.Fields() {
+				fields = append(fields, field.Field)
+			}
+
+			assert.Equal(t, tc.wantFields, fields)
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	t.Parallel()
+
+	quotaExceeded := `{"type": "about:blank", "title": "Quota exceeded", "status": 429, "code": "quota-exceeded"}`
+
+	testCases := []struct {
+		name         string
+		retryAfter   string
+		rejections   int32
+		options      []client.Option
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name: "no retries by default", retryAfter: "0", rejections: 1, wantAttempts: 1, wantErr: client.ErrQuotaExceeded,
+		},
+		{
+			name: "retry after seconds", retryAfter: "0", rejections: 2, wantAttempts: 3,
+			options: []client.Option{client.WithRetries(2, time.Hour)},
+		},
+		{
+			name: "retry after date", retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+			rejections: 1, wantAttempts: 2, options: []client.Option{client.WithRetries(1, time.Hour)},
+		},
+		{
+			name: "backoff without retry after", rejections: 2, wantAttempts: 3,
+			options: []client.Option{client.WithRetries(2, time.Millisecond)},
+		},
+		{
+			name: "retries exhausted", retryAfter: "0", rejections: 3, wantAttempts: 2,
+			options: []client.Option{client.WithRetries(1, time.Millisecond)}, wantErr: client.ErrQuotaExceeded,
+		},
+		{
+			name: "retries disabled", retryAfter: "0", rejections: 1, wantAttempts: 1,
+			options: []client.Option{client.WithRetries(0, 0)}, wantErr: client.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+
+			stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "A patent", string(body), "the body is sent again on retries")
+
+				if attempts.Add(1) <= tc.rejections {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+
+					w.Header().Set("Content-Type", "application/problem+json")
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = w.Write([]byte(quotaExceeded))
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id": "` + unknownID + `", "status": "pending"}`))
+			}))
+			defer stub.Close()
+
+			job, err := newClient(t, stub.URL, tc.options...).SubmitText(context.Background(), "A patent",
+				client.SubmitOptions{})
+
+			assert.Equal(t, tc.wantAttempts, attempts.Load())
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, job)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, unknownID, job.ID)
+		})
+	}
+}
+
+func TestClient_WaitForJob(t *testing.T) {
+	t.Parallel()
+
+	var polls atomic.Int32
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if polls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"id": "` + unknownID + `", "status": "running"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id": "` + unknownID + `", "status": "failed", "error": "engine unavailable"}`))
+	}))
+	defer stub.Close()
+
+	job, err := newClient(t, stub.URL).WaitForJob(context.Background(), unknownID)
+
+	assert.ErrorIs(t, err, client.ErrJobFailed)
+	assert.ErrorContains(t, err, "engine unavailable")
+	assert.Equal(t, client.StatusFailed, job.Status)
+	assert.Equal(t, int32(3), polls.Load())
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// maxErrorBodySize bounds how much of an error response is read.
+const maxErrorBodySize = 1 << 20
+
+// The errors are matched by the problem code the server reports, they mirror the errors of the server's use cases.
+var (
+	ErrJobNotFound           = errors.New("job not found")
+	ErrDocumentNotFound      = errors.New("job was not submitted as a document")
+	ErrBatchNotFound         = errors.New("batch not found")
+	ErrPatentNotFound        = errors.New("patent not found")
+	ErrNoPreviousSubmission  = errors.New("patent has no previous submission")
+	ErrPortfolioNotFound     = errors.New("portfolio not found")
+	ErrDeadLetterJobNotFound = errors.New("dead-letter job not found")
+
+	ErrQuotaExceeded      = errors.New("quota exceeded")
+	ErrPriorityNotAllowed = errors.New("priority not allowed by plan")
+	ErrSameEngine         = errors.New("job was already valued by this engine")
+	ErrOperatorRequired   = errors.New("operator privileges required")
+
+	ErrBatchesDisabled    = errors.New("batches are disabled")
+	ErrSimilarityDisabled = errors.New("similarity search is disabled")
+	ErrSearchDisabled     = errors.New("full-text search is disabled")
+	ErrReportsDisabled    = errors.New("reports are disabled")
+
+	ErrUnsupportedPatentDocument = errors.New("unsupported patent document")
+	ErrNotAcceptable             = errors.New("export format not acceptable")
+
+	ErrEmptyBatch       = errors.New("batch is empty")
+	ErrBatchTooLarge    = errors.New("batch too large")
+	ErrUnknownEngine    = errors.New("unknown engine")
+	ErrInvalidMetadata  = errors.New("invalid job metadata")
+	ErrInvalidQuery     = errors.New("invalid search query")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidPortfolio = errors.New("invalid portfolio")
+	ErrUnknownMember    = errors.New("unknown portfolio member")
+)
+
+// The errors are matched by HTTP status, whatever the problem code.
+var (
+	// ErrInvalidRequest matches all bad requests, including those matching a more specific error
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrUnauthorized         = errors.New("missing or invalid credentials")
+	ErrNotFound             = errors.New("not found")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrRequestTooLarge      = errors.New("request too large")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+	ErrNotImplemented       = errors.New("feature is disabled on the server")
+	ErrServer               = errors.New("server error")
+)
+
+//nolint:gochecknoglobals // lookup table
+var errorsByCode = map[string]error{
+	"job-not-found":               ErrJobNotFound,
+	"document-not-found":          ErrDocumentNotFound,
+	"batch-not-found":             ErrBatchNotFound,
+	"patent-not-found":            ErrPatentNotFound,
+	"no-previous-submission":      ErrNoPreviousSubmission,
+	"portfolio-not-found":         ErrPortfolioNotFound,
+	"dead-letter-job-not-found":   ErrDeadLetterJobNotFound,
+	"quota-exceeded":              ErrQuotaExceeded,
+	"priority-not-allowed":        ErrPriorityNotAllowed,
+	"same-engine":                 ErrSameEngine,
+	"operator-required":           ErrOperatorRequired,
+	"batches-disabled":            ErrBatchesDisabled,
+	"similarity-disabled":         ErrSimilarityDisabled,
+	"search-disabled":             ErrSearchDisabled,
+	"reports-disabled":            ErrReportsDisabled,
+	"unsupported-patent-document": ErrUnsupportedPatentDocument,
+	"not-acceptable":              ErrNotAcceptable,
+	"empty-batch":                 ErrEmptyBatch,
+	"batch-too-large":             ErrBatchTooLarge,
+	"unknown-engine":              ErrUnknownEngine,
+	"invalid-metadata":            ErrInvalidMetadata,
+	"invalid-query":               ErrInvalidQuery,
+	"unknown-currency":            ErrUnknownCurrency,
+	"invalid-portfolio":           ErrInvalidPortfolio,
+	"unknown-portfolio-member":    ErrUnknownMember,
+}
+
+//nolint:gochecknoglobals // lookup table
+var errorsByStatus = map[int]error{
+	http.StatusBadRequest:            ErrInvalidRequest,
+	http.StatusUnauthorized:          ErrUnauthorized,
+	http.StatusNotFound:              ErrNotFound,
+	http.StatusUnsupportedMediaType:  ErrUnsupportedMediaType,
+	http.StatusRequestEntityTooLarge: ErrRequestTooLarge,
+	http.StatusUnprocessableEntity:   ErrIdempotencyKeyReused,
+	http.StatusNotImplemented:        ErrNotImplemented,
+}
+
+// Error is an error response of the API, described by its problem details.
+type Error struct {
+	StatusCode int
+	Problem    problem.Details
+}
+
+func (e *Error) Error() string {
+	message := e.Problem.Title
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	if e.Problem.Detail != "" {
+		message += ": " + e.Problem.Detail
+	}
+
+	return message
+}
+
+// Is matches the error of the problem code and the error of the HTTP status.
+func (e *Error) Is(target error) bool {
+	if err, ok := errorsByCode[e.Problem.Code]; ok && errors.Is(err, target) {
+		return true
+	}
+
+	if e.StatusCode >= http.StatusInternalServerError {
+		return errors.Is(ErrServer, target)
+	}
+
+	err, ok := errorsByStatus[e.StatusCode]
+
+	return ok && errors.Is(err, target)
+}
+
+// Fields lists the invalid fields of a bad request, located by parameter name or JSON pointer into the body.
+func (e *Error) Fields() []problem.FieldError {
+	return e.Problem.Errors
+}
+
+// readError closes the response, bodies that are no problem details are kept as detail.
+func readError(response *http.Response) *Error {
+	defer response.Body.Close()
+
+	apiErr := &Error{StatusCode: response.StatusCode}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	if err != nil {
+		return apiErr
+	}
+
+	if json.Unmarshal(body, &apiErr.Problem) != nil {
+		apiErr.Problem = problem.Details{Status: response.StatusCode, Detail: strings.TrimSpace(string(body))}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	patentsPath   = "/patents"
+	batchesPath   = "/batches"
+	enginesPath   = "/engines"
+	historiesPath = "/histories"
+)
+
+// JobFilter selects jobs by labels and tags, a label with empty value matches all values.
+type JobFilter struct {
+	// Query restricts the jobs to those matching a full-text query, results are ranked by relevance
+	Query  string
+	Labels map[string]string
+	Tags   []string
+}
+
+func (f JobFilter) values() url.Values {
+	query := url.Values{}
+
+	if f.Query != "" {
+		query.Set("q", f.Query)
+	}
+
+	addMetadata(query, f.Labels, f.Tags)
+
+	return query
+}
+
+func addMetadata(query url.Values, labels map[string]string, tags []string) {
+	for key, value := range labels {
+		if value == "" {
+			query.Add("label", key)
+		} else {
+			query.Add("label", key+":"+value)
+		}
+	}
+
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+}
+
+// SubmitOptions are the options of a job submission, all of them are optional.
+type SubmitOptions struct {
+	Priority string
+	// Fresh values the patent again even if a cached result exists
+	Fresh          bool
+	TechnicalField string
+	Engine         string
+	EngineVersion  string
+	// PatentKey links the job to a logical patent across amended submissions
+	PatentKey string
+	Labels    map[string]string
+	Tags      []string
+	Note      string
+	// IdempotencyKey makes retries of the submission return the job created first instead of creating another one
+	IdempotencyKey string
+}
+
+func (o SubmitOptions) values() url.Values {
+	query := url.Values{}
+
+	for name, value := range map[string]string{
+		"priority": o.Priority, "technicalField": o.TechnicalField, "engine": o.Engine,
+		"engineVersion": o.EngineVersion, "patentKey": o.PatentKey, "note": o.Note,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	if o.Fresh {
+		query.Set("fresh", "true")
+	}
+
+	addMetadata(query, o.Labels, o.Tags)
+
+	return query
+}
+
+// DocumentUpload is a patent as PDF file, the metadata takes precedence over what is recognized in the text.
+type DocumentUpload struct {
+	FileName          string
+	Content           []byte
+	Title             string
+	PublicationNumber string
+	// PriorityDate is formatted YYYY-MM-DD
+	PriorityDate string
+	Jurisdiction string
+	CPCClasses   []string
+	IPCClasses   []string
+}
+
+func (u DocumentUpload) encode() (string, []byte, error) {
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	file, err := writer.CreateFormFile("file", u.FileName)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "cannot create upload")
+	}
+
+	if _, err := file.Write(u.Content); err != nil {
+		return "", nil, errors.Wrap(err, "cannot write upload")
+	}
+
+	for name, value := range map[string]string{
+		"title": u.Title, "publicationNumber": u.PublicationNumber, "priorityDate": u.PriorityDate,
+		"jurisdiction": u.Jurisdiction, "cpcClasses": strings.Join(u.CPCClasses, ","),
+		"ipcClasses": strings.Join(u.IPCClasses, ","),
+	} {
+		if value == "" {
+			continue
+		}
+
+		if err := writer.WriteField(name, value); err != nil {
+			return "", nil, errors.Wrap(err, "cannot write upload")
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", nil, errors.Wrap(err, "cannot write upload")
+	}
+
+	return writer.FormDataContentType(), body.Bytes(), nil
+}
+
+// ListJobs lists the jobs of the identity.
+func (c *Client) ListJobs(ctx context.Context, filter JobFilter) ([]Job, error) {
+	return decode[[]Job](ctx, c, newRequest(http.MethodGet, patentsPath, filter.values()))
+}
+
+// ExportJobs downloads the jobs of the identity in a format, e.g. ExportFormatCSV; columns select and order the
+// exported columns, nil exports the default columns.
+func (c *Client) ExportJobs(ctx context.Context, format string, columns []string, filter JobFilter) (*Download, error) {
+	query := filter.values()
+
+	if format != "" {
+		query.Set("format", format)
+	}
+
+	if len(columns) > 0 {
+		query.Set("columns", strings.Join(columns, ","))
+	}
+
+	return c.download(ctx, newRequest(http.MethodGet, patentsPath+"/export", query))
+}
+
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	return decode[*Job](ctx, c, newRequest(http.MethodGet, patentsPath+pathEscape(id), nil))
+}
+
+// SubmitText values a patent given as plain text.
+func (c *Client) SubmitText(ctx context.Context, content string, options SubmitOptions) (*Job, error) {
+	r := newRequest(http.MethodPost, patentsPath, options.values()).
+		withBody("text/plain; charset=utf-8", []byte(content)).
+		withIdempotencyKey(options.IdempotencyKey)
+
+	return decode[*Job](ctx, c, r)
+}
+
+// SubmitPatent values a structured patent.
+func (c *Client) SubmitPatent(ctx context.Context, patent PatentSubmission, options SubmitOptions) (*Job, error) {
+	r, err := newRequest(http.MethodPost, patentsPath, options.values()).withJSON(patent)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode[*Job](ctx, c, r.withIdempotencyKey(options.IdempotencyKey))
+}
+
+// SubmitXML values a patent given as USPTO or EPO XML document.
+func (c *Client) SubmitXML(ctx context.Context, document []byte, options SubmitOptions) (*Job, error) {
+	r := newRequest(http.MethodPost, patentsPath, options.values()).
+		withBody("application/xml", document).
+		withIdempotencyKey(options.IdempotencyKey)
+
+	return decode[*Job](ctx, c, r)
+}
+
+// SubmitDocument values a patent uploaded as PDF file.
+func (c *Client) SubmitDocument(ctx context.Context, upload DocumentUpload, options SubmitOptions) (*Job, error) {
+	contentType, body, err := upload.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRequest(http.MethodPost, patentsPath, options.values()).
+		withBody(contentType, body).
+		withIdempotencyKey(options.IdempotencyKey)
+
+	return decode[*Job](ctx, c, r)
+}
+
+// UpdateJobMetadata changes the labels, tags and note of a job.
+func (c *Client) UpdateJobMetadata(ctx context.Context, id string, patch JobMetadataPatch) (*Job, error) {
+	r, err := newRequest(http.MethodPatch, patentsPath+pathEscape(id), nil).withJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode[*Job](ctx, c, r)
+}
+
+// RevalueOptions select the engine of a revaluation, all of them are optional.
+type RevalueOptions struct {
+	Priority       string
+	Engine         string
+	EngineVersion  string
+	IdempotencyKey string
+}
+
+// RevalueJob values the patent of a finished job again, typically with a newer engine version.
+func (c *Client) RevalueJob(ctx context.Context, id string, options RevalueOptions) (*Job, error) {
+	query := url.Values{}
+
+	for name, value := range map[string]string{
+		"priority": options.Priority, "engine": options.Engine, "engineVersion": options.EngineVersion,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	r := newRequest(http.MethodPost, patentsPath+pathEscape(id, "revalue"), query).
+		withIdempotencyKey(options.IdempotencyKey)
+
+	return decode[*Job](ctx, c, r)
+}
+
+// GetRevaluations lists the jobs valuing the patent of a job again.
+func (c *Client) GetRevaluations(ctx context.Context, id string) ([]Job, error) {
+	return decode[[]Job](ctx, c, newRequest(http.MethodGet, patentsPath+pathEscape(id, "revaluations"), nil))
+}
+
+// GetJobDocument downloads the file a job was submitted as.
+func (c *Client) GetJobDocument(ctx context.Context, id string) (*Download, error) {
+	return c.download(ctx, newRequest(http.MethodGet, patentsPath+pathEscape(id, "document"), nil))
+}
+
+// GetJobReport downloads the printable HTML report of a job.
+func (c *Client) GetJobReport(ctx context.Context, id string) (*Download, error) {
+	return c.download(ctx, newRequest(http.MethodGet, patentsPath+pathEscape(id, "report"), nil))
+}
+
+func (c *Client) GetClaims(ctx context.Context, id string) (*ClaimAnalysis, error) {
+	return decode[*ClaimAnalysis](ctx, c, newRequest(http.MethodGet, patentsPath+pathEscape(id, "claims"), nil))
+}
+
+// GetSimilarPatents lists the most similar patents of the identity, zero limit leaves the limit to the server.
+func (c *Client) GetSimilarPatents(ctx context.Context, id string, limit int) ([]SimilarPatent, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	return decode[[]SimilarPatent](ctx, c, newRequest(http.MethodGet, patentsPath+pathEscape(id, "similar"), query))
+}
+
+// BatchOptions are the options of a batch submission, all of them are optional.
+type BatchOptions struct {
+	Mode           string
+	IdempotencyKey string
+}
+
+// CreateBatch submits several patents at once.
+func (c *Client) CreateBatch(ctx context.Context, items []BatchItemRequest, options BatchOptions) (*Batch, error) {
+	query := url.Values{}
+	if options.Mode != "" {
+		query.Set("mode", options.Mode)
+	}
+
+	r, err := newRequest(http.MethodPost, patentsPath+"/batch", query).withJSON(items)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode[*Batch](ctx, c, r.withIdempotencyKey(options.IdempotencyKey))
+}
+
+func (c *Client) GetBatch(ctx context.Context, id string) (*Batch, error) {
+	return decode[*Batch](ctx, c, newRequest(http.MethodGet, batchesPath+pathEscape(id), nil))
+}
+
+// GetEngines lists the engines jobs can be created for.
+func (c *Client) GetEngines(ctx context.Context) ([]AvailableEngine, error) {
+	return decode[[]AvailableEngine](ctx, c, newRequest(http.MethodGet, enginesPath, nil))
+}
+
+// GetHistory lists the jobs of a logical patent, oldest first.
+func (c *Client) GetHistory(ctx context.Context, patentKey string) (*History, error) {
+	return decode[*History](ctx, c, newRequest(http.MethodGet, historiesPath+pathEscape(patentKey), nil))
+}
+
+// GetDiff compares two jobs of a logical patent, empty IDs compare the latest submission with the previous one.
+func (c *Client) GetDiff(ctx context.Context, patentKey string, fromID string, toID string) (*PatentDiff, error) {
+	query := url.Values{}
+
+	if fromID != "" {
+		query.Set("from", fromID)
+	}
+
+	if toID != "" {
+		query.Set("to", toID)
+	}
+
+	return decode[*PatentDiff](ctx, c, newRequest(http.MethodGet, historiesPath+pathEscape(patentKey, "diff"), query))
+}
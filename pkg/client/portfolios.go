@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const portfoliosPath = "/portfolios"
+
+func (c *Client) ListPortfolios(ctx context.Context) ([]Portfolio, error) {
+	return decode[[]Portfolio](ctx, c, newRequest(http.MethodGet, portfoliosPath, nil))
+}
+
+// CreatePortfolio groups jobs and the jobs of batches of the identity.
+func (c *Client) CreatePortfolio(ctx context.Context, portfolio CreatePortfolio) (*Portfolio, error) {
+	r, err := newRequest(http.MethodPost, portfoliosPath, nil).withJSON(portfolio)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode[*Portfolio](ctx, c, r)
+}
+
+func (c *Client) GetPortfolio(ctx context.Context, id string) (*Portfolio, error) {
+	return decode[*Portfolio](ctx, c, newRequest(http.MethodGet, portfoliosPath+pathEscape(id), nil))
+}
+
+// GetPortfolioStatistics aggregates the jobs of a portfolio, top is the number of most valuable jobs listed, zero
+// leaves it to the server.
+func (c *Client) GetPortfolioStatistics(ctx context.Context, id string, top int) (*PortfolioStatistics, error) {
+	query := url.Values{}
+	if top > 0 {
+		query.Set("top", strconv.Itoa(top))
+	}
+
+	return decode[*PortfolioStatistics](ctx, c,
+		newRequest(http.MethodGet, portfoliosPath+pathEscape(id, "statistics"), query))
+}
@@ -0,0 +1,320 @@
+package client
+
+import "time"
+
+// Job statuses, a job is settled once finished or failed.
+const (
+	StatusPending  = "pending"
+	StatusRunning  = "running"
+	StatusFinished = "finished"
+	StatusFailed   = "failed"
+)
+
+// Job priorities, the plan of the identity bounds which ones it may use.
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// Batch modes, atomic batches are rejected as a whole if any item is, best-effort batches accept what they can.
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best-effort"
+)
+
+// Export formats.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+// Job is a valuation job, see the Patent schema.
+type Job struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Priority  string    `json:"priority"`
+	CreatedAt time.Time `json:"createdAt"`
+	BatchID   string    `json:"batchId,omitempty"`
+	// PatentKey links the job to a logical patent across amended submissions
+	PatentKey string `json:"patentKey,omitempty"`
+	// TechnicalField is empty for unclassified patents
+	TechnicalField string `json:"technicalField,omitempty"`
+	// Title and PublicationNumber are only known for structured submissions
+	Title             string `json:"title,omitempty"`
+	PublicationNumber string `json:"publicationNumber,omitempty"`
+	// Document describes the uploaded file, its content is served by GetJobDocument
+	Document *Document `json:"document,omitempty"`
+	// Engine is the engine chosen at creation, or the one that valued the job once finished
+	Engine *Engine `json:"engine,omitempty"`
+	// RevaluationOf is the job this one values again with another engine
+	RevaluationOf string            `json:"revaluationOf,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Note          string            `json:"note,omitempty"`
+	// Valuation is the monetary result, only present for finished jobs
+	Valuation *Valuation `json:"valuation,omitempty"`
+	// Explanation justifies the value, only present for finished jobs
+	Explanation *Explanation `json:"explanation,omitempty"`
+	Cached      bool         `json:"cached,omitempty"`
+	// Error is the reason of failed jobs
+	Error string `json:"error,omitempty"`
+	// Search explains why the job matched a full-text query, only present in search results
+	Search *SearchMatch `json:"search,omitempty"`
+}
+
+// Settled reports whether the job is finished or failed.
+func (j *Job) Settled() bool {
+	return j.Status == StatusFinished || j.Status == StatusFailed
+}
+
+type Document struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+type Engine struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AvailableEngine is an engine jobs can be created for.
+type AvailableEngine struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Default marks the engine used if none is requested
+	Default bool `json:"default"`
+}
+
+type Valuation struct {
+	Currency string  `json:"currency"`
+	Low      float64 `json:"low"`
+	Expected float64 `json:"expected"`
+	High     float64 `json:"high"`
+	// Date is the day the patent was valued, YYYY-MM-DD
+	Date string `json:"date"`
+}
+
+type Explanation struct {
+	Engine  Engine            `json:"engine"`
+	Factors []ValuationFactor `json:"factors"`
+	// Confidence is absent if the engine gave no confidence interval
+	Confidence *ConfidenceInterval `json:"confidence,omitempty"`
+}
+
+type ValuationFactor struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Weight      float64 `json:"weight"`
+	Score       float64 `json:"score"`
+}
+
+type ConfidenceInterval struct {
+	Level float64 `json:"level"`
+	// Currency is the ISO 4217 code of the bounds, the same as that of the valuation
+	Currency string `json:"currency"`
+	Lower    int    `json:"lower"`
+	Upper    int    `json:"upper"`
+}
+
+type SearchMatch struct {
+	Score    float64   `json:"score"`
+	Snippets []Snippet `json:"snippets"`
+}
+
+type Snippet struct {
+	Field string `json:"field"`
+	// Text is HTML with the matching terms enclosed in <mark> elements
+	Text string `json:"text"`
+}
+
+type SimilarPatent struct {
+	// Score is the similarity from 0 (nothing in common) to 1 (same terms)
+	Score float64 `json:"score"`
+	Job   Job     `json:"job"`
+}
+
+// PatentSubmission is a structured patent, see the PatentSubmission schema.
+type PatentSubmission struct {
+	Title             string   `json:"title"`
+	Abstract          string   `json:"abstract,omitempty"`
+	Claims            []string `json:"claims"`
+	Description       string   `json:"description,omitempty"`
+	PublicationNumber string   `json:"publicationNumber,omitempty"`
+	// PriorityDate is formatted YYYY-MM-DD
+	PriorityDate    string   `json:"priorityDate,omitempty"`
+	CPCClasses      []string `json:"cpcClasses,omitempty"`
+	IPCClasses      []string `json:"ipcClasses,omitempty"`
+	CitedReferences []string `json:"citedReferences,omitempty"`
+	Jurisdiction    string   `json:"jurisdiction,omitempty"`
+}
+
+// JobMetadataPatch changes the metadata of a job, nil fields are left as they are.
+type JobMetadataPatch struct {
+	// Labels are merged into the existing labels, labels set to nil are removed
+	Labels map[string]*string `json:"labels,omitempty"`
+	// Tags replace the existing tags
+	Tags *[]string `json:"tags,omitempty"`
+	// Note replaces the existing note, an empty note removes it
+	Note *string `json:"note,omitempty"`
+}
+
+type ClaimAnalysis struct {
+	// Claims holds the independent claims, dependent claims are nested below the first claim they refer to
+	Claims  []ClaimNode  `json:"claims"`
+	Metrics ClaimMetrics `json:"metrics"`
+}
+
+type ClaimNode struct {
+	Number      int         `json:"number"`
+	Text        string      `json:"text"`
+	Independent bool        `json:"independent"`
+	Category    string      `json:"category"`
+	DependsOn   []int       `json:"dependsOn,omitempty"`
+	Children    []ClaimNode `json:"children,omitempty"`
+}
+
+type ClaimMetrics struct {
+	Claims                        int `json:"claims"`
+	IndependentClaims             int `json:"independentClaims"`
+	DependentClaims               int `json:"dependentClaims"`
+	Breadth                       int `json:"breadth"`
+	Depth                         int `json:"depth"`
+	ShortestIndependentClaimWords int `json:"shortestIndependentClaimWords"`
+	Categories                    int `json:"categories"`
+}
+
+type History struct {
+	PatentKey string `json:"patentKey"`
+	Jobs      []Job  `json:"jobs"`
+}
+
+type PatentDiff struct {
+	PatentKey string `json:"patentKey"`
+	From      Job    `json:"from"`
+	To        Job    `json:"to"`
+	// Value is only present if both jobs are finished
+	Value  *ValueChange `json:"value,omitempty"`
+	Claims ClaimDiff    `json:"claims"`
+}
+
+type ValueChange struct {
+	Currency string  `json:"currency"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Change   float64 `json:"change"`
+	// ChangePercent is relative to From, absent if From is zero
+	ChangePercent *float64 `json:"changePercent,omitempty"`
+}
+
+type ClaimDiff struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Amended   int `json:"amended"`
+	Unchanged int `json:"unchanged"`
+	// Changes lists the added, removed and amended claims
+	Changes []ClaimChange `json:"changes"`
+}
+
+type ClaimChange struct {
+	Number int    `json:"number"`
+	Change string `json:"change"`
+	// Before is absent for added and After for removed claims
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// BatchItemRequest is a single patent of a batch submission.
+type BatchItemRequest struct {
+	Content        string            `json:"content"`
+	Priority       string            `json:"priority,omitempty"`
+	Fresh          bool              `json:"fresh,omitempty"`
+	TechnicalField string            `json:"technicalField,omitempty"`
+	Engine         string            `json:"engine,omitempty"`
+	EngineVersion  string            `json:"engineVersion,omitempty"`
+	PatentKey      string            `json:"patentKey,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Note           string            `json:"note,omitempty"`
+}
+
+type Batch struct {
+	ID        string        `json:"id"`
+	Mode      string        `json:"mode"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Progress  BatchProgress `json:"progress"`
+	Items     []BatchItem   `json:"items"`
+}
+
+type BatchProgress struct {
+	Total    int `json:"total"`
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	Pending  int `json:"pending"`
+	Running  int `json:"running"`
+	Finished int `json:"finished"`
+	Failed   int `json:"failed"`
+	// Done reports whether all accepted jobs are finished or failed
+	Done bool `json:"done"`
+}
+
+// BatchItem is the job created for an item, or the reason the item was rejected.
+type BatchItem struct {
+	Index int    `json:"index"`
+	Job   *Job   `json:"job,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type CreatePortfolio struct {
+	Name     string   `json:"name"`
+	JobIDs   []string `json:"jobIds,omitempty"`
+	BatchIDs []string `json:"batchIds,omitempty"`
+}
+
+type Portfolio struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	JobIDs    []string  `json:"jobIds"`
+	BatchIDs  []string  `json:"batchIds"`
+}
+
+type PortfolioStatistics struct {
+	Jobs     int `json:"jobs"`
+	Pending  int `json:"pending"`
+	Running  int `json:"running"`
+	Finished int `json:"finished"`
+	Failed   int `json:"failed"`
+
+	// Currency is the ISO 4217 code of all values, including the valuations of the top jobs
+	Currency   string  `json:"currency"`
+	TotalValue int     `json:"totalValue"`
+	MeanValue  float64 `json:"meanValue"`
+	// Percentiles are keyed p10, p25, ..., absent without finished jobs
+	Percentiles      map[string]float64 `json:"percentiles,omitempty"`
+	ByTechnicalField []FieldStatistics  `json:"byTechnicalField"`
+	Top              []Job              `json:"top"`
+}
+
+type FieldStatistics struct {
+	// TechnicalField is empty for unclassified patents
+	TechnicalField string  `json:"technicalField"`
+	Count          int     `json:"count"`
+	TotalValue     int     `json:"totalValue"`
+	MeanValue      float64 `json:"meanValue"`
+}
+
+type DeadLetterJob struct {
+	ID       string     `json:"id"`
+	OwnerID  string     `json:"ownerId"`
+	Attempts int        `json:"attempts"`
+	Errors   []JobError `json:"errors"`
+}
+
+type JobError struct {
+	Attempt    int       `json:"attempt"`
+	Message    string    `json:"message"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
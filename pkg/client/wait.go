@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var ErrJobFailed = errors.New("job failed")
+
+// WaitForJob polls a job until it is finished or failed, or the context ends. A failed job is returned along with an
+// ErrJobFailed error giving the reason.
+func (c *Client) WaitForJob(ctx context.Context, id string) (*Job, error) {
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if job.Status == StatusFailed {
+			return job, errors.Wrap(ErrJobFailed, job.Error)
+		}
+
+		if job.Settled() {
+			return job, nil
+		}
+
+		if err := sleep(ctx, c.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WaitForBatch polls a batch until all its accepted jobs are finished or failed, or the context ends.
+func (c *Client) WaitForBatch(ctx context.Context, id string) (*Batch, error) {
+	for {
+		batch, err := c.GetBatch(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if batch.Progress.Done {
+			return batch, nil
+		}
+
+		if err := sleep(ctx, c.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package currency converts amounts between currencies with a static table of exchange rates.
+package currency
+
+import (
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidTable    = errors.New("invalid currency table")
+
+	isoCode = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Table holds exchange rates relative to a base currency.
+type Table struct {
+	base string
+	// rates are the amounts of each currency worth one unit of the base currency
+	rates map[string]float64
+}
+
+type tableFile struct {
+	Base  string             `yaml:"base"`
+	Rates map[string]float64 `yaml:"rates"`
+}
+
+// NewTable checks the ISO 4217 codes and rates, the base currency is added with rate 1 if missing.
+func NewTable(base string, rates map[string]float64) (*Table, error) {
+	if !isoCode.MatchString(base) {
+		return nil, errors.Wrapf(ErrInvalidTable, "base currency %q is no ISO 4217 code", base)
+	}
+
+	t := &Table{base: base, rates: map[string]float64{base: 1}}
+
+	for code, rate := range rates {
+		if !isoCode.MatchString(code) {
+			return nil, errors.Wrapf(ErrInvalidTable, "currency %q is no ISO 4217 code", code)
+		}
+
+		if rate <= 0 || (code == base && rate != 1) {
+			return nil, errors.Wrapf(ErrInvalidTable, "invalid rate %v for %s", rate, code)
+		}
+
+		t.rates[code] = rate
+	}
+
+	return t, nil
+}
+
+// Load reads a YAML table like
+//
+//	base: EUR
+//	rates:
+//	  USD: 1.08
+func Load(file string) (*Table, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read currency table")
+	}
+
+	var parsed tableFile
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, errors.Wrap(ErrInvalidTable, err.Error())
+	}
+
+	return NewTable(parsed.Base, parsed.Rates)
+}
+
+func (t *Table) Base() string {
+	return t.base
+}
+
+// Normalize returns the upper case code of a known currency, or an ErrUnknownCurrency error.
+func (t *Table) Normalize(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := t.rates[normalized]; !ok {
+		return "", errors.Wrap(ErrUnknownCurrency, code)
+	}
+
+	return normalized, nil
+}
+
+// Convert converts amount from one currency to another, rounded to hundredths.
+func (t *Table) Convert(amount float64, from string, to string) (float64, error) {
+	fromRate, ok := t.rates[from]
+	if !ok {
+		return 0, errors.Wrap(ErrUnknownCurrency, from)
+	}
+
+	toRate, ok := t.rates[to]
+	if !ok {
+		return 0, errors.Wrap(ErrUnknownCurrency, to)
+	}
+
+	return math.Round(amount/fromRate*toRate*100) / 100, nil //nolint:gomnd // hundredths
+}
@@ -0,0 +1,89 @@
+//nolint:funlen // Test functions are long, due to test cases
+package currency_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/currency"
+)
+
+func TestTable_Convert(t *testing.T) {
+	t.Parallel()
+
+	table, err := currency.NewTable("EUR", map[string]float64{"USD": 1.25, "JPY": 160})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name    string
+		amount  float64
+		from    string
+		to      string
+		want    float64
+		wantErr error
+	}{
+		{name: "same currency", amount: 42, from: "EUR", to: "EUR", want: 42},
+		{name: "from base", amount: 42, from: "EUR", to: "USD", want: 52.5},
+		{name: "to base", amount: 52.5, from: "USD", to: "EUR", want: 42},
+		{name: "cross rate", amount: 10, from: "USD", to: "JPY", want: 1280},
+		{name: "rounded to hundredths", amount: 1, from: "JPY", to: "USD", want: 0.01},
+		{name: "unknown target", amount: 1, from: "EUR", to: "CHF", wantErr: currency.ErrUnknownCurrency},
+		{name: "unknown source", amount: 1, from: "CHF", to: "EUR", wantErr: currency.ErrUnknownCurrency},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := table.Convert(tc.amount, tc.from, tc.to)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.InDelta(t, tc.want, got, 1e-9)
+		})
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		base    string
+		rates   map[string]float64
+		wantErr error
+	}{
+		{name: "valid", base: "EUR", rates: map[string]float64{"EUR": 1, "USD": 1.08}},
+		{name: "malformed base", base: "euro", wantErr: currency.ErrInvalidTable},
+		{name: "malformed code", base: "EUR", rates: map[string]float64{"usd": 1.08}, wantErr: currency.ErrInvalidTable},
+		{name: "negative rate", base: "EUR", rates: map[string]float64{"USD": -1}, wantErr: currency.ErrInvalidTable},
+		{name: "base rate not 1", base: "EUR", rates: map[string]float64{"EUR": 2}, wantErr: currency.ErrInvalidTable},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := currency.NewTable(tc.base, tc.rates)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	table, err := currency.Load("../../config/currencies.yml")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "EUR", table.Base())
+
+	code, err := table.Normalize(" usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", code)
+}
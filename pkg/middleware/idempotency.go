@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/log"
+)
+
+const (
+	idempotencyKeyHeaderField     = "Idempotency-Key"
+	idempotentReplayedHeaderField = "Idempotent-Replayed"
+	maxIdempotencyKeyLength       = 255
+	idempotencyCacheSweepInterval = time.Minute
+	idempotencyKeyReusedMessage   = "Idempotency-Key was already used for a different request"
+)
+
+// IdempotencyScope returns the namespace an Idempotency-Key lives in, typically the caller's identity.
+type IdempotencyScope func(c echo.Context) (string, error)
+
+type idempotencyResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	// done is closed once the first request completed, response is nil if it was not worth keeping
+	done      chan struct{}
+	response  *idempotencyResponse
+	expiresAt time.Time
+}
+
+type idempotencyCache struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to retry: the first response per scope and key
+// is kept for the given ttl and replayed for later requests with the same key, concurrent duplicates wait for the first
+// one to complete, and reusing a key for a different request is rejected with 422.
+// Responses with 429 or 5xx status are not kept, so a retry executes the request again.
+func Idempotency(ttl time.Duration, scope IdempotencyScope) echo.MiddlewareFunc {
+	cache := &idempotencyCache{
+		ttl:     ttl,
+		entries: map[string]*idempotencyEntry{},
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(idempotencyKeyHeaderField)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key too long")
+			}
+
+			scopeID, err := scope(c)
+			if err != nil {
+				log.Errorf("idempotency scope lookup failed: %v", err)
+
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+
+			fingerprint, err := fingerprintRequest(c)
+			if err != nil {
+				return err
+			}
+
+			return cache.handle(c, next, scopeID+"\x00"+key, fingerprint)
+		}
+	}
+}
+
+func fingerprintRequest(c echo.Context) ([sha256.Size]byte, error) {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return [sha256.Size]byte{}, errors.Wrap(err, "cannot read request body")
+	}
+
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request().Method + " " + c.Request().URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	var fingerprint [sha256.Size]byte
+
+	copy(fingerprint[:], hash.Sum(nil))
+
+	return fingerprint, nil
+}
+
+func (ic *idempotencyCache) handle(
+	c echo.Context, next echo.HandlerFunc, key string, fingerprint [sha256.Size]byte,
+) error {
+	for {
+		entry, first := ic.acquire(key, fingerprint)
+
+		if entry.fingerprint != fingerprint {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, idempotencyKeyReusedMessage)
+		}
+
+		if first {
+			return ic.execute(c, next, key, entry)
+		}
+
+		select {
+		case <-entry.done:
+		case <-c.Request().Context().Done():
+			return errors.Wrap(c.Request().Context().Err(), "waiting for concurrent idempotent request")
+		}
+
+		if entry.response != nil {
+			return replay(c, entry.response)
+		}
+		// the first request's response was not kept, try to become the first one ourselves
+	}
+}
+
+// acquire returns the live entry for the key, creating it if there is none; first reports whether it was created.
+func (ic *idempotencyCache) acquire(key string, fingerprint [sha256.Size]byte) (*idempotencyEntry, bool) {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	now := time.Now()
+	ic.sweep(now)
+
+	if entry := ic.entries[key]; entry != nil && (entry.expiresAt.IsZero() || now.Before(entry.expiresAt)) {
+		return entry, false
+	}
+
+	entry := &idempotencyEntry{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+	ic.entries[key] = entry
+
+	return entry, true
+}
+
+// sweep drops expired entries once in a while; the caller has to hold the mutex.
+func (ic *idempotencyCache) sweep(now time.Time) {
+	if now.Sub(ic.lastSweep) < idempotencyCacheSweepInterval {
+		return
+	}
+
+	ic.lastSweep = now
+
+	for key, entry := range ic.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(ic.entries, key)
+		}
+	}
+}
+
+func (ic *idempotencyCache) execute(c echo.Context, next echo.HandlerFunc, key string, entry *idempotencyEntry) error {
+	recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = recorder
+
+	defer func() {
+		c.Response().Writer = recorder.ResponseWriter
+
+		ic.mutex.Lock()
+		defer ic.mutex.Unlock()
+
+		status := c.Response().Status
+		if c.Response().Committed && status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+			entry.response = &idempotencyResponse{
+				status: status,
+				header: c.Response().Header().Clone(),
+				body:   recorder.body.Bytes(),
+			}
+			entry.expiresAt = time.Now().Add(ic.ttl)
+		} else {
+			delete(ic.entries, key)
+		}
+
+		close(entry.done)
+	}()
+
+	if err := next(c); err != nil {
+		// render the error now, so the response can be recorded
+		c.Error(err)
+	}
+
+	return nil
+}
+
+func replay(c echo.Context, response *idempotencyResponse) error {
+	for name, values := range response.header {
+		if name == echo.HeaderXRequestID {
+			continue
+		}
+
+		c.Response().Header()[name] = values
+	}
+
+	c.Response().Header().Set(idempotentReplayedHeaderField, "true")
+	c.Response().WriteHeader(response.status)
+
+	if _, err := c.Response().Write(response.body); err != nil {
+		return errors.Wrap(err, "cannot replay idempotent response")
+	}
+
+	return nil
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	n, err := r.ResponseWriter.Write(b)
+	if err != nil {
+		return n, errors.Wrap(err, "cannot write response")
+	}
+
+	return n, nil
+}
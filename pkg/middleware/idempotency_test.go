@@ -0,0 +1,176 @@
+//nolint:funlen // Test functions are long, due to test cases
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/middleware"
+)
+
+type idempotencyTestServer struct {
+	echo  *echo.Echo
+	calls atomic.Int32
+	// release blocks the handler until closed, if set
+	release chan struct{}
+	status  int
+}
+
+func newIdempotencyTestServer() *idempotencyTestServer {
+	s := &idempotencyTestServer{
+		echo:   echo.New(),
+		status: http.StatusCreated,
+	}
+
+	scope := func(c echo.Context) (string, error) {
+		return c.Request().Header.Get("X-User"), nil
+	}
+
+	s.echo.POST("/jobs", func(c echo.Context) error {
+		call := s.calls.Add(1)
+
+		if s.release != nil {
+			<-s.release
+		}
+
+		if s.status >= http.StatusBadRequest {
+			return echo.NewHTTPError(s.status, "failed")
+		}
+
+		return c.String(s.status, "job-"+strconv.Itoa(int(call)))
+	}, middleware.Idempotency(time.Hour, scope))
+
+	return s
+}
+
+func (s *idempotencyTestServer) post(user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	rec := httptest.NewRecorder()
+	s.echo.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retry replays the first response", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+
+		first := s.post("alice", "k1", "patent")
+		second := s.post("alice", "k1", "patent")
+
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, int32(1), s.calls.Load())
+	})
+
+	t.Run("requests without key are not deduplicated", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+
+		assert.Equal(t, "job-1", s.post("alice", "", "patent").Body.String())
+		assert.Equal(t, "job-2", s.post("alice", "", "patent").Body.String())
+	})
+
+	t.Run("keys are scoped per identity", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+
+		assert.Equal(t, "job-1", s.post("alice", "k1", "patent").Body.String())
+		assert.Equal(t, "job-2", s.post("bob", "k1", "patent").Body.String())
+	})
+
+	t.Run("reused key with a different body is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+
+		s.post("alice", "k1", "patent")
+		rec := s.post("alice", "k1", "another patent")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, int32(1), s.calls.Load())
+	})
+
+	t.Run("transient errors are not kept", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+		s.status = http.StatusTooManyRequests
+
+		assert.Equal(t, http.StatusTooManyRequests, s.post("alice", "k1", "patent").Code)
+
+		s.status = http.StatusCreated
+
+		assert.Equal(t, http.StatusCreated, s.post("alice", "k1", "patent").Code)
+		assert.Equal(t, int32(2), s.calls.Load())
+	})
+
+	t.Run("client errors are kept", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+		s.status = http.StatusBadRequest
+
+		s.post("alice", "k1", "patent")
+		rec := s.post("alice", "k1", "patent")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, int32(1), s.calls.Load())
+	})
+
+	t.Run("concurrent duplicates are serialised", func(t *testing.T) {
+		t.Parallel()
+
+		s := newIdempotencyTestServer()
+		s.release = make(chan struct{})
+
+		const duplicates = 5
+
+		var wg sync.WaitGroup
+
+		bodies := make([]string, duplicates)
+
+		for i := 0; i < duplicates; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				bodies[i] = s.post("alice", "k1", "patent").Body.String()
+			}(i)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		close(s.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), s.calls.Load())
+
+		for _, body := range bodies {
+			assert.Equal(t, "job-1", body)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"bytes"
+	"strings"
+	tmpl "text/template"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const componentsParameterPrefix = "#/components/parameters/"
+
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// Document is the part of an OpenAPI document needed for validation.
+type Document struct {
+	Paths      map[string]PathItem   `yaml:"paths"`
+	Security   []SecurityRequirement `yaml:"security"`
+	Components struct {
+		Schemas         map[string]*Schema        `yaml:"schemas"`
+		Parameters      map[string]*Parameter     `yaml:"parameters"`
+		SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+// PathItem holds the operations of a path template, e.g. /patents/{patentId}, keyed by lower case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation is the part of an OpenAPI operation object needed for validation.
+type Operation struct {
+	Parameters  []*Parameter          `yaml:"parameters"`
+	RequestBody *RequestBody          `yaml:"requestBody"`
+	Responses   map[string]*Response  `yaml:"responses"`
+	Security    []SecurityRequirement `yaml:"security"`
+}
+
+// Parameter is a path, query or header parameter, or a reference to one of the components section.
+type Parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *Schema `yaml:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+type Response struct {
+	Content map[string]*MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// SecurityRequirement lists the names of security schemes that have to be satisfied together.
+type SecurityRequirement map[string][]string
+
+type SecurityScheme struct {
+	Type string `yaml:"type"`
+	In   string `yaml:"in"`
+	Name string `yaml:"name"`
+}
+
+func renderDocument(openAPIFile string, template interface{}) ([]byte, error) {
+	openAPITemplate, err := tmpl.ParseGlob(openAPIFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse template")
+	}
+
+	var openAPIRendered bytes.Buffer
+
+	if err := openAPITemplate.Execute(&openAPIRendered, template); err != nil {
+		return nil, errors.Wrap(err, "cannot execute template")
+	}
+
+	return openAPIRendered.Bytes(), nil
+}
+
+// LoadDocument renders the OpenAPI template like the documentation routes do and parses the result.
+func LoadDocument(openAPIFile string, template interface{}) (*Document, error) {
+	rendered, err := renderDocument(openAPIFile, template)
+	if err != nil {
+		return nil, err
+	}
+
+	var document Document
+	if err := yaml.Unmarshal(rendered, &document); err != nil {
+		return nil, errors.Wrap(err, "cannot parse OpenAPI document")
+	}
+
+	return &document, nil
+}
+
+// Schema returns the named schema of the components section, with its references resolvable.
+func (d *Document) Schema(name string) (*Schema, error) {
+	schema, ok := d.Components.Schemas[name]
+	if !ok {
+		return nil, errors.Wrap(ErrSchemaNotFound, name)
+	}
+
+	return &Schema{Ref: componentsSchemaPrefix + name, document: d, resolved: schema}, nil
+}
+
+// parameter resolves a reference to a parameter of the components section.
+func (d *Document) parameter(parameter *Parameter) *Parameter {
+	if parameter.Ref == "" {
+		return parameter
+	}
+
+	if resolved, ok := d.Components.Parameters[strings.TrimPrefix(parameter.Ref, componentsParameterPrefix)]; ok {
+		return resolved
+	}
+
+	return parameter
+}
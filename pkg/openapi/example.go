@@ -0,0 +1,87 @@
+package openapi
+
+import "strings"
+
+const exampleString = "example"
+
+// Example returns a value matching the schema, e.g. as request payload in tests. It takes the example, first enum
+// value or default of the schema where given and derives a value from the constraints otherwise; objects only get
+// their required properties.
+func (d *Document) Example(schema *Schema) any {
+	schema = schema.resolve(d)
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case schema.Default != nil:
+		return schema.Default
+	}
+
+	switch schema.Type {
+	case "object":
+		object := map[string]any{}
+
+		for _, name := range schema.Required {
+			if property, ok := schema.Properties[name]; ok {
+				object[name] = d.Example(property)
+			} else {
+				object[name] = exampleString
+			}
+		}
+
+		return object
+	case "array":
+		count := 1
+		if schema.MinItems != nil && *schema.MinItems > count {
+			count = *schema.MinItems
+		}
+
+		items := make([]any, count)
+		for i := range items {
+			items[i] = exampleString
+
+			if schema.Items != nil {
+				items[i] = d.Example(schema.Items)
+			}
+		}
+
+		return items
+	case "integer", "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+
+		return float64(1)
+	case "boolean":
+		return false
+	case "string":
+		return schema.exampleString()
+	}
+
+	return nil
+}
+
+func (s *Schema) exampleString() string {
+	switch s.Format {
+	case "date":
+		return "2024-01-31"
+	case "date-time":
+		return "2024-01-31T12:00:00Z"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	}
+
+	value := exampleString
+
+	if s.MinLength != nil && *s.MinLength > len(value) {
+		value = strings.Repeat("x", *s.MinLength)
+	}
+
+	if s.MaxLength != nil && *s.MaxLength < len(value) {
+		value = value[:*s.MaxLength]
+	}
+
+	return value
+}
@@ -0,0 +1,50 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+)
+
+func TestDocument_Example(t *testing.T) {
+	t.Parallel()
+
+	document, err := openapi.LoadDocument(specFile, struct{ ServerBaseURL string }{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	names := make([]string, 0, len(document.Components.Schemas))
+	for name := range document.Components.Schemas {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := document.Schema(name)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// validate the example as it is sent, numbers of the document become float64 like in requests
+			encoded, err := json.Marshal(document.Example(schema))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var example any
+
+			assert.NoError(t, json.Unmarshal(encoded, &example))
+			assert.NoError(t, schema.Validate(example))
+		})
+	}
+}
@@ -1,9 +1,7 @@
 package openapi
 
 import (
-	"bytes"
 	"net/http"
-	tmpl "text/template"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
@@ -18,16 +16,13 @@ func NewHandlers() *HTTPHandlers {
 }
 
 func (h *HTTPHandlers) GetOpenAPIDocument(openAPIFile string, template interface{}) echo.HandlerFunc {
-	openAPITemplate := tmpl.Must(tmpl.ParseGlob(openAPIFile))
-
-	var openAPIRendered bytes.Buffer
-
-	if err := openAPITemplate.Execute(&openAPIRendered, template); err != nil {
-		log.Fatalf("cannot execute template: %v", err)
+	openAPIRendered, err := renderDocument(openAPIFile, template)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	return func(c echo.Context) error {
-		if err := c.Blob(http.StatusOK, "text/yaml", openAPIRendered.Bytes()); err != nil {
+		if err := c.Blob(http.StatusOK, "text/yaml", openAPIRendered); err != nil {
 			log.Error(err)
 
 			return errors.Wrap(err, "OpenAPI file failed")
@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const componentsSchemaPrefix = "#/components/schemas/"
+
+// Schema is the subset of the OpenAPI 3.0 schema object that the API specification uses.
+type Schema struct {
+	Ref                  string                `yaml:"$ref"`
+	Type                 string                `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Nullable             bool                  `yaml:"nullable"`
+	Enum                 []any                 `yaml:"enum"`
+	Properties           map[string]*Schema    `yaml:"properties"`
+	Required             []string              `yaml:"required"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	Items                *Schema               `yaml:"items"`
+	MinItems             *int                  `yaml:"minItems"`
+	MaxItems             *int                  `yaml:"maxItems"`
+	MinLength            *int                  `yaml:"minLength"`
+	MaxLength            *int                  `yaml:"maxLength"`
+	Minimum              *float64              `yaml:"minimum"`
+	Maximum              *float64              `yaml:"maximum"`
+	Pattern              string                `yaml:"pattern"`
+	Default              any                   `yaml:"default"`
+	Example              any                   `yaml:"example"`
+
+	document *Document
+	resolved *Schema
+}
+
+// AdditionalProperties is either a boolean or a schema for the properties not listed.
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return errors.Wrap(value.Decode(&a.Allowed), "additionalProperties")
+	}
+
+	a.Allowed = true
+
+	return errors.Wrap(value.Decode(&a.Schema), "additionalProperties")
+}
+
+// ValidationError locates a violation by the JSON pointer of the offending value.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks a value as decoded by encoding/json into an interface{} and returns ValidationErrors,
+// or nil if the value matches the schema.
+func (s *Schema) Validate(value any) error {
+	if errs := s.violations(s.document, value); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// violations validates a value with the references of the schema resolved in the given document.
+func (s *Schema) violations(document *Document, value any) ValidationErrors {
+	var errs ValidationErrors
+
+	s.validate(document, "", value, &errs)
+
+	return errs
+}
+
+func (s *Schema) resolve(document *Document) *Schema {
+	if s.resolved != nil {
+		return s.resolved
+	}
+
+	if s.Ref == "" || document == nil {
+		return s
+	}
+
+	if resolved, ok := document.Components.Schemas[strings.TrimPrefix(s.Ref, componentsSchemaPrefix)]; ok {
+		return resolved
+	}
+
+	return s
+}
+
+//nolint:cyclop,gocognit // one branch per keyword
+func (s *Schema) validate(document *Document, path string, value any, errs *ValidationErrors) {
+	schema := s.resolve(document)
+	report := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: "/" + strings.TrimPrefix(path, "/"), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			report("must not be null")
+		}
+
+		return
+	}
+
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		report("must be one of %v", schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			report("must be an object")
+
+			return
+		}
+
+		schema.validateObject(document, path, object, errs, report)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			report("must be an array")
+
+			return
+		}
+
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			report("must contain at least %d items", *schema.MinItems)
+		}
+
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			report("must contain at most %d items", *schema.MaxItems)
+		}
+
+		if schema.Items != nil {
+			for i, item := range array {
+				schema.Items.validate(document, fmt.Sprintf("%s/%d", path, i), item, errs)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			report("must be a string")
+
+			return
+		}
+
+		schema.validateString(str, report)
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			report("must be a number")
+
+			return
+		}
+
+		if schema.Type == "integer" && number != math.Trunc(number) {
+			report("must be an integer")
+		}
+
+		if schema.Minimum != nil && number < *schema.Minimum {
+			report("must be at least %v", *schema.Minimum)
+		}
+
+		if schema.Maximum != nil && number > *schema.Maximum {
+			report("must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("must be a boolean")
+		}
+	}
+}
+
+func (s *Schema) validateObject(
+	document *Document, path string, object map[string]any, errs *ValidationErrors, report func(string, ...any),
+) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			report("missing required property %q", name)
+		}
+	}
+
+	// sorted for a stable order of the reported violations
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		property := object[name]
+		propertyPath := path + "/" + name
+
+		if propertySchema, ok := s.Properties[name]; ok {
+			propertySchema.validate(document, propertyPath, property, errs)
+
+			continue
+		}
+
+		if s.AdditionalProperties == nil {
+			continue
+		}
+
+		if !s.AdditionalProperties.Allowed {
+			*errs = append(*errs, ValidationError{Path: propertyPath, Message: "unknown property"})
+		} else if s.AdditionalProperties.Schema != nil {
+			s.AdditionalProperties.Schema.validate(document, propertyPath, property, errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, report func(string, ...any)) {
+	length := len([]rune(str))
+
+	if s.MinLength != nil && length < *s.MinLength {
+		report("must be at least %d characters long", *s.MinLength)
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		report("must be at most %d characters long", *s.MaxLength)
+	}
+
+	if s.Pattern != "" {
+		if pattern, err := regexp.Compile(s.Pattern); err == nil && !pattern.MatchString(str) {
+			report("must match %s", s.Pattern)
+		}
+	}
+
+	switch s.Format {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			report("must be a date (YYYY-MM-DD)")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			report("must be an RFC 3339 date-time")
+		}
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			report("must be a UUID")
+		}
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+//nolint:funlen // Test functions are long, due to test cases
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+)
+
+const specFile = "../../patAi.openapi3.yaml"
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	document, err := openapi.LoadDocument(specFile, struct{ ServerBaseURL string }{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		schema   string
+		document string
+		want     []openapi.ValidationError
+	}{
+		{
+			name:   "complete patent",
+			schema: "PatentSubmission",
+			document: `{
+				"title": "Method for valuing patents",
+				"abstract": "A method ...",
+				"claims": ["1. A method ...", "2. The method according to claim 1 ..."],
+				"publicationNumber": "EP1234567A1",
+				"priorityDate": "2021-03-14",
+				"cpcClasses": ["G06Q 50/18"],
+				"jurisdiction": "EP"
+			}`,
+			want: nil,
+		},
+		{
+			name:     "missing required properties",
+			schema:   "PatentSubmission",
+			document: `{"abstract": "A method ..."}`,
+			want: []openapi.ValidationError{
+				{Path: "/", Message: `missing required property "title"`},
+				{Path: "/", Message: `missing required property "claims"`},
+			},
+		},
+		{
+			name:     "violations are located",
+			schema:   "PatentSubmission",
+			document: `{"title": "T", "claims": [], "priorityDate": "14.03.2021", "jurisdiction": "europe", "foo": 1}`,
+			want: []openapi.ValidationError{
+				{Path: "/claims", Message: "must contain at least 1 items"},
+				{Path: "/foo", Message: "unknown property"},
+				{Path: "/jurisdiction", Message: "must match ^[A-Z]{2}$"},
+				{Path: "/priorityDate", Message: "must be a date (YYYY-MM-DD)"},
+			},
+		},
+		{
+			name:     "array items are checked",
+			schema:   "PatentSubmission",
+			document: `{"title": "T", "claims": ["1. A method", 2]}`,
+			want:     []openapi.ValidationError{{Path: "/claims/1", Message: "must be a string"}},
+		},
+		{
+			name:     "references and enums are resolved",
+			schema:   "BatchItemRequest",
+			document: `{"content": "x", "priority": "urgent"}`,
+			want:     []openapi.ValidationError{{Path: "/priority", Message: "must be one of [low normal high]"}},
+		},
+		{
+			name:     "wrong type",
+			schema:   "PatentSubmission",
+			document: `["title"]`,
+			want:     []openapi.ValidationError{{Path: "/", Message: "must be an object"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := document.Schema(tc.schema)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var value any
+			if !assert.NoError(t, json.Unmarshal([]byte(tc.document), &value)) {
+				return
+			}
+
+			err = schema.Validate(value)
+			if tc.want == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var errs openapi.ValidationErrors
+			if !assert.ErrorAs(t, err, &errs) {
+				return
+			}
+
+			assert.ElementsMatch(t, tc.want, []openapi.ValidationError(errs))
+		})
+	}
+}
+
+func TestDocument_Schema(t *testing.T) {
+	t.Parallel()
+
+	document, err := openapi.LoadDocument(specFile, struct{ ServerBaseURL string }{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = document.Schema("NoSuchSchema")
+	assert.ErrorIs(t, err, openapi.ErrSchemaNotFound)
+}
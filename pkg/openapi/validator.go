@@ -0,0 +1,526 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/log"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+// maxValidatedResponseSize bounds the responses kept in memory for validation, larger ones are passed unvalidated.
+const maxValidatedResponseSize = 1 << 20
+
+var (
+	ErrInvalidRequest       = errors.New("request does not match the API specification")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	errUndocumentedStatus      = errors.New("status is not documented")
+	errUndocumentedContentType = errors.New("content type is not documented")
+	errUndocumentedBody        = errors.New("body is not documented")
+)
+
+// ValidatorConfig configures the validation middleware.
+type ValidatorConfig struct {
+	// BasePath is the path the paths of the document are relative to, e.g. /api/v0
+	BasePath string
+	// ValidateResponses records responses and reports their deviations from the document, meant for development only
+	ValidateResponses bool
+	// OnResponseDrift is called with the deviations of a response, it defaults to logging them as warning
+	OnResponseDrift func(c echo.Context, err error)
+}
+
+// ProblemTypes maps the errors of the validation middleware to the problem types they are reported as.
+func ProblemTypes() []problem.Mapping {
+	return []problem.Mapping{
+		{Err: ErrInvalidRequest, Type: problem.Type{
+			Status: http.StatusBadRequest, Code: "invalid-request", Title: "Request does not match the API specification",
+		}},
+		{Err: ErrUnsupportedMediaType, Type: problem.Type{
+			Status: http.StatusUnsupportedMediaType, Code: "unsupported-media-type", Title: "Unsupported media type",
+		}},
+	}
+}
+
+type route struct {
+	segments []string
+	literals int
+	item     PathItem
+}
+
+// Validator returns a middleware validating the parameters, content type and JSON body of requests against the
+// operations of the document, violations are reported as ErrInvalidRequest with the offending fields. Requests to
+// paths the document does not describe are passed on, as are requests lacking the credentials of the operation's
+// security requirements, so that authentication failures take precedence.
+// With ValidateResponses, responses are recorded and their deviations from the document reported to OnResponseDrift.
+func (d *Document) Validator(cfg ValidatorConfig) echo.MiddlewareFunc {
+	routes := d.routes()
+
+	if cfg.OnResponseDrift == nil {
+		cfg.OnResponseDrift = logResponseDrift
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			path, ok := strings.CutPrefix(c.Request().URL.EscapedPath(), cfg.BasePath)
+			if !ok {
+				return next(c)
+			}
+
+			operation, pathParams := matchOperation(routes, path, c.Request().Method)
+			if operation == nil {
+				return next(c)
+			}
+
+			if d.hasCredentials(c.Request(), operation) {
+				if err := d.validateRequest(c, operation, pathParams); err != nil {
+					return err
+				}
+			}
+
+			if !cfg.ValidateResponses || c.Request().Method == http.MethodHead {
+				return next(c)
+			}
+
+			return d.observeResponse(c, next, operation, cfg.OnResponseDrift)
+		}
+	}
+}
+
+// routes orders the path templates by their number of literal segments, so that /patents/export is matched before
+// /patents/{patentId}.
+func (d *Document) routes() []route {
+	routes := make([]route, 0, len(d.Paths))
+
+	for template, item := range d.Paths {
+		segments := strings.Split(strings.Trim(template, "/"), "/")
+
+		literals := 0
+
+		for _, segment := range segments {
+			if !isPathParameter(segment) {
+				literals++
+			}
+		}
+
+		routes = append(routes, route{segments: segments, literals: literals, item: item})
+	}
+
+	slices.SortStableFunc(routes, func(a, b route) int {
+		if a.literals != b.literals {
+			return b.literals - a.literals
+		}
+
+		return strings.Compare(strings.Join(a.segments, "/"), strings.Join(b.segments, "/"))
+	})
+
+	return routes
+}
+
+func matchOperation(routes []route, path string, method string) (*Operation, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, r := range routes {
+		pathParams, ok := r.match(segments)
+		if !ok {
+			continue
+		}
+
+		operation := r.item[strings.ToLower(method)]
+		if operation == nil {
+			return nil, nil
+		}
+
+		return operation, pathParams
+	}
+
+	return nil, nil
+}
+
+func (r route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	pathParams := map[string]string{}
+
+	for i, segment := range r.segments {
+		value, err := url.PathUnescape(segments[i])
+		if err != nil {
+			return nil, false
+		}
+
+		if isPathParameter(segment) {
+			pathParams[strings.Trim(segment, "{}")] = value
+		} else if segment != value {
+			return nil, false
+		}
+	}
+
+	return pathParams, true
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// hasCredentials reports whether the request carries the credentials of one of the operation's security requirements,
+// without checking them.
+func (d *Document) hasCredentials(request *http.Request, operation *Operation) bool {
+	requirements := operation.Security
+	if requirements == nil {
+		requirements = d.Security
+	}
+
+	if len(requirements) == 0 {
+		return true
+	}
+
+	for _, requirement := range requirements {
+		satisfied := true
+
+		for name := range requirement {
+			scheme, ok := d.Components.SecuritySchemes[name]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case scheme.Type == "apiKey" && scheme.In == "header":
+				satisfied = satisfied && request.Header.Get(scheme.Name) != ""
+			case scheme.Type == "apiKey" && scheme.In == "query":
+				satisfied = satisfied && request.URL.Query().Get(scheme.Name) != ""
+			case scheme.Type == "http":
+				satisfied = satisfied && request.Header.Get(echo.HeaderAuthorization) != ""
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Document) validateRequest(c echo.Context, operation *Operation, pathParams map[string]string) error {
+	var fields []problem.FieldError
+
+	for _, parameter := range operation.Parameters {
+		fields = append(fields, d.validateParameter(c, d.parameter(parameter), pathParams)...)
+	}
+
+	if operation.RequestBody != nil {
+		bodyFields, err := d.validateBody(c, operation.RequestBody)
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, bodyFields...)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+
+	return &problem.ValidationError{Err: errors.Wrap(ErrInvalidRequest, strings.Join(messages, "; ")), Fields: fields}
+}
+
+// validateParameter reports violations by the parameter name, followed by the JSON pointer for array items.
+func (d *Document) validateParameter(
+	c echo.Context, parameter *Parameter, pathParams map[string]string,
+) []problem.FieldError {
+	var values []string
+
+	switch parameter.In {
+	case "path":
+		if value, ok := pathParams[parameter.Name]; ok {
+			values = []string{value}
+		}
+	case "query":
+		values = c.QueryParams()[parameter.Name]
+	case "header":
+		values = c.Request().Header.Values(parameter.Name)
+	}
+
+	if len(values) == 0 {
+		if parameter.Required {
+			return []problem.FieldError{{Field: parameter.Name, Message: "missing required parameter"}}
+		}
+
+		return nil
+	}
+
+	if parameter.Schema == nil {
+		return nil
+	}
+
+	violations := parameter.Schema.violations(d, d.parameterValue(parameter.Schema, values))
+	fields := make([]problem.FieldError, 0, len(violations))
+
+	for _, violation := range violations {
+		field := parameter.Name
+		if violation.Path != "/" {
+			field += violation.Path
+		}
+
+		fields = append(fields, problem.FieldError{Field: field, Message: violation.Message})
+	}
+
+	return fields
+}
+
+// parameterValue converts the values of a parameter to the type of its schema, values that cannot be converted are
+// kept as string for the schema to report them.
+func (d *Document) parameterValue(schema *Schema, values []string) any {
+	schema = schema.resolve(d)
+
+	if schema.Type != "array" {
+		return d.scalarValue(schema, values[0])
+	}
+
+	items := make([]any, 0, len(values))
+
+	for _, value := range values {
+		if schema.Items == nil {
+			items = append(items, value)
+		} else {
+			items = append(items, d.scalarValue(schema.Items, value))
+		}
+	}
+
+	return items
+}
+
+func (d *Document) scalarValue(schema *Schema, value string) any {
+	switch schema.resolve(d).Type {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+
+	return value
+}
+
+// validateBody checks the content type of the body and validates JSON bodies, which are read and put back for the
+// handler; violations are located by JSON pointer.
+func (d *Document) validateBody(c echo.Context, body *RequestBody) ([]problem.FieldError, error) {
+	request := c.Request()
+
+	if request.ContentLength == 0 {
+		if body.Required {
+			return []problem.FieldError{{Field: "/", Message: "request body is required"}}, nil
+		}
+
+		return nil, nil
+	}
+
+	mediaType := parseMediaType(request.Header.Get(echo.HeaderContentType))
+
+	content, ok := matchMediaType(body.Content, mediaType)
+	if !ok {
+		return nil, problem.Detailed(ErrUnsupportedMediaType, "%q, use one of %s", mediaType, documentedMediaTypes(body.Content))
+	}
+
+	if content.Schema == nil || !isJSON(mediaType) {
+		return nil, nil
+	}
+
+	raw, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read request body")
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return []problem.FieldError{{Field: "/", Message: "malformed JSON: " + err.Error()}}, nil
+	}
+
+	violations := content.Schema.violations(d, value)
+	fields := make([]problem.FieldError, 0, len(violations))
+
+	for _, violation := range violations {
+		fields = append(fields, problem.FieldError{Field: violation.Path, Message: violation.Message})
+	}
+
+	return fields, nil
+}
+
+// observeResponse renders errors itself, so that problem responses are validated as well.
+func (d *Document) observeResponse(
+	c echo.Context, next echo.HandlerFunc, operation *Operation, onDrift func(echo.Context, error),
+) error {
+	recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = recorder
+
+	defer func() {
+		c.Response().Writer = recorder.ResponseWriter
+	}()
+
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+
+	if !c.Response().Committed {
+		return nil
+	}
+
+	if err := d.validateResponse(operation, c.Response().Status, c.Response().Header(), recorder); err != nil {
+		onDrift(c, err)
+	}
+
+	return nil
+}
+
+func logResponseDrift(c echo.Context, err error) {
+	log.Warnf("response to %s %s deviates from the API specification: %v", c.Request().Method, c.Request().URL.Path, err)
+}
+
+func (d *Document) validateResponse(
+	operation *Operation, status int, header http.Header, recorder *responseRecorder,
+) error {
+	response := operation.response(status)
+	if response == nil {
+		return errors.Wrapf(errUndocumentedStatus, "%d", status)
+	}
+
+	if len(response.Content) == 0 {
+		if recorder.size > 0 {
+			return errors.Wrapf(errUndocumentedBody, "status %d", status)
+		}
+
+		return nil
+	}
+
+	mediaType := parseMediaType(header.Get(echo.HeaderContentType))
+
+	content, ok := matchMediaType(response.Content, mediaType)
+	if !ok {
+		return errors.Wrapf(errUndocumentedContentType, "%q for status %d, documented are %s",
+			mediaType, status, documentedMediaTypes(response.Content))
+	}
+
+	if content.Schema == nil || !isJSON(mediaType) || recorder.truncated {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(recorder.body.Bytes(), &value); err != nil {
+		return errors.Wrap(err, "malformed JSON")
+	}
+
+	if violations := content.Schema.violations(d, value); len(violations) > 0 {
+		return violations
+	}
+
+	return nil
+}
+
+// response returns the response documented for the status, falling back to its range (e.g. 4XX) and the default.
+func (o *Operation) response(status int) *Response {
+	code := strconv.Itoa(status)
+
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if response, ok := o.Responses[key]; ok {
+			return response
+		}
+	}
+
+	return nil
+}
+
+func parseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+// matchMediaType finds the content of a media type, by the media type itself or a range like text/* or */*.
+func matchMediaType(content map[string]*MediaType, mediaType string) (*MediaType, bool) {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	for _, key := range []string{mediaType, mainType + "/*", "*/*"} {
+		if media, ok := content[key]; ok {
+			if media == nil {
+				media = &MediaType{}
+			}
+
+			return media, true
+		}
+	}
+
+	return nil, false
+}
+
+func documentedMediaTypes(content map[string]*MediaType) string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+
+	slices.Sort(mediaTypes)
+
+	return strings.Join(mediaTypes, ", ")
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// responseRecorder keeps a copy of JSON responses up to maxValidatedResponseSize while writing them through.
+type responseRecorder struct {
+	http.ResponseWriter
+	body      bytes.Buffer
+	size      int
+	truncated bool
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.size += len(b)
+
+	if isJSON(parseMediaType(r.Header().Get(echo.HeaderContentType))) && !r.truncated {
+		if r.body.Len()+len(b) > maxValidatedResponseSize {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	if err != nil {
+		return n, errors.Wrap(err, "cannot write response")
+	}
+
+	return n, nil
+}
+
+// Unwrap gives http.ResponseController access to the flusher of the underlying writer for streamed responses.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,353 @@
+//nolint:funlen // Test functions are long, due to test cases
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/openapi"
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+const validatorSpec = `
+openapi: 3.0.0
+paths:
+  /things:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+        - $ref: '#/components/parameters/tag'
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Thing'
+          text/*:
+            schema:
+              type: string
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+        4XX:
+          description: Client error
+          content:
+            application/problem+json:
+              schema:
+                type: object
+  /things/export:
+    get:
+      responses:
+        '200':
+          description: Export
+          content:
+            text/csv:
+              schema:
+                type: string
+  /things/{thingId}:
+    get:
+      parameters:
+        - name: thingId
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        '200':
+          description: A thing
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+        '404':
+          description: Not found
+  /public:
+    get:
+      security: []
+      parameters:
+        - name: X-Trace
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Nothing
+components:
+  parameters:
+    tag:
+      name: tag
+      in: query
+      schema:
+        type: array
+        items:
+          type: string
+          maxLength: 3
+  schemas:
+    Thing:
+      type: object
+      properties:
+        name:
+          type: string
+      required:
+        - name
+      additionalProperties: false
+  securitySchemes:
+    api_key:
+      type: apiKey
+      in: header
+      name: X-API-Key
+security:
+  - api_key: []
+`
+
+type validatorTestServer struct {
+	echo   *echo.Echo
+	mutex  sync.Mutex
+	drifts []string
+}
+
+func newValidatorTestServer(t *testing.T, handler echo.HandlerFunc) *validatorTestServer {
+	t.Helper()
+
+	specFile := filepath.Join(t.TempDir(), "spec.yaml")
+	assert.NoError(t, os.WriteFile(specFile, []byte(validatorSpec), 0o600))
+
+	document, err := openapi.LoadDocument(specFile, nil)
+	assert.NoError(t, err)
+
+	s := &validatorTestServer{echo: echo.New()}
+	s.echo.HTTPErrorHandler = problem.NewErrorHandler(problem.Config{Mappings: openapi.ProblemTypes()})
+	s.echo.Use(document.Validator(openapi.ValidatorConfig{
+		BasePath:          "/api",
+		ValidateResponses: true,
+		OnResponseDrift: func(_ echo.Context, err error) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			s.drifts = append(s.drifts, err.Error())
+		},
+	}))
+
+	for _, path := range []string{"/api/things", "/api/things/export", "/api/things/:id", "/api/public", "/api/other"} {
+		s.echo.Any(path, handler)
+	}
+
+	return s
+}
+
+func (s *validatorTestServer) do(method, target, contentType, body string, header map[string]string) (int, string) {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set(echo.HeaderContentType, contentType)
+	}
+
+	for name, value := range header {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.echo.ServeHTTP(recorder, request)
+
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestDocument_Validator_Requests(t *testing.T) {
+	t.Parallel()
+
+	authenticated := map[string]string{"X-API-Key": "key"}
+
+	testCases := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		header      map[string]string
+		wantStatus  int
+		wantCode    string
+		wantFields  []problem.FieldError
+	}{
+		{
+			name: "valid JSON body", method: http.MethodPost, target: "/api/things?limit=2&tag=a&tag=b",
+			contentType: "application/json", body: `{"name": "thing"}`, header: authenticated,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "media type range", method: http.MethodPost, target: "/api/things",
+			contentType: "text/plain; charset=utf-8", body: "thing", header: authenticated,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "invalid JSON body", method: http.MethodPost, target: "/api/things",
+			contentType: "application/json", body: `{"size": 1}`, header: authenticated,
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{
+				{Field: "/", Message: `missing required property "name"`},
+				{Field: "/size", Message: "unknown property"},
+			},
+		},
+		{
+			name: "malformed JSON body", method: http.MethodPost, target: "/api/things",
+			contentType: "application/json", body: `{"name": `, header: authenticated,
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{{Field: "/", Message: "malformed JSON: unexpected end of JSON input"}},
+		},
+		{
+			name: "missing body", method: http.MethodPost, target: "/api/things", header: authenticated,
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{{Field: "/", Message: "request body is required"}},
+		},
+		{
+			name: "unsupported media type", method: http.MethodPost, target: "/api/things",
+			contentType: "image/png", body: "png", header: authenticated,
+			wantStatus: http.StatusUnsupportedMediaType, wantCode: "unsupported-media-type",
+		},
+		{
+			name: "invalid query parameters", method: http.MethodPost, target: "/api/things?limit=zero&tag=a&tag=long",
+			contentType: "application/json", body: `{"name": "thing"}`, header: authenticated,
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{
+				{Field: "limit", Message: "must be a number"},
+				{Field: "tag/1", Message: "must be at most 3 characters long"},
+			},
+		},
+		{
+			name: "invalid path parameter", method: http.MethodGet, target: "/api/things/42", header: authenticated,
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{{Field: "thingId", Message: "must be a UUID"}},
+		},
+		{
+			name: "literal segments take precedence", method: http.MethodGet, target: "/api/things/export",
+			header: authenticated, wantStatus: http.StatusOK,
+		},
+		{
+			name: "missing required header", method: http.MethodGet, target: "/api/public",
+			wantStatus: http.StatusBadRequest, wantCode: "invalid-request",
+			wantFields: []problem.FieldError{{Field: "X-Trace", Message: "missing required parameter"}},
+		},
+		{
+			name: "requests without credentials are passed on", method: http.MethodPost, target: "/api/things",
+			contentType: "image/png", body: "png", wantStatus: http.StatusOK,
+		},
+		{
+			name: "undocumented paths are passed on", method: http.MethodPost, target: "/api/other",
+			contentType: "image/png", body: "png", header: authenticated, wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newValidatorTestServer(t, func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			status, body := s.do(tc.method, tc.target, tc.contentType, tc.body, tc.header)
+
+			assert.Equal(t, tc.wantStatus, status)
+
+			if tc.wantCode == "" {
+				return
+			}
+
+			var details problem.Details
+
+			assert.NoError(t, json.Unmarshal([]byte(body), &details))
+			assert.Equal(t, tc.wantCode, details.Code)
+			assert.Equal(t, tc.wantFields, details.Errors)
+		})
+	}
+}
+
+func TestDocument_Validator_Responses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		target     string
+		handler    echo.HandlerFunc
+		wantDrifts []string
+	}{
+		{
+			name:   "matching response",
+			target: "/api/things/0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"name": "thing"})
+			},
+		},
+		{
+			name:   "schema violation",
+			target: "/api/things/0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"value": "thing"})
+			},
+			wantDrifts: []string{`/: missing required property "name"; /value: unknown property`},
+		},
+		{
+			name:   "undocumented status",
+			target: "/api/things/0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusConflict)
+			},
+			wantDrifts: []string{"409: status is not documented"},
+		},
+		{
+			name:   "undocumented body",
+			target: "/api/things/0b5e1e2c-4d0e-4c61-9a4b-5f1c6b0e7a11",
+			handler: func(c echo.Context) error {
+				return c.String(http.StatusNotFound, "not found")
+			},
+			wantDrifts: []string{"status 404: body is not documented"},
+		},
+		{
+			name:   "undocumented content type",
+			target: "/api/things/export",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, []string{})
+			},
+			wantDrifts: []string{`"application/json" for status 200, documented are text/csv: content type is not documented`},
+		},
+		{
+			name:   "errors are validated as problem responses",
+			target: "/api/things/export",
+			handler: func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusBadRequest, "bad")
+			},
+			wantDrifts: []string{"400: status is not documented"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newValidatorTestServer(t, tc.handler)
+
+			s.do(http.MethodGet, tc.target, "", "", map[string]string{"X-API-Key": "key"})
+
+			assert.Equal(t, tc.wantDrifts, s.drifts)
+		})
+	}
+}
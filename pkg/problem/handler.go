@@ -0,0 +1,127 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/MyChaOS87/patAi/pkg/log"
+)
+
+// Config configures the error handler.
+type Config struct {
+	// TypeURIPrefix is prepended to the codes of mapped problem types to form their type URI
+	TypeURIPrefix string
+	Mappings      []Mapping
+	// ExposeInternalErrors reports the messages of internal errors to clients, which is meant for development only
+	ExposeInternalErrors bool
+}
+
+// NewErrorHandler returns an echo.HTTPErrorHandler writing problem details. Errors are resolved in this order: mapped
+// errors by their first matching mapping, detailed by a ValidationError or the outermost Detailed message and by the
+// message of the mapped error otherwise, echo.HTTPErrors by their status, validation errors as bad requests and
+// anything else as internal error, which is logged along with the request ID. The delay of a RetryAfter error is sent
+// as Retry-After header in seconds.
+func NewErrorHandler(cfg Config) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		details, internal := resolve(cfg, err)
+
+		details.Instance = c.Request().URL.Path
+		details.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+		if internal {
+			log.Errorf("request %s %s (%s) failed: %v", c.Request().Method, details.Instance, details.RequestID, err)
+
+			if !cfg.ExposeInternalErrors {
+				details.Detail = ""
+			}
+		} else if details.Detail != err.Error() {
+			log.Debugf("request %s %s (%s) failed: %v", c.Request().Method, details.Instance, details.RequestID, err)
+		}
+
+		if c.Response().Committed {
+			return
+		}
+
+		var retryErr *retryError
+		if errors.As(err, &retryErr) {
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryErr.after.Seconds()))))
+		}
+
+		if writeErr := write(c, details); writeErr != nil {
+			log.Errorf("cannot write problem details: %v", writeErr)
+		}
+	}
+}
+
+// resolve builds the problem details for err, internal reports whether its message must be hidden from clients.
+func resolve(cfg Config, err error) (Details, bool) {
+	var fields []FieldError
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		fields = validationErr.Fields
+	}
+
+	for _, mapping := range cfg.Mappings {
+		if errors.Is(err, mapping.Err) {
+			return Details{
+				Type:   cfg.TypeURIPrefix + mapping.Type.Code,
+				Title:  mapping.Type.Title,
+				Status: mapping.Type.Status,
+				Detail: ClientMessage(err, mapping.Err.Error()),
+				Code:   mapping.Type.Code,
+				Errors: fields,
+			}, false
+		}
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		details := blank(httpErr.Code)
+		details.Detail = fmt.Sprint(httpErr.Message)
+		details.Errors = fields
+
+		return details, httpErr.Code >= http.StatusInternalServerError
+	}
+
+	if validationErr != nil {
+		details := blank(http.StatusBadRequest)
+		details.Detail = err.Error()
+		details.Errors = fields
+
+		return details, false
+	}
+
+	details := blank(http.StatusInternalServerError)
+	details.Detail = err.Error()
+
+	return details, true
+}
+
+func blank(status int) Details {
+	return Details{
+		Type:   BlankType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   statusCode(status),
+	}
+}
+
+func write(c echo.Context, details Details) error {
+	if c.Request().Method == http.MethodHead {
+		return errors.Wrap(c.NoContent(details.Status), "cannot write status")
+	}
+
+	body, err := json.Marshal(details)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode problem details")
+	}
+
+	return errors.Wrap(c.Blob(details.Status, MIMEType, body), "cannot write problem details")
+}
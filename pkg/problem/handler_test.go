@@ -0,0 +1,209 @@
+//nolint:funlen // Test functions are long, due to test cases
+package problem_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/problem"
+)
+
+var (
+	errNotFound = errors.New("thing not found")
+	errInvalid  = errors.New("malformed thing")
+	errTooMany  = errors.New("too many things")
+)
+
+func TestNewErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	mappings := []problem.Mapping{
+		{Err: errNotFound, Type: problem.Type{Status: http.StatusNotFound, Code: "thing-not-found", Title: "Thing not found"}},
+		{Err: errInvalid, Type: problem.Type{Status: http.StatusBadRequest, Code: "malformed-thing", Title: "Malformed thing"}},
+		{Err: errTooMany, Type: problem.Type{Status: http.StatusTooManyRequests, Code: "too-many-things", Title: "Too many"}},
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expose   bool
+		method   string
+		want     problem.Details
+		wantBody bool
+		// wantRetryAfter is the expected Retry-After header, empty if there must be none
+		wantRetryAfter string
+	}{
+		{
+			name: "mapped error",
+			err:  errors.WithStack(errors.Wrap(errors.Wrap(errNotFound, "thing 42"), "thing use case error")),
+			want: problem.Details{
+				Type: "urn:test:thing-not-found", Title: "Thing not found", Status: http.StatusNotFound,
+				Detail: "thing not found", Code: "thing-not-found",
+			},
+			wantBody: true,
+		},
+		{
+			name: "detailed mapped error",
+			err:  errors.Wrap(problem.Detailed(errNotFound, "thing %d", 42), "thing use case error"),
+			want: problem.Details{
+				Type: "urn:test:thing-not-found", Title: "Thing not found", Status: http.StatusNotFound,
+				Detail: "thing 42: thing not found", Code: "thing-not-found",
+			},
+			wantBody: true,
+		},
+		{
+			name: "retry after is rounded up to seconds",
+			err:  errors.Wrap(problem.RetryAfter(errTooMany, 1500*time.Millisecond), "thing use case error"),
+			want: problem.Details{
+				Type: "urn:test:too-many-things", Title: "Too many", Status: http.StatusTooManyRequests,
+				Detail: "too many things", Code: "too-many-things",
+			},
+			wantBody:       true,
+			wantRetryAfter: "2",
+		},
+		{
+			name: "mapped validation error is reported as built for the client",
+			err:  errors.WithStack(problem.Invalid("id", errors.Wrap(errors.Wrap(errInvalid, "not a number"), "id 4x2"))),
+			want: problem.Details{
+				Type: "urn:test:malformed-thing", Title: "Malformed thing", Status: http.StatusBadRequest,
+				Detail: "id 4x2: not a number: malformed thing", Code: "malformed-thing",
+				Errors: []problem.FieldError{{Field: "id", Message: "id 4x2: not a number: malformed thing"}},
+			},
+			wantBody: true,
+		},
+		{
+			name: "mapped validation error",
+			err:  problem.Invalid("id", errInvalid),
+			want: problem.Details{
+				Type: "urn:test:malformed-thing", Title: "Malformed thing", Status: http.StatusBadRequest,
+				Detail: "malformed thing", Code: "malformed-thing",
+				Errors: []problem.FieldError{{Field: "id", Message: "malformed thing"}},
+			},
+			wantBody: true,
+		},
+		{
+			name: "unmapped validation error",
+			err: &problem.ValidationError{
+				Err:    errors.New("invalid body"),
+				Fields: []problem.FieldError{{Field: "/title", Message: "required"}},
+			},
+			want: problem.Details{
+				Type: problem.BlankType, Title: "Bad Request", Status: http.StatusBadRequest, Detail: "invalid body",
+				Code: "bad-request", Errors: []problem.FieldError{{Field: "/title", Message: "required"}},
+			},
+			wantBody: true,
+		},
+		{
+			name: "HTTP error",
+			err:  echo.NewHTTPError(http.StatusUnauthorized, "missing key"),
+			want: problem.Details{
+				Type: problem.BlankType, Title: "Unauthorized", Status: http.StatusUnauthorized, Detail: "missing key",
+				Code: "unauthorized",
+			},
+			wantBody: true,
+		},
+		{
+			name: "internal error is hidden",
+			err:  errors.New("database password is hunter2"),
+			want: problem.Details{
+				Type: problem.BlankType, Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Code: "internal-server-error",
+			},
+			wantBody: true,
+		},
+		{
+			name: "internal HTTP error is hidden",
+			err:  echo.NewHTTPError(http.StatusInternalServerError, "database password is hunter2"),
+			want: problem.Details{
+				Type: problem.BlankType, Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Code: "internal-server-error",
+			},
+			wantBody: true,
+		},
+		{
+			name:   "internal error is exposed",
+			err:    errors.New("database unavailable"),
+			expose: true,
+			want: problem.Details{
+				Type: problem.BlankType, Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Detail: "database unavailable", Code: "internal-server-error",
+			},
+			wantBody: true,
+		},
+		{
+			name:   "HEAD request",
+			err:    errNotFound,
+			method: http.MethodHead,
+			want:   problem.Details{Status: http.StatusNotFound},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.HTTPErrorHandler = problem.NewErrorHandler(problem.Config{
+				TypeURIPrefix:        "urn:test:",
+				Mappings:             mappings,
+				ExposeInternalErrors: tc.expose,
+			})
+			e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+				Generator: func() string { return "request-1" },
+			}))
+			e.Any("/things/:id", func(echo.Context) error { return tc.err })
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, httptest.NewRequest(method, "/things/42", nil))
+
+			assert.Equal(t, tc.want.Status, recorder.Code)
+			assert.Equal(t, tc.wantRetryAfter, recorder.Header().Get("Retry-After"))
+
+			if !tc.wantBody {
+				assert.Empty(t, recorder.Body.String())
+
+				return
+			}
+
+			assert.Equal(t, problem.MIMEType, recorder.Header().Get(echo.HeaderContentType))
+
+			var got problem.Details
+
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+
+			tc.want.Instance = "/things/42"
+			tc.want.RequestID = "request-1"
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewErrorHandler_UnknownRoute(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(problem.Config{})
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.JSONEq(t, `{
+		"type": "about:blank", "title": "Not Found", "status": 404, "detail": "Not Found", "instance": "/nowhere",
+		"code": "not-found"
+	}`, recorder.Body.String())
+}
@@ -0,0 +1,134 @@
+// Package problem reports errors as RFC 7807 problem details (application/problem+json).
+//
+// Domain errors are mapped to problem types by errors.Is, so that handlers can return the errors of their use cases
+// as they are; everything that is neither mapped nor an echo.HTTPError is an internal error.
+package problem
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	MIMEType = "application/problem+json"
+
+	// BlankType is the problem type of problems that mean no more than their HTTP status.
+	BlankType = "about:blank"
+)
+
+// Details is the problem details object, extended by a stable error code, the request ID and field-level details.
+type Details struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail explains this occurrence, it is left out for internal errors unless they are exposed
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code identifies the problem type, for the blank type it is derived from the status, e.g. not-found
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError locates a violation in the request, by parameter name or JSON pointer into the body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Type is a kind of problem, its URI is the code appended to the configured type URI prefix.
+type Type struct {
+	Status int
+	Code   string
+	Title  string
+}
+
+// Mapping reports errors matching Err as problems of Type.
+type Mapping struct {
+	Err  error
+	Type Type
+}
+
+// ValidationError adds field-level details to an error, errors that are not mapped otherwise are bad requests.
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Invalid reports err for a single field, typically a path or query parameter.
+func Invalid(field string, err error) error {
+	return &ValidationError{Err: err, Fields: []FieldError{{Field: field, Message: err.Error()}}}
+}
+
+// detailedError is a message for clients about the error it wraps.
+type detailedError struct {
+	message string
+	err     error
+}
+
+func (e *detailedError) Error() string {
+	return e.message
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.err
+}
+
+// Detailed wraps err with context meant for clients, like errors.Wrapf does; a mapped error is reported with the
+// message of the outermost Detailed error, whereas other wraps are only logged.
+func Detailed(err error, format string, args ...any) error {
+	return errors.WithStack(&detailedError{message: fmt.Sprintf(format, args...) + ": " + err.Error(), err: err})
+}
+
+// retryError tells clients when the request failing with the error it wraps may succeed.
+type retryError struct {
+	after time.Duration
+	err   error
+}
+
+func (e *retryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter wraps err with the time after which the request may succeed, it is reported in the Retry-After header.
+func RetryAfter(err error, after time.Duration) error {
+	return errors.WithStack(&retryError{after: after, err: err})
+}
+
+// ClientMessage returns the message of err built for clients, that of a ValidationError or the outermost Detailed
+// error, or fallback if there is none; the rest of the wrap chain may reveal internals such as
+// "valuation use case error: job not found" and is meant for the logs.
+func ClientMessage(err error, fallback string) string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Error()
+	}
+
+	var detailedErr *detailedError
+	if errors.As(err, &detailedErr) {
+		return detailedErr.Error()
+	}
+
+	return fallback
+}
+
+// statusCode derives a code from an HTTP status, e.g. not-found for 404.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "-")
+}
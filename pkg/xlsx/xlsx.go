@@ -0,0 +1,231 @@
+// Package xlsx streams a single worksheet as an Office Open XML workbook, rows are written as they come without
+// keeping them in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrClosed = errors.New("workbook is closed")
+
+const MIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+const (
+	contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	rootRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+		`Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	workbookRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" ` +
+		`Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	workbookStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="`
+	workbookEnd = `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	sheetStart  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetEnd = `</sheetData></worksheet>`
+)
+
+// Writer writes the rows of the only worksheet of a workbook.
+type Writer struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+	closed  bool
+}
+
+// NewWriter starts a workbook with a single worksheet of the given name, which must not be longer than 31
+// characters. The workbook is complete once the writer is closed.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	archive := zip.NewWriter(w)
+
+	var name []byte
+	if err := xml.EscapeText(sliceWriter{&name}, []byte(sheetName)); err != nil {
+		return nil, errors.Wrap(err, "cannot escape sheet name")
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: contentTypes},
+		{name: "_rels/.rels", content: rootRelationships},
+		{name: "xl/workbook.xml", content: workbookStart + string(name) + workbookEnd},
+		{name: "xl/_rels/workbook.xml.rels", content: workbookRelationships},
+	}
+
+	for _, part := range parts {
+		if err := writePart(archive, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// the worksheet is the last part, so that its rows can be streamed into the archive
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create worksheet")
+	}
+
+	if _, err := io.WriteString(sheet, sheetStart); err != nil {
+		return nil, errors.Wrap(err, "cannot write worksheet")
+	}
+
+	return &Writer{archive: archive, sheet: sheet}, nil
+}
+
+func writePart(archive *zip.Writer, name string, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return errors.Wrapf(err, "cannot create %s", name)
+	}
+
+	if _, err := io.WriteString(part, content); err != nil {
+		return errors.Wrapf(err, "cannot write %s", name)
+	}
+
+	return nil
+}
+
+// WriteRow appends a row. Integers and floats become numeric cells, booleans boolean cells, times are written in
+// RFC 3339 and everything else as text; nil leaves the cell empty.
+func (w *Writer) WriteRow(values []any) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.rows++
+
+	row := make([]byte, 0, 64*len(values)) //nolint:gomnd // rough size of a cell
+	row = append(row, `<row r="`...)
+	row = strconv.AppendInt(row, int64(w.rows), 10)
+	row = append(row, `">`...)
+
+	for i, value := range values {
+		row = appendCell(row, cellReference(i, w.rows), value)
+	}
+
+	row = append(row, `</row>`...)
+
+	if _, err := w.sheet.Write(row); err != nil {
+		return errors.Wrap(err, "cannot write row")
+	}
+
+	return nil
+}
+
+// Flush writes buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return errors.Wrap(w.archive.Flush(), "cannot flush workbook")
+}
+
+// Close completes the workbook, it does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	if _, err := io.WriteString(w.sheet, sheetEnd); err != nil {
+		return errors.Wrap(err, "cannot write worksheet")
+	}
+
+	return errors.Wrap(w.archive.Close(), "cannot close workbook")
+}
+
+func appendCell(row []byte, reference string, value any) []byte {
+	switch v := value.(type) {
+	case nil:
+		return row
+	case int:
+		return appendNumber(row, reference, strconv.Itoa(v))
+	case int64:
+		return appendNumber(row, reference, strconv.FormatInt(v, 10))
+	case float64:
+		return appendNumber(row, reference, strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		bit := "0"
+		if v {
+			bit = "1"
+		}
+
+		row = append(row, `<c r="`+reference+`" t="b"><v>`+bit+`</v></c>`...)
+
+		return row
+	case time.Time:
+		return appendText(row, reference, v.Format(time.RFC3339))
+	case string:
+		return appendText(row, reference, v)
+	default:
+		return appendText(row, reference, toString(v))
+	}
+}
+
+func appendNumber(row []byte, reference string, number string) []byte {
+	return append(row, `<c r="`+reference+`"><v>`+number+`</v></c>`...)
+}
+
+func appendText(row []byte, reference string, text string) []byte {
+	if text == "" {
+		return row
+	}
+
+	row = append(row, `<c r="`+reference+`" t="inlineStr"><is><t xml:space="preserve">`...)
+	// EscapeText only fails if the writer does
+	_ = xml.EscapeText(sliceWriter{&row}, []byte(text))
+
+	return append(row, `</t></is></c>`...)
+}
+
+func toString(value any) string {
+	if stringer, ok := value.(interface{ String() string }); ok {
+		return stringer.String()
+	}
+
+	return ""
+}
+
+// cellReference returns the A1 reference of the zero-based column in the one-based row, e.g. AA3.
+func cellReference(column int, row int) string {
+	var letters []byte
+
+	for column >= 0 {
+		letters = append([]byte{byte('A' + column%26)}, letters...) //nolint:gomnd // letters of the alphabet
+		column = column/26 - 1                                      //nolint:gomnd // letters of the alphabet
+	}
+
+	return string(letters) + strconv.Itoa(row)
+}
+
+// sliceWriter appends to a byte slice.
+type sliceWriter struct {
+	buffer *[]byte
+}
+
+func (s sliceWriter) Write(p []byte) (int, error) {
+	*s.buffer = append(*s.buffer, p...)
+
+	return len(p), nil
+}
@@ -0,0 +1,111 @@
+//nolint:funlen // Test functions are long, due to test cases
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MyChaOS87/patAi/pkg/xlsx"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		rows  [][]any
+		sheet string
+	}{
+		{
+			name:  "no rows",
+			sheet: "<sheetData></sheetData>",
+		},
+		{
+			name: "cell types",
+			rows: [][]any{
+				{"id", "value", "cached", "createdAt"},
+				{"a <b> & c", 42, true, time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)},
+				{"", 1.5, false, nil},
+			},
+			sheet: `<sheetData><row r="1">` +
+				`<c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>` +
+				`<c r="B1" t="inlineStr"><is><t xml:space="preserve">value</t></is></c>` +
+				`<c r="C1" t="inlineStr"><is><t xml:space="preserve">cached</t></is></c>` +
+				`<c r="D1" t="inlineStr"><is><t xml:space="preserve">createdAt</t></is></c>` +
+				`</row><row r="2">` +
+				`<c r="A2" t="inlineStr"><is><t xml:space="preserve">a &lt;b&gt; &amp; c</t></is></c>` +
+				`<c r="B2"><v>42</v></c>` +
+				`<c r="C2" t="b"><v>1</v></c>` +
+				`<c r="D2" t="inlineStr"><is><t xml:space="preserve">2024-08-15T12:00:00Z</t></is></c>` +
+				`</row><row r="3">` +
+				`<c r="B3"><v>1.5</v></c>` +
+				`<c r="C3" t="b"><v>0</v></c>` +
+				`</row></sheetData>`,
+		},
+		{
+			name:  "columns beyond Z",
+			rows:  [][]any{append(make([]any, 27), 1)},
+			sheet: `<sheetData><row r="1"><c r="AB1"><v>1</v></c></row></sheetData>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buffer bytes.Buffer
+
+			writer, err := xlsx.NewWriter(&buffer, "Patents & more")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, row := range tc.rows {
+				assert.NoError(t, writer.WriteRow(row))
+			}
+
+			assert.NoError(t, writer.Close())
+			assert.ErrorIs(t, writer.WriteRow([]any{"late"}), xlsx.ErrClosed)
+
+			archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			parts := map[string]string{}
+
+			for _, file := range archive.File {
+				reader, err := file.Open()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				content, err := io.ReadAll(reader)
+				assert.NoError(t, err)
+
+				parts[file.Name] = string(content)
+
+				// every part must be well-formed XML
+				decoder := xml.NewDecoder(bytes.NewReader(content))
+				for err == nil {
+					_, err = decoder.Token()
+				}
+
+				assert.ErrorIs(t, err, io.EOF, file.Name)
+			}
+
+			assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Patents &amp; more"`)
+			assert.Contains(t, parts, "[Content_Types].xml")
+			assert.Contains(t, parts, "_rels/.rels")
+			assert.Contains(t, parts, "xl/_rels/workbook.xml.rels")
+			assert.Contains(t, parts["xl/worksheets/sheet1.xml"], tc.sheet)
+		})
+	}
+}